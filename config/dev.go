@@ -7,6 +7,8 @@
 // 没有k8s 这个编译标签
 package config
 
+import "time"
+
 // 本地连接
 var Config = config{
 	DB: DBConfig{
@@ -16,4 +18,69 @@ var Config = config{
 	Redis: RedisConfig{
 		Addr: "localhost:30033",
 	},
+	// 本地不配置密钥，启动时临时生成一把
+	JWT: JWTConfig{
+		// 本地没有redis的话可以换成SessionStoreMemory，限流、验证码、登录失败次数这些也会改为放在内存里
+		SessionStore: SessionStoreRedis,
+		Algorithm:    "EdDSA",
+		MaxKeys:      3,
+
+		AccessExpiration:   time.Minute * 30,
+		RefreshExpiration:  time.Hour * 24 * 7,
+		RenewWindow:        time.Minute * 10,
+		MaxSessionLifetime: time.Hour * 24 * 30,
+
+		RevocationLag:     time.Minute * 5,
+		RevocationCacheMB: 64,
+	},
+	// 本地不发邮件，验证码打印在控制台
+	Email: EmailConfig{},
+	Account: AccountConfig{
+		DeletionGracePeriod:  time.Hour * 24,
+		PurgeInterval:        time.Minute * 10,
+		HandleChangeCooldown: time.Minute,
+		StatusCacheTTL:       time.Second * 5,
+	},
+	Export: ExportConfig{
+		Dir:              "./data/exports",
+		Retention:        time.Hour * 24,
+		LinkExpiration:   time.Minute * 15,
+		CleanupInterval:  time.Minute * 10,
+		EphemeralSignKey: true,
+	},
+	Login: LoginGuardConfig{
+		Window:         time.Hour,
+		DelayAfter:     3,
+		BaseDelay:      time.Second,
+		MaxDelay:       time.Minute,
+		LockAfter:      10,
+		LockDuration:   time.Minute * 15,
+		CaptchaAfter:   3,
+		IpCaptchaAfter: 10,
+		IpBlockAfter:   100,
+	},
+	Captcha: CaptchaConfig{
+		Provider: CaptchaLocal,
+	},
+	Password: PasswordConfig{
+		Algorithm:      PasswordArgon2id,
+		BcryptCost:     10,
+		Argon2Memory:   19 * 1024,
+		Argon2Time:     2,
+		Argon2Threads:  1,
+		MaxConcurrency: 4,
+	},
+	Storage: StorageConfig{
+		Type:      StorageLocal,
+		PublicURL: "http://localhost:8080/static",
+		LocalDir:  "./data/storage",
+	},
+	Avatar: AvatarConfig{
+		MaxSize:      5 << 20,
+		MaxDimension: 4096,
+	},
+	LoginHistory: LoginHistoryConfig{
+		Retention:     time.Hour * 24 * 30,
+		PruneInterval: time.Hour,
+	},
 }
@@ -3,7 +3,85 @@
 // 带有k8s的tag时，才编译
 package config
 
+import "time"
+
 var Config = config{
 	DB:    DBConfig{DSN: "root:root@tcp(webook-mysql:11309)/webook"},
 	Redis: RedisConfig{Addr: "webook-redis:6379"},
+	// 多个pod必须共用同一套密钥，通过secret挂载进来
+	JWT: JWTConfig{
+		// 多实例必须用redis共享会话状态
+		SessionStore: SessionStoreRedis,
+		Algorithm:    "EdDSA",
+		Keys: []JWTKeyConfig{
+			{Kid: "webook-jwt-1", PrivateKeyFile: "/etc/webook/jwt/webook-jwt-1.pem"},
+		},
+		MaxKeys: 3,
+
+		AccessExpiration:   time.Minute * 30,
+		RefreshExpiration:  time.Hour * 24 * 7,
+		RenewWindow:        time.Minute * 10,
+		MaxSessionLifetime: time.Hour * 24 * 30,
+
+		RevocationLag:     time.Minute * 5,
+		RevocationCacheMB: 64,
+	},
+	Email: EmailConfig{
+		Host:     "smtp.exmail.qq.com",
+		Port:     587,
+		Username: "noreply@webook.com",
+		From:     "webook <noreply@webook.com>",
+	},
+	Account: AccountConfig{
+		DeletionGracePeriod:  time.Hour * 24 * 30,
+		PurgeInterval:        time.Hour,
+		HandleChangeCooldown: time.Hour * 24 * 30,
+		StatusCacheTTL:       time.Second * 5,
+	},
+	// 挂载的共享卷
+	Export: ExportConfig{
+		Dir:             "/var/lib/webook/exports",
+		Retention:       time.Hour * 24 * 7,
+		LinkExpiration:  time.Minute * 15,
+		CleanupInterval: time.Hour,
+	},
+	Login: LoginGuardConfig{
+		Window:         time.Hour,
+		DelayAfter:     3,
+		BaseDelay:      time.Second,
+		MaxDelay:       time.Minute,
+		LockAfter:      10,
+		LockDuration:   time.Minute * 15,
+		CaptchaAfter:   3,
+		IpCaptchaAfter: 10,
+		IpBlockAfter:   100,
+	},
+	Captcha: CaptchaConfig{
+		Provider:  CaptchaSiteVerify,
+		VerifyURL: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	},
+	Password: PasswordConfig{
+		Algorithm:     PasswordArgon2id,
+		BcryptCost:    10,
+		Argon2Memory:  64 * 1024,
+		Argon2Time:    3,
+		Argon2Threads: 2,
+		// 最多占用 8 * 64MiB = 512MiB，和pod的内存限制对应
+		MaxConcurrency: 8,
+	},
+	Storage: StorageConfig{
+		Type:      StorageS3,
+		PublicURL: "https://static.webook.com",
+		Endpoint:  "http://minio:9000",
+		Bucket:    "webook",
+		Region:    "us-east-1",
+	},
+	Avatar: AvatarConfig{
+		MaxSize:      5 << 20,
+		MaxDimension: 4096,
+	},
+	LoginHistory: LoginHistoryConfig{
+		Retention:     time.Hour * 24 * 180,
+		PruneInterval: time.Hour,
+	},
 }
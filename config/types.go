@@ -1,8 +1,21 @@
 package config
 
+import "time"
+
 type config struct {
-	DB    DBConfig
-	Redis RedisConfig
+	DB       DBConfig
+	Redis    RedisConfig
+	JWT      JWTConfig
+	Email    EmailConfig
+	Account  AccountConfig
+	Export   ExportConfig
+	Login    LoginGuardConfig
+	Captcha  CaptchaConfig
+	Password PasswordConfig
+	Storage  StorageConfig
+	Avatar   AvatarConfig
+	// 登录历史
+	LoginHistory LoginHistoryConfig
 }
 
 type DBConfig struct {
@@ -12,3 +25,164 @@ type DBConfig struct {
 type RedisConfig struct {
 	Addr string
 }
+
+// 会话状态的存储方式
+const (
+	SessionStoreRedis  = "redis"
+	SessionStoreMemory = "memory"
+)
+
+type JWTConfig struct {
+	// 会话状态存在哪里，默认redis；memory只适合单机部署和本地开发，这时其他用到redis的缓存也都放在内存里
+	SessionStore string
+	// 签名算法：RS256 或 EdDSA
+	Algorithm string
+	// 第一个是签名用的主密钥，其余的只用于校验，轮换时把新密钥放到最前面
+	Keys []JWTKeyConfig
+	// 最多保留多少把校验密钥
+	MaxKeys int
+
+	// 短token有效期
+	AccessExpiration time.Duration
+	// 长token有效期
+	RefreshExpiration time.Duration
+	// 短token剩余有效期小于这个值时，中间件自动续期，0表示不续期
+	RenewWindow time.Duration
+	// 从登录开始算，会话最长能续多久
+	MaxSessionLifetime time.Duration
+
+	// redis挂了之后，本地退出登录缓存最多允许落后多久，超过就拒绝请求
+	RevocationLag time.Duration
+	// 本地退出登录缓存的内存上限，单位MB
+	RevocationCacheMB int
+}
+
+// 发邮件用的SMTP服务器，密码通过环境变量SMTP_PASSWORD注入
+type EmailConfig struct {
+	// 为空时不真正发送，只打印到控制台
+	Host     string
+	Port     int
+	Username string
+	From     string
+}
+
+// 账号生命周期
+type AccountConfig struct {
+	// 申请注销之后的恢复期，期间登录可以恢复账号
+	DeletionGracePeriod time.Duration
+	// 多久检查一次过了恢复期的账号
+	PurgeInterval time.Duration
+	// 两次修改用户名之间至少隔多久
+	HandleChangeCooldown time.Duration
+	// 账号状态在本地缓存多久，也就是封禁生效的最大延迟
+	StatusCacheTTL time.Duration
+}
+
+// 个人数据导出，下载地址的签名密钥通过环境变量EXPORT_SIGN_KEY注入
+type ExportConfig struct {
+	// 归档文件存放的目录，多实例部署时必须是共享存储
+	Dir string
+	// 归档保留多久
+	Retention time.Duration
+	// 下载地址多久过期
+	LinkExpiration time.Duration
+	// 多久清理一次过期的归档
+	CleanupInterval time.Duration
+	// 没有配置EXPORT_SIGN_KEY时允许临时生成一把，重启或者多实例时链接会失效，只适合本地开发
+	EphemeralSignKey bool
+}
+
+// 密码登录防暴力破解，次数都是统计窗口内的失败次数
+type LoginGuardConfig struct {
+	// 失败次数的统计窗口
+	Window time.Duration
+	// 失败这么多次之后每次失败都要等一会，每多失败一次翻倍，最多MaxDelay
+	DelayAfter int64
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// 失败这么多次之后锁定账号
+	LockAfter    int64
+	LockDuration time.Duration
+	// 账号、ip失败这么多次之后需要人机验证
+	CaptchaAfter   int64
+	IpCaptchaAfter int64
+	// ip失败这么多次之后直接拒绝
+	IpBlockAfter int64
+}
+
+// 人机验证服务
+const (
+	// 只要带了票据就通过，只能用于本地开发
+	CaptchaLocal = "local"
+	// reCAPTCHA、hCaptcha、Turnstile通用的siteverify协议
+	CaptchaSiteVerify = "siteverify"
+)
+
+// 密码登录失败多了之后的人机验证，siteverify的密钥通过环境变量CAPTCHA_SECRET注入
+type CaptchaConfig struct {
+	// local 或 siteverify，不配置启动失败
+	Provider string
+	// siteverify的校验地址
+	VerifyURL string
+}
+
+// 新密码用的哈希算法
+const (
+	PasswordBcrypt   = "bcrypt"
+	PasswordArgon2id = "argon2id"
+)
+
+// 密码哈希，老的hash在用户登录成功时自动升级到当前的算法和参数
+type PasswordConfig struct {
+	// bcrypt 或 argon2id，默认bcrypt
+	Algorithm  string
+	BcryptCost int
+	// argon2id的内存，单位KiB
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+	// 同时最多算几个hash，argon2id占用的内存是这个数乘以Argon2Memory，0表示不限制
+	MaxConcurrency int
+}
+
+// 对象存储的类型
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// 头像等文件的存储，S3的密钥通过环境变量S3_ACCESS_KEY_ID、S3_SECRET_ACCESS_KEY注入
+type StorageConfig struct {
+	// local 或 s3，默认local
+	Type string
+	// 对外访问的前缀，local时是web服务器的/static，s3时一般是CDN
+	PublicURL string
+	// local：文件存放的目录，多实例部署时必须是共享存储
+	LocalDir string
+	// s3：兼容S3的服务地址，比如 https://s3.us-east-1.amazonaws.com
+	Endpoint string
+	Bucket   string
+	Region   string
+}
+
+// 头像上传限制
+type AvatarConfig struct {
+	// 文件大小，字节
+	MaxSize int64
+	// 宽高的上限
+	MaxDimension int
+}
+
+// 登录历史的保留策略
+type LoginHistoryConfig struct {
+	// 登录记录保留多久，0表示一直保留
+	Retention time.Duration
+	// 多久清理一次过期的记录
+	PruneInterval time.Duration
+}
+
+type JWTKeyConfig struct {
+	Kid string
+	// PEM格式的私钥文件
+	PrivateKeyFile string
+}
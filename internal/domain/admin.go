@@ -0,0 +1,52 @@
+package domain
+
+// 管理后台查询用户的条件，零值的条件不生效
+type UserFilter struct {
+	// 前缀匹配
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+	// 注册时间范围，毫秒，左闭右开
+	CreatedFrom int64 `json:"createdFrom"`
+	CreatedTo   int64 `json:"createdTo"`
+}
+
+// 管理员的操作类型
+const (
+	AuditActionUserSearch         = "user_search"
+	AuditActionUserView           = "user_view"
+	AuditActionUserBan            = "user_ban"
+	AuditActionUserUnban          = "user_unban"
+	AuditActionUserLogout         = "user_logout"
+	AuditActionUserResetPassword  = "user_reset_password"
+	AuditActionUserResetTwoFactor = "user_reset_two_factor"
+)
+
+// 操作的结果，先记pending再执行，执行完回填
+const (
+	AuditResultPending = "pending"
+	AuditResultSuccess = "success"
+	AuditResultFailed  = "failed"
+)
+
+// 执行操作的管理员
+type AuditOperator struct {
+	UserId    int64
+	Ip        string
+	UserAgent string
+}
+
+// 管理员操作的审计日志，除了回填结果不修改
+type AuditLog struct {
+	Id         int64  `json:"id"`
+	OperatorId int64  `json:"operatorId"`
+	Action     string `json:"action"`
+	// 被操作的用户，查询用户列表的时候为0
+	TargetId  int64  `json:"targetId,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	Ip        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Result    string `json:"result"`
+	// 失败的原因
+	Error     string `json:"error,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+}
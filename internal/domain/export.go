@@ -0,0 +1,19 @@
+package domain
+
+// 个人数据导出的状态
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+// 一次个人数据导出，归档文件生成好之后在ExpireAt之前可以下载
+type DataExport struct {
+	Id     int64  `json:"id"`
+	UserId int64  `json:"-"`
+	Status string `json:"status"`
+	// 归档文件在服务器上的路径
+	File     string `json:"-"`
+	ExpireAt int64  `json:"expireAt,omitempty"`
+	CreateAt int64  `json:"createdAt"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+// 关注关系，Follower关注了Followee
+type FollowRelation struct {
+	// 分页游标
+	Id        int64 `json:"id"`
+	Follower  int64 `json:"follower"`
+	Followee  int64 `json:"followee"`
+	CreatedAt int64 `json:"createdAt"`
+}
+
+// 粉丝数和关注数
+type FollowStatistic struct {
+	Followers int64 `json:"followers"`
+	Followees int64 `json:"followees"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+// 登录方式的提供方
+const (
+	IdentityEmail  = "email"
+	IdentityPhone  = "phone"
+	IdentityWeChat = "wechat"
+)
+
+// 账号绑定的一种登录方式
+type Identity struct {
+	Id       int64  `json:"id"`
+	UserId   int64  `json:"-"`
+	Provider string `json:"provider"`
+	// 邮箱、手机号、微信openId
+	Subject string `json:"subject"`
+	// 0表示还没验证
+	VerifiedAt int64 `json:"verifiedAt"`
+	CreateAt   int64 `json:"createdAt"`
+}
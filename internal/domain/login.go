@@ -0,0 +1,23 @@
+package domain
+
+// 密码登录的失败次数和锁定状态，管理员排查用
+type LoginLockState struct {
+	Account string `json:"account"`
+	// 统计窗口内的失败次数
+	Failures int64 `json:"failures"`
+	// 锁定到什么时候，0表示没有锁定
+	LockedUntil int64 `json:"lockedUntil,omitempty"`
+}
+
+// 密码登录前占用的一次尝试，先按失败计数，并发的请求不能一起越过阈值
+// 密码正确时归还
+type LoginAttempt struct {
+	Account string
+	Ip      string
+	// 这次之前统计窗口内的失败次数
+	AccountFailures int64
+	IpFailures      int64
+	// 先按失败加上的锁，归还时一起去掉，0表示没有加锁
+	// 账号已经被锁定时是已有的锁
+	LockedUntil int64
+}
@@ -0,0 +1,62 @@
+package domain
+
+// 登录方式
+const (
+	LoginMethodPassword = "password"
+	LoginMethodSms      = "sms"
+	LoginMethodWeChat   = "wechat"
+	// 两步验证的第二步
+	LoginMethodTwoFactor = "two_factor"
+	// 用refresh token换新的token
+	LoginMethodRefresh = "refresh"
+)
+
+// 登录结果
+const (
+	LoginResultSuccess = "success"
+	// 第一步通过，等待两步验证
+	LoginResultTwoFactorRequired = "two_factor_required"
+	// 密码、验证码错误
+	LoginResultWrongCredentials = "wrong_credentials"
+	// 失败次数太多被锁定，或者需要人机验证
+	LoginResultLocked  = "locked"
+	LoginResultBanned  = "banned"
+	LoginResultDeleted = "deleted"
+	// refresh token、登录凭证无效或者已经退出
+	LoginResultInvalidToken = "invalid_token"
+	// refresh token被重复使用
+	LoginResultTokenReused = "token_reused"
+	LoginResultError       = "error"
+)
+
+// 一次登录尝试，只追加不修改
+type LoginRecord struct {
+	Id int64 `json:"id"`
+	// 登录失败、找不到用户时为0
+	UserId int64  `json:"userId,omitempty"`
+	Method string `json:"method"`
+	Result string `json:"result"`
+	// 登录时填的邮箱、手机号
+	Account   string `json:"account,omitempty"`
+	Ip        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	// 登录成功才有
+	Ssid      string `json:"ssid,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+func (r LoginRecord) Succeeded() bool {
+	return r.Result == LoginResultSuccess
+}
+
+// 管理后台查询登录记录的条件，零值的条件不生效
+type LoginRecordFilter struct {
+	UserId  int64  `json:"userId"`
+	Account string `json:"account"`
+	Ip      string `json:"ip"`
+	Method  string `json:"method"`
+	Result  string `json:"result"`
+	// 时间范围，毫秒，左闭右开
+	CreatedFrom int64 `json:"createdFrom"`
+	CreatedTo   int64 `json:"createdTo"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+// 账号合并完成的事件，其他模块据此把外键从SourceId改成TargetId
+type UserMergedEvent struct {
+	// 被合并掉、只剩墓碑的用户
+	SourceId int64 `json:"sourceId"`
+	// 保留下来的用户
+	TargetId int64 `json:"targetId"`
+	MergedAt int64 `json:"mergedAt"`
+}
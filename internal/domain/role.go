@@ -0,0 +1,51 @@
+package domain
+
+// 权限点，格式为 资源:动作
+const (
+	// 拥有所有权限
+	PermissionAll = "*"
+	// 给用户分配、收回角色
+	PermissionRoleManage = "role:manage"
+	// 管理后台查询、查看用户
+	PermissionUserView = "user:view"
+	// 封禁用户
+	PermissionUserBan = "user:ban"
+	// 强制下线、重置密码和两步验证
+	PermissionUserManage = "user:manage"
+	// 查看管理员操作的审计日志
+	PermissionAuditView = "audit:view"
+	// 查看、解除密码登录的锁定
+	PermissionLoginLockManage = "login_lock:manage"
+	// 查看/debug/vars里的运行指标，里面有进程的启动参数
+	PermissionDebugView = "debug:view"
+	// 管理后台查询所有用户的登录记录
+	PermissionLoginHistoryView = "login_history:view"
+)
+
+// 内置的角色
+const (
+	RoleAdmin = "admin"
+)
+
+type Role struct {
+	Id          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// 用户拥有的角色和权限
+type UserAuthz struct {
+	UserId      int64    `json:"userId"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+func (a UserAuthz) HasPermission(permission string) bool {
+	for _, p := range a.Permissions {
+		if p == PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
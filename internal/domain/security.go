@@ -0,0 +1,31 @@
+package domain
+
+// 安全事件类型
+const (
+	// refresh token被重复使用，整个token family被吊销
+	SecurityEventRefreshTokenReuse = "refresh_token_reuse"
+	// 通过邮箱验证码重置了密码
+	SecurityEventPasswordReset = "password_reset"
+	// 登录状态下修改了密码
+	SecurityEventPasswordChange = "password_change"
+	// 把另一个账号合并了进来
+	SecurityEventAccountMerge = "account_merge"
+	// 申请注销账号
+	SecurityEventAccountDelete = "account_delete"
+	// 开启两步验证
+	SecurityEventTwoFactorEnable = "two_factor_enable"
+	// 关闭两步验证
+	SecurityEventTwoFactorDisable = "two_factor_disable"
+)
+
+// 需要留痕的安全事件
+type SecurityEvent struct {
+	Id        int64  `json:"id"`
+	UserId    int64  `json:"userId"`
+	Type      string `json:"type"`
+	Ssid      string `json:"ssid,omitempty"`
+	Ip        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	CreateAt  int64  `json:"createdAt"`
+}
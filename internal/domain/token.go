@@ -0,0 +1,38 @@
+package domain
+
+// 个人访问令牌的前缀，中间件靠它区分令牌和jwt
+const PersonalTokenPrefix = "wpat_"
+
+// 个人访问令牌能申请的授权范围
+const (
+	// 查看个人资料
+	ScopeProfileRead = "profile:read"
+	// 修改个人资料
+	ScopeProfileWrite = "profile:write"
+)
+
+// 所有允许申请的scope，令牌管理本身不在里面，令牌不能用来创建令牌
+var PersonalTokenScopes = []string{ScopeProfileRead, ScopeProfileWrite}
+
+// 给脚本、CI用的个人访问令牌，明文只在创建时返回一次
+type PersonalToken struct {
+	Id     int64    `json:"id"`
+	UserId int64    `json:"-"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// 明文的前几位，方便用户区分是哪个令牌
+	Prefix     string `json:"prefix"`
+	LastUsedAt int64  `json:"lastUsedAt"`
+	// 0表示永不过期
+	ExpireAt int64 `json:"expireAt"`
+	CreateAt int64 `json:"createdAt"`
+}
+
+func (t PersonalToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
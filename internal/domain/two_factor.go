@@ -0,0 +1,21 @@
+package domain
+
+// 两步验证的状态，密钥不返回给前端
+type TwoFactor struct {
+	UserId int64  `json:"-"`
+	Secret string `json:"-"`
+	// 0表示还在绑定中，没有确认
+	EnabledAt int64 `json:"enabledAt"`
+	// 最后一次使用的TOTP时间片，同一个验证码不能用两次
+	LastUsedStep int64 `json:"-"`
+}
+
+func (t TwoFactor) Enabled() bool {
+	return t.EnabledAt > 0
+}
+
+// 开始绑定时返回给前端的数据，URI直接生成二维码给认证器扫
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
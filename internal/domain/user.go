@@ -12,4 +12,88 @@ type User struct {
 	CreatetAt     int64  `json:"createdAt"`
 	WeChatOpenId  string `json:"weChatOpenId"`
 	WeChatUnionId string `json:"weChatUnionId"`
+	// 公开主页的用户名，没设置过为空
+	Handle string `json:"handle,omitempty"`
+	// 邮箱是否已经验证，邮箱注册的账号验证前有些操作不能做
+	EmailVerified bool `json:"emailVerified"`
+	// 头像各个尺寸的访问地址，没有上传过头像为空
+	Avatar map[string]string `json:"avatar,omitempty"`
+	// 账号状态，管理员可以封禁
+	UserStatus
+	// 申请注销的时间，0表示正常
+	DeletedAt int64 `json:"deletedAt,omitempty"`
+	// 注销恢复期的截止时间，之前登录可以恢复账号
+	PurgeAt int64 `json:"purgeAt,omitempty"`
+}
+
+// 账号状态
+const (
+	UserStatusActive uint8 = iota
+	// 永久封禁
+	UserStatusBanned
+	// 暂停使用，到SuspendedUntil自动恢复
+	UserStatusSuspended
+)
+
+type UserStatus struct {
+	Status uint8 `json:"status"`
+	// 暂停使用的截止时间，毫秒
+	SuspendedUntil int64 `json:"suspendedUntil,omitempty"`
+	// 封禁原因，会展示给用户
+	StatusReason string `json:"statusReason,omitempty"`
+}
+
+// 现在是否不能使用，暂停过期了就当作正常
+func (s UserStatus) Banned(now int64) bool {
+	switch s.Status {
+	case UserStatusBanned:
+		return true
+	case UserStatusSuspended:
+		return now < s.SuspendedUntil
+	default:
+		return false
+	}
+}
+
+// 正在注销的恢复期内
+func (u User) Deleted() bool {
+	return u.DeletedAt != 0
+}
+
+// 登录中间件每个请求都要判断的账号状态
+type AccountStatus struct {
+	UserStatus
+	// 正在注销的恢复期内，恢复之前已经签发的凭证都不能用
+	PendingDeletion bool `json:"pendingDeletion,omitempty"`
+}
+
+// 被封禁、暂停或者正在注销
+func (s AccountStatus) Unusable(now int64) bool {
+	return s.PendingDeletion || s.Banned(now)
+}
+
+// 用邮箱注册、但还没验证邮箱，手机号和微信注册的账号不需要
+func (u User) NeedsEmailVerification() bool {
+	return u.Email != "" && !u.EmailVerified
+}
+
+// 公开主页能看到的资料，不能带手机号、邮箱、微信这些
+type PublicProfile struct {
+	Id          int64             `json:"id"`
+	Handle      string            `json:"handle"`
+	NickName    string            `json:"nickname,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Avatar      map[string]string `json:"avatar,omitempty"`
+	CreatedAt   int64             `json:"createdAt"`
+}
+
+func (u User) Public() PublicProfile {
+	return PublicProfile{
+		Id:          u.Id,
+		Handle:      u.Handle,
+		NickName:    u.NickName,
+		Description: u.Description,
+		Avatar:      u.Avatar,
+		CreatedAt:   u.CreatetAt,
+	}
 }
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/entity"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, l domain.AuditLog) (int64, error)
+	UpdateResult(ctx context.Context, id int64, result string, errMsg string) error
+	Find(ctx context.Context, targetId int64, offset int, limit int) ([]domain.AuditLog, int64, error)
+}
+
+type auditLogRepository struct {
+	entity entity.AuditLogEntity
+}
+
+func NewAuditLogRepository(entity entity.AuditLogEntity) AuditLogRepository {
+	return &auditLogRepository{entity: entity}
+}
+
+func (repo *auditLogRepository) Create(ctx context.Context, l domain.AuditLog) (int64, error) {
+	return repo.entity.Create(ctx, entity.AuditLog{
+		OperatorId: l.OperatorId,
+		Action:     l.Action,
+		TargetId:   l.TargetId,
+		Detail:     l.Detail,
+		Ip:         l.Ip,
+		UserAgent:  l.UserAgent,
+		Result:     l.Result,
+		Error:      l.Error,
+	})
+}
+
+func (repo *auditLogRepository) UpdateResult(ctx context.Context, id int64, result string, errMsg string) error {
+	return repo.entity.UpdateResult(ctx, id, result, errMsg)
+}
+
+func (repo *auditLogRepository) Find(ctx context.Context, targetId int64, offset int, limit int) ([]domain.AuditLog, int64, error) {
+	ls, total, err := repo.entity.Find(ctx, targetId, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	logs := make([]domain.AuditLog, 0, len(ls))
+	for _, l := range ls {
+		logs = append(logs, domain.AuditLog{
+			Id:         l.Id,
+			OperatorId: l.OperatorId,
+			Action:     l.Action,
+			TargetId:   l.TargetId,
+			Detail:     l.Detail,
+			Ip:         l.Ip,
+			UserAgent:  l.UserAgent,
+			Result:     l.Result,
+			Error:      l.Error,
+			CreatedAt:  l.CreateTime,
+		})
+	}
+	return logs, total, nil
+}
@@ -34,9 +34,9 @@ func (l *localCodeCache) Set(ctx context.Context, biz string, phone string, inpu
 	if err == bigcache.ErrEntryNotFound {
 		// 新发送手机短信，设置一下：发送的验证码、已验证次数
 		codeData := CodeData{
-			code:    inputCode,
-			count:   3,
-			setTime: time.Now().UnixMilli(),
+			Code:    inputCode,
+			Count:   3,
+			SetTime: time.Now().UnixMilli(),
 		}
 		encoded, err := l.encodeData(codeData)
 		if err != nil {
@@ -57,12 +57,12 @@ func (l *localCodeCache) Set(ctx context.Context, biz string, phone string, inpu
 	if err != nil {
 		return ErrUnknownForCode
 	}
-	if time.Since(time.Unix(c.setTime, 0)) >= time.Minute {
+	if time.Since(time.UnixMilli(c.SetTime)) >= time.Minute {
 		// 当前时间和设置时间超了1分钟，可以发送，重新set一下
 		codeData := CodeData{
-			code:    inputCode,
-			count:   3,
-			setTime: time.Now().UnixMilli(),
+			Code:    inputCode,
+			Count:   3,
+			SetTime: time.Now().UnixMilli(),
 		}
 		encoded, err := l.encodeData(codeData)
 		if err != nil {
@@ -83,7 +83,10 @@ func (l *localCodeCache) Verify(ctx context.Context, biz string, phone, inputCod
 
 	key := l.key(biz, phone)
 	cached, err := l.cache.Get(key)
-	// 缓存不存在，或者别的问题，都认为别人在搞我
+	// 和lua脚本一样，没发过验证码也当作验证次数用完了
+	if err == bigcache.ErrEntryNotFound {
+		return false, ErrCodeVerifyTooMany
+	}
 	if err != nil {
 		return false, ErrUnknownForCode
 	}
@@ -93,13 +96,14 @@ func (l *localCodeCache) Verify(ctx context.Context, biz string, phone, inputCod
 	}
 
 	// 用户已经使用了所有的尝试次数，有人在搞我
-	if codeData.count <= 0 {
+	// 验证的时候会重新写入，bigcache的过期时间跟着往后延，这里按发送时间再判断一次
+	if codeData.Count <= 0 || time.Since(time.UnixMilli(codeData.SetTime)) >= 10*time.Minute {
 		return false, ErrCodeVerifyTooMany
 	}
 
 	// 验证码匹配
-	if codeData.code == inputCode {
-		codeData.count = -1
+	if codeData.Code == inputCode {
+		codeData.Count = -1
 		encoded, err := l.encodeData(codeData)
 		if err != nil {
 			return false, ErrUnknownForCode
@@ -112,7 +116,7 @@ func (l *localCodeCache) Verify(ctx context.Context, biz string, phone, inputCod
 	}
 
 	// 验证码不匹配
-	codeData.count -= 1
+	codeData.Count -= 1
 	encoded, err := l.encodeData(codeData)
 	if err != nil {
 		return false, ErrUnknownForCode
@@ -152,11 +156,12 @@ func (l *localCodeCache) decode(data []byte) (CodeData, error) {
 	return c, nil
 }
 
+// 字段要导出，不然json编码不进去
 type CodeData struct {
 	// 验证码
-	code string
+	Code string `json:"code"`
 	// 验证次数
-	count int
+	Count int `json:"count"`
 	// 设置的毫秒时间戳
-	setTime int64
+	SetTime int64 `json:"setTime"`
 }
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 和lua脚本的行为保持一致
+func TestLocalCodeCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCodeCache()
+
+	// 没发过验证码
+	_, err := c.Verify(ctx, "login", "13800000000", "123456")
+	assert.Equal(t, ErrCodeVerifyTooMany, err)
+
+	require.NoError(t, c.Set(ctx, "login", "13800000000", "123456"))
+	// 一分钟内不能重发
+	assert.Equal(t, ErrCodeSendTooMany, c.Set(ctx, "login", "13800000000", "654321"))
+
+	ok, err := c.Verify(ctx, "login", "13800000000", "000000")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = c.Verify(ctx, "login", "13800000000", "123456")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	// 验证通过之后不能再用
+	_, err = c.Verify(ctx, "login", "13800000000", "123456")
+	assert.Equal(t, ErrCodeVerifyTooMany, err)
+
+	// 输错3次之后作废
+	require.NoError(t, c.Set(ctx, "login", "13900000000", "123456"))
+	for i := 0; i < 3; i++ {
+		ok, err = c.Verify(ctx, "login", "13900000000", "000000")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+	_, err = c.Verify(ctx, "login", "13900000000", "123456")
+	assert.Equal(t, ErrCodeVerifyTooMany, err)
+}
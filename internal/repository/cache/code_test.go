@@ -27,7 +27,7 @@ func TestCodeCache_Set(t *testing.T) {
 				redisClient := redismocks.NewMockCmdable(ctrl)
 				// 特殊的响应，返回的是int64
 				redisResponse := redis.NewCmdResult(int64(0), nil)
-				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone:login:11111111111"}, "123456").Return(redisResponse)
+				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone_code:login:11111111111"}, "123456").Return(redisResponse)
 				return redisClient
 			},
 			wantCode: 0,
@@ -43,7 +43,7 @@ func TestCodeCache_Set(t *testing.T) {
 				redisClient := redismocks.NewMockCmdable(ctrl)
 				// 特殊的响应，返回的是int64
 				redisResponse := redis.NewCmdResult(int64(-1), nil)
-				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone:login:11111111111"}, "123456").Return(redisResponse)
+				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone_code:login:11111111111"}, "123456").Return(redisResponse)
 				return redisClient
 			},
 			wantCode: -1,
@@ -59,7 +59,7 @@ func TestCodeCache_Set(t *testing.T) {
 				redisClient := redismocks.NewMockCmdable(ctrl)
 				// 特殊的响应，返回的是int64
 				redisResponse := redis.NewCmdResult(int64(-2), nil)
-				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone:login:11111111111"}, "123456").Return(redisResponse)
+				redisClient.EXPECT().Eval(gomock.Any(), setCodeScript, []string{"phone_code:login:11111111111"}, "123456").Return(redisResponse)
 				return redisClient
 			},
 			wantCode: -2,
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"webook/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 粉丝数和关注数，以数据库为准，数据库改了就删缓存
+type FollowCache interface {
+	GetStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error)
+	SetStatistic(ctx context.Context, userId int64, s domain.FollowStatistic) error
+	DelStatistic(ctx context.Context, userIds ...int64) error
+}
+
+type followCache struct {
+	client         redis.Cmdable
+	expirationTime time.Duration
+}
+
+func NewFollowCache(client redis.Cmdable) FollowCache {
+	return &followCache{client: client, expirationTime: time.Minute * 15}
+}
+
+func (cache *followCache) key(userId int64) string {
+	return fmt.Sprintf("follow.statistic.%d", userId)
+}
+
+func (cache *followCache) GetStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	var s domain.FollowStatistic
+	res, err := cache.client.HMGet(ctx, cache.key(userId), "followers", "followees").Result()
+	if err != nil {
+		return s, err
+	}
+	// 两个字段一起写，缺一个都当作不存在
+	if res[0] == nil || res[1] == nil {
+		return s, ErrKeyNotExist
+	}
+	if _, err = fmt.Sscan(res[0].(string), &s.Followers); err != nil {
+		return s, err
+	}
+	_, err = fmt.Sscan(res[1].(string), &s.Followees)
+	return s, err
+}
+
+func (cache *followCache) SetStatistic(ctx context.Context, userId int64, s domain.FollowStatistic) error {
+	pipe := cache.client.TxPipeline()
+	pipe.HSet(ctx, cache.key(userId), "followers", s.Followers, "followees", s.Followees)
+	pipe.Expire(ctx, cache.key(userId), cache.expirationTime)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (cache *followCache) DelStatistic(ctx context.Context, userIds ...int64) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		keys = append(keys, cache.key(id))
+	}
+	return cache.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+// 没有redis时用的关注数缓存
+type localFollowCache struct {
+	store          *localStore
+	expirationTime time.Duration
+}
+
+func NewLocalFollowCache() FollowCache {
+	return &localFollowCache{store: newLocalStore(time.Minute * 15), expirationTime: time.Minute * 15}
+}
+
+func (cache *localFollowCache) GetStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	val, ok := cache.store.get(cache.key(userId))
+	if !ok {
+		return domain.FollowStatistic{}, ErrKeyNotExist
+	}
+	return val.(domain.FollowStatistic), nil
+}
+
+func (cache *localFollowCache) SetStatistic(ctx context.Context, userId int64, s domain.FollowStatistic) error {
+	cache.store.set(cache.key(userId), s, cache.expirationTime)
+	return nil
+}
+
+func (cache *localFollowCache) DelStatistic(ctx context.Context, userIds ...int64) error {
+	keys := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		keys = append(keys, cache.key(id))
+	}
+	cache.store.del(keys...)
+	return nil
+}
+
+func (cache *localFollowCache) key(userId int64) string {
+	return strconv.FormatInt(userId, 10)
+}
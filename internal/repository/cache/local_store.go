@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// 没有redis时各个本地缓存共用的kv，带过期时间，只适合单机部署和本地开发
+// 值直接存结构体，取出来的是副本，不要存指针
+type localStore struct {
+	lock    sync.Mutex
+	entries map[string]localEntry
+	// 过期的数据读的时候才发现，每隔这么久整体清理一次，避免map一直变大
+	sweepInterval time.Duration
+	lastSweep     time.Time
+}
+
+type localEntry struct {
+	val      any
+	expireAt time.Time
+}
+
+func newLocalStore(sweepInterval time.Duration) *localStore {
+	return &localStore{entries: map[string]localEntry{}, sweepInterval: sweepInterval, lastSweep: time.Now()}
+}
+
+func (s *localStore) set(key string, val any, ttl time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	s.sweepLocked(now)
+	s.entries[key] = localEntry{val: val, expireAt: now.Add(ttl)}
+}
+
+func (s *localStore) get(key string) (any, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.getLocked(key, time.Now())
+}
+
+func (s *localStore) del(keys ...string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	n := 0
+	for _, key := range keys {
+		if _, ok := s.getLocked(key, now); ok {
+			n++
+		}
+		delete(s.entries, key)
+	}
+	return n
+}
+
+// 持有锁读改写，相当于redis里的lua脚本
+// fn返回新的值和是否保留，不保留就删掉，保留的沿用原来的过期时间，不存在的key不会新建
+func (s *localStore) update(key string, fn func(val any, ok bool) (any, bool)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	val, ok := s.getLocked(key, time.Now())
+	newVal, keep := fn(val, ok)
+	if !keep {
+		delete(s.entries, key)
+		return
+	}
+	if ok {
+		s.entries[key] = localEntry{val: newVal, expireAt: s.entries[key].expireAt}
+	}
+}
+
+func (s *localStore) getLocked(key string, now time.Time) (any, bool) {
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(e.expireAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return e.val, true
+}
+
+func (s *localStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !now.Before(e.expireAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	//go:embed lua/login_fail.lua
+	loginFailScript string
+	//go:embed lua/login_reserve.lua
+	loginReserveScript string
+	//go:embed lua/login_release.lua
+	loginReleaseScript string
+)
+
+var (
+	ErrAttemptLocked    = errors.New("账号锁定中")
+	ErrAttemptIpBlocked = errors.New("ip失败次数过多")
+)
+
+// 密码登录的失败次数和账号锁定
+type LoginAttemptCache interface {
+	// 登录前占用一次尝试，先按失败计数，lockSchedule[i]是第i+1次失败的锁定时间，超出的按最后一项算
+	// 账号锁定中返回ErrAttemptLocked，ip失败次数到了ipBlockAfter返回ErrAttemptIpBlocked，都不占用
+	Reserve(ctx context.Context, account string, ip string, ipBlockAfter int64,
+		lockSchedule []time.Duration) (domain.LoginAttempt, error)
+	// 密码正确，归还占用的尝试
+	Release(ctx context.Context, attempt domain.LoginAttempt) error
+	// 记录一次没有占用过的失败，返回统计窗口内账号和ip的失败次数
+	IncrFailure(ctx context.Context, account string, ip string) (int64, int64, error)
+	Failures(ctx context.Context, account string, ip string) (int64, int64, error)
+	// 锁定账号d时间
+	Lock(ctx context.Context, account string, d time.Duration) error
+	// 锁定到什么时候，毫秒时间戳，没有锁定返回0
+	LockedUntil(ctx context.Context, account string) (int64, error)
+	// 清掉账号的失败次数和锁定，ip的计数只能等统计窗口过期
+	// 否则攻击者用自己的账号登录一次就能把ip的计数清零
+	Reset(ctx context.Context, account string) error
+}
+
+type loginAttemptCache struct {
+	client redis.Cmdable
+	// 失败次数的统计窗口
+	window time.Duration
+}
+
+func NewLoginAttemptCache(client redis.Cmdable, window time.Duration) LoginAttemptCache {
+	return &loginAttemptCache{client: client, window: window}
+}
+
+func (c *loginAttemptCache) Reserve(ctx context.Context, account string, ip string, ipBlockAfter int64,
+	lockSchedule []time.Duration) (domain.LoginAttempt, error) {
+	args := make([]any, 0, len(lockSchedule)+3)
+	args = append(args, int64(c.window.Seconds()), ipBlockAfter, time.Now().UnixMilli())
+	for _, d := range lockSchedule {
+		args = append(args, d.Milliseconds())
+	}
+	res, err := c.client.Eval(ctx, loginReserveScript,
+		[]string{c.accountKey(account), c.ipKey(ip), c.lockKey(account)}, args...).Int64Slice()
+	if err != nil {
+		return domain.LoginAttempt{}, err
+	}
+	attempt := domain.LoginAttempt{Account: account, Ip: ip, AccountFailures: res[1], IpFailures: res[2], LockedUntil: res[3]}
+	switch res[0] {
+	case -1:
+		return attempt, ErrAttemptLocked
+	case -2:
+		return attempt, ErrAttemptIpBlocked
+	default:
+		return attempt, nil
+	}
+}
+
+func (c *loginAttemptCache) Release(ctx context.Context, attempt domain.LoginAttempt) error {
+	return c.client.Eval(ctx, loginReleaseScript,
+		[]string{c.accountKey(attempt.Account), c.ipKey(attempt.Ip), c.lockKey(attempt.Account)},
+		attempt.LockedUntil).Err()
+}
+
+func (c *loginAttemptCache) IncrFailure(ctx context.Context, account string, ip string) (int64, int64, error) {
+	res, err := c.client.Eval(ctx, loginFailScript, []string{c.accountKey(account), c.ipKey(ip)},
+		int64(c.window.Seconds())).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return res[0], res[1], nil
+}
+
+func (c *loginAttemptCache) Failures(ctx context.Context, account string, ip string) (int64, int64, error) {
+	vals, err := c.client.MGet(ctx, c.accountKey(account), c.ipKey(ip)).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	return toInt64(vals[0]), toInt64(vals[1]), nil
+}
+
+func (c *loginAttemptCache) Lock(ctx context.Context, account string, d time.Duration) error {
+	until := time.Now().Add(d).UnixMilli()
+	return c.client.Set(ctx, c.lockKey(account), until, d).Err()
+}
+
+func (c *loginAttemptCache) LockedUntil(ctx context.Context, account string) (int64, error) {
+	until, err := c.client.Get(ctx, c.lockKey(account)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return until, err
+}
+
+func (c *loginAttemptCache) Reset(ctx context.Context, account string) error {
+	return c.client.Del(ctx, c.accountKey(account), c.lockKey(account)).Err()
+}
+
+func (c *loginAttemptCache) accountKey(account string) string {
+	return fmt.Sprintf("login:fail:account:%s", account)
+}
+
+func (c *loginAttemptCache) ipKey(ip string) string {
+	return fmt.Sprintf("login:fail:ip:%s", ip)
+}
+
+func (c *loginAttemptCache) lockKey(account string) string {
+	return fmt.Sprintf("login:lock:%s", account)
+}
+
+// MGet返回的是字符串，key不存在是nil
+func toInt64(val any) int64 {
+	str, ok := val.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(str, 10, 64)
+	return n
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+	"webook/internal/domain"
+)
+
+// 没有redis时用的本地实现，只适合单机部署和本地开发，多实例之间的计数不共享
+type localLoginAttemptCache struct {
+	lock sync.Mutex
+	// 账号、ip的失败次数和账号的锁定都放在这里，用前缀区分
+	counters map[string]*localCounter
+	window   time.Duration
+	// 上一次清理过期计数的时间
+	lastSweep time.Time
+}
+
+type localCounter struct {
+	val      int64
+	expireAt time.Time
+}
+
+func NewLocalLoginAttemptCache(window time.Duration) LoginAttemptCache {
+	return &localLoginAttemptCache{counters: map[string]*localCounter{}, window: window, lastSweep: time.Now()}
+}
+
+// 和lua脚本的逻辑一样，整个过程持有锁
+func (c *localLoginAttemptCache) Reserve(ctx context.Context, account string, ip string, ipBlockAfter int64,
+	lockSchedule []time.Duration) (domain.LoginAttempt, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	c.sweepLocked(now)
+	attempt := domain.LoginAttempt{Account: account, Ip: ip}
+	if until := c.getLocked(c.lockKey(account), now); until > now.UnixMilli() {
+		attempt.LockedUntil = until
+		return attempt, ErrAttemptLocked
+	}
+	if ipFailures := c.getLocked(c.ipKey(ip), now); ipFailures >= ipBlockAfter {
+		attempt.IpFailures = ipFailures
+		return attempt, ErrAttemptIpBlocked
+	}
+	attempt.AccountFailures = c.incrLocked(c.accountKey(account), now) - 1
+	attempt.IpFailures = c.incrLocked(c.ipKey(ip), now) - 1
+	if n := len(lockSchedule); n > 0 {
+		d := lockSchedule[min(int(attempt.AccountFailures), n-1)]
+		if d > 0 {
+			until := now.Add(d)
+			attempt.LockedUntil = until.UnixMilli()
+			c.counters[c.lockKey(account)] = &localCounter{val: attempt.LockedUntil, expireAt: until}
+		}
+	}
+	return attempt, nil
+}
+
+func (c *localLoginAttemptCache) Release(ctx context.Context, attempt domain.LoginAttempt) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	for _, key := range []string{c.accountKey(attempt.Account), c.ipKey(attempt.Ip)} {
+		if c.getLocked(key, now) > 0 {
+			c.counters[key].val--
+		}
+	}
+	// 只删自己加的锁
+	lockKey := c.lockKey(attempt.Account)
+	if attempt.LockedUntil != 0 && c.getLocked(lockKey, now) == attempt.LockedUntil {
+		delete(c.counters, lockKey)
+	}
+	return nil
+}
+
+func (c *localLoginAttemptCache) IncrFailure(ctx context.Context, account string, ip string) (int64, int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	c.sweepLocked(now)
+	return c.incrLocked(c.accountKey(account), now), c.incrLocked(c.ipKey(ip), now), nil
+}
+
+func (c *localLoginAttemptCache) Failures(ctx context.Context, account string, ip string) (int64, int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	return c.getLocked(c.accountKey(account), now), c.getLocked(c.ipKey(ip), now), nil
+}
+
+func (c *localLoginAttemptCache) Lock(ctx context.Context, account string, d time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	until := time.Now().Add(d)
+	c.counters[c.lockKey(account)] = &localCounter{val: until.UnixMilli(), expireAt: until}
+	return nil
+}
+
+func (c *localLoginAttemptCache) LockedUntil(ctx context.Context, account string) (int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.getLocked(c.lockKey(account), time.Now()), nil
+}
+
+func (c *localLoginAttemptCache) Reset(ctx context.Context, account string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.counters, c.accountKey(account))
+	delete(c.counters, c.lockKey(account))
+	return nil
+}
+
+// 和lua脚本一样，第一次失败时开始计时
+func (c *localLoginAttemptCache) incrLocked(key string, now time.Time) int64 {
+	counter, ok := c.counters[key]
+	if !ok || !now.Before(counter.expireAt) {
+		counter = &localCounter{expireAt: now.Add(c.window)}
+		c.counters[key] = counter
+	}
+	counter.val++
+	return counter.val
+}
+
+func (c *localLoginAttemptCache) getLocked(key string, now time.Time) int64 {
+	counter, ok := c.counters[key]
+	if !ok || !now.Before(counter.expireAt) {
+		return 0
+	}
+	return counter.val
+}
+
+// 每个窗口清理一次过期的计数，避免map一直变大
+func (c *localLoginAttemptCache) sweepLocked(now time.Time) {
+	if now.Sub(c.lastSweep) < c.window {
+		return
+	}
+	c.lastSweep = now
+	for key, counter := range c.counters {
+		if !now.Before(counter.expireAt) {
+			delete(c.counters, key)
+		}
+	}
+}
+
+func (c *localLoginAttemptCache) accountKey(account string) string {
+	return "account:" + account
+}
+
+func (c *localLoginAttemptCache) ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (c *localLoginAttemptCache) lockKey(account string) string {
+	return "lock:" + account
+}
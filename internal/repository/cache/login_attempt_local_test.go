@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLoginAttemptCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalLoginAttemptCache(time.Millisecond * 50)
+
+	account, ip, err := c.IncrFailure(ctx, "a@qq.com", "1.1.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{1, 1}, [2]int64{account, ip})
+	account, ip, err = c.IncrFailure(ctx, "b@qq.com", "1.1.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{1, 2}, [2]int64{account, ip})
+
+	require.NoError(t, c.Lock(ctx, "a@qq.com", time.Minute))
+	until, err := c.LockedUntil(ctx, "a@qq.com")
+	require.NoError(t, err)
+	assert.Greater(t, until, time.Now().UnixMilli())
+
+	// 登录成功只清账号的计数和锁定，ip的不清
+	require.NoError(t, c.Reset(ctx, "a@qq.com"))
+	account, ip, err = c.Failures(ctx, "a@qq.com", "1.1.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{0, 2}, [2]int64{account, ip})
+	until, err = c.LockedUntil(ctx, "a@qq.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), until)
+
+	// 过了统计窗口重新计数
+	time.Sleep(time.Millisecond * 60)
+	account, ip, err = c.IncrFailure(ctx, "b@qq.com", "1.1.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{1, 1}, [2]int64{account, ip})
+}
+
+// 并发的请求各自占用一次，锁上之后后来的都被拒绝，不会都通过检查
+func TestLocalLoginAttemptCache_Reserve(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalLoginAttemptCache(time.Minute)
+	// 第3次失败开始锁
+	schedule := []time.Duration{0, 0, time.Minute}
+
+	var wg sync.WaitGroup
+	var reserved, locked atomic.Int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Reserve(ctx, "a@qq.com", "1.1.1.1", 100, schedule)
+			switch err {
+			case nil:
+				reserved.Add(1)
+			case ErrAttemptLocked:
+				locked.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(3), reserved.Load())
+	assert.Equal(t, int64(7), locked.Load())
+
+	// 归还只去掉自己加的锁
+	c = NewLocalLoginAttemptCache(time.Minute)
+	attempt, err := c.Reserve(ctx, "b@qq.com", "2.2.2.2", 100, []time.Duration{time.Minute})
+	require.NoError(t, err)
+	assert.Greater(t, attempt.LockedUntil, time.Now().UnixMilli())
+	require.NoError(t, c.Release(ctx, attempt))
+	account, ip, err := c.Failures(ctx, "b@qq.com", "2.2.2.2")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{0, 0}, [2]int64{account, ip})
+	until, err := c.LockedUntil(ctx, "b@qq.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), until)
+
+	// ip失败次数到了阈值直接拒绝，不计数
+	_, err = c.Reserve(ctx, "c@qq.com", "2.2.2.2", 0, nil)
+	assert.Equal(t, ErrAttemptIpBlocked, err)
+}
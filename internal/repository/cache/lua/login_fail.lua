@@ -0,0 +1,11 @@
+-- 账号和ip的失败次数各加一，第一次失败时开始计时
+local window = tonumber(ARGV[1])
+local account = redis.call("INCR", KEYS[1])
+if account == 1 then
+    redis.call("EXPIRE", KEYS[1], window)
+end
+local ip = redis.call("INCR", KEYS[2])
+if ip == 1 then
+    redis.call("EXPIRE", KEYS[2], window)
+end
+return {account, ip}
@@ -0,0 +1,14 @@
+-- 归还占用的尝试，计数减回去，这次加上的锁去掉
+-- KEYS: 账号的失败次数、ip的失败次数、账号的锁
+-- ARGV: 占用时加的锁，0表示没有加锁
+for i = 1, 2 do
+    -- 已经过期的不能减成负数
+    if tonumber(redis.call("GET", KEYS[i]) or "0") > 0 then
+        redis.call("DECR", KEYS[i])
+    end
+end
+-- 只删自己加的锁，别的请求加的留着
+if ARGV[1] ~= "0" and redis.call("GET", KEYS[3]) == ARGV[1] then
+    redis.call("DEL", KEYS[3])
+end
+return 0
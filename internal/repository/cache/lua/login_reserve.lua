@@ -0,0 +1,43 @@
+-- 登录前占用一次尝试：先按失败计数，该锁就先锁上，并发的请求只能等这次的结果
+-- KEYS: 账号的失败次数、ip的失败次数、账号的锁
+-- ARGV: 统计窗口(秒)、ip拒绝的阈值、当前时间(毫秒)、第1..n次失败的锁定时间(毫秒)
+-- 返回{状态, 账号之前的失败次数, ip之前的失败次数, 锁定到什么时候}
+-- 状态: 0占用成功，-1账号锁定中，-2 ip失败太多
+local window = tonumber(ARGV[1])
+local ipBlockAfter = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local lockedUntil = tonumber(redis.call("GET", KEYS[3]) or "0")
+if lockedUntil > now then
+    return {-1, 0, 0, lockedUntil}
+end
+local ip = tonumber(redis.call("GET", KEYS[2]) or "0")
+if ip >= ipBlockAfter then
+    return {-2, 0, ip, 0}
+end
+
+-- 和login_fail.lua一样，第一次失败时开始计时
+local account = redis.call("INCR", KEYS[1])
+if account == 1 then
+    redis.call("EXPIRE", KEYS[1], window)
+end
+ip = redis.call("INCR", KEYS[2])
+if ip == 1 then
+    redis.call("EXPIRE", KEYS[2], window)
+end
+
+-- 超过表的长度按最后一项算
+local n = #ARGV - 3
+lockedUntil = 0
+if n > 0 then
+    local idx = account
+    if idx > n then
+        idx = n
+    end
+    local d = tonumber(ARGV[3 + idx])
+    if d > 0 then
+        lockedUntil = now + d
+        redis.call("SET", KEYS[3], lockedUntil, "PX", d)
+    end
+end
+return {0, account - 1, ip - 1, lockedUntil}
@@ -0,0 +1,17 @@
+-- 两步验证的登录凭证，每校验一次记一次次数
+-- 返回{状态, uid}，试太多次的时候也返回uid，用于记录登录历史
+local key = KEYS[1]
+local maxAttempts = tonumber(ARGV[1])
+
+local uid = redis.call("hget", key, "uid")
+if not uid then
+    -- 不存在或者已经过期
+    return {-1, 0}
+end
+local cnt = redis.call("hincrby", key, "cnt", 1)
+if cnt > maxAttempts then
+    -- 试太多次了，凭证作废，重新登录
+    redis.call("del", key)
+    return {-2, tonumber(uid)}
+end
+return {0, tonumber(uid)}
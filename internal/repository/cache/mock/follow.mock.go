@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/cache/follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/cache/follow.go -package=cachemocks -destination=./internal/repository/cache/mock/follow.mock.go
+//
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowCache is a mock of FollowCache interface.
+type MockFollowCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowCacheMockRecorder
+}
+
+// MockFollowCacheMockRecorder is the mock recorder for MockFollowCache.
+type MockFollowCacheMockRecorder struct {
+	mock *MockFollowCache
+}
+
+// NewMockFollowCache creates a new mock instance.
+func NewMockFollowCache(ctrl *gomock.Controller) *MockFollowCache {
+	mock := &MockFollowCache{ctrl: ctrl}
+	mock.recorder = &MockFollowCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowCache) EXPECT() *MockFollowCacheMockRecorder {
+	return m.recorder
+}
+
+// DelStatistic mocks base method.
+func (m *MockFollowCache) DelStatistic(ctx context.Context, userIds ...int64) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range userIds {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DelStatistic", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelStatistic indicates an expected call of DelStatistic.
+func (mr *MockFollowCacheMockRecorder) DelStatistic(ctx any, userIds ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, userIds...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelStatistic", reflect.TypeOf((*MockFollowCache)(nil).DelStatistic), varargs...)
+}
+
+// GetStatistic mocks base method.
+func (m *MockFollowCache) GetStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatistic", ctx, userId)
+	ret0, _ := ret[0].(domain.FollowStatistic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatistic indicates an expected call of GetStatistic.
+func (mr *MockFollowCacheMockRecorder) GetStatistic(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatistic", reflect.TypeOf((*MockFollowCache)(nil).GetStatistic), ctx, userId)
+}
+
+// SetStatistic mocks base method.
+func (m *MockFollowCache) SetStatistic(ctx context.Context, userId int64, s domain.FollowStatistic) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatistic", ctx, userId, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatistic indicates an expected call of SetStatistic.
+func (mr *MockFollowCacheMockRecorder) SetStatistic(ctx, userId, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatistic", reflect.TypeOf((*MockFollowCache)(nil).SetStatistic), ctx, userId, s)
+}
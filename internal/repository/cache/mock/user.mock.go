@@ -1,3 +1,11 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/cache/user.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/cache/user.go -package=cachemocks -destination=./internal/repository/cache/mock/user.mock.go
+//
+// Package cachemocks is a generated GoMock package.
 package cachemocks
 
 import (
@@ -10,52 +18,109 @@ import (
 
 // MockUserCache is a mock of UserCache interface.
 type MockUserCache struct {
-        ctrl     *gomock.Controller
-        recorder *MockUserCacheMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockUserCacheMockRecorder
 }
 
 // MockUserCacheMockRecorder is the mock recorder for MockUserCache.
 type MockUserCacheMockRecorder struct {
-        mock *MockUserCache
+	mock *MockUserCache
 }
 
 // NewMockUserCache creates a new mock instance.
 func NewMockUserCache(ctrl *gomock.Controller) *MockUserCache {
-        mock := &MockUserCache{ctrl: ctrl}
-        mock.recorder = &MockUserCacheMockRecorder{mock}
-        return mock
+	mock := &MockUserCache{ctrl: ctrl}
+	mock.recorder = &MockUserCacheMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockUserCache) EXPECT() *MockUserCacheMockRecorder {
-        return m.recorder
+	return m.recorder
+}
+
+// Del mocks base method.
+func (m *MockUserCache) Del(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockUserCacheMockRecorder) Del(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockUserCache)(nil).Del), ctx, id)
+}
+
+// DelHandle mocks base method.
+func (m *MockUserCache) DelHandle(ctx context.Context, handle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelHandle", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelHandle indicates an expected call of DelHandle.
+func (mr *MockUserCacheMockRecorder) DelHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelHandle", reflect.TypeOf((*MockUserCache)(nil).DelHandle), ctx, handle)
 }
 
 // Get mocks base method.
 func (m *MockUserCache) Get(ctx context.Context, id int64) (domain.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Get", ctx, id)
-        ret0, _ := ret[0].(domain.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
 func (mr *MockUserCacheMockRecorder) Get(ctx, id any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserCache)(nil).Get), ctx, id)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserCache)(nil).Get), ctx, id)
+}
+
+// GetHandle mocks base method.
+func (m *MockUserCache) GetHandle(ctx context.Context, handle string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHandle", ctx, handle)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHandle indicates an expected call of GetHandle.
+func (mr *MockUserCacheMockRecorder) GetHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHandle", reflect.TypeOf((*MockUserCache)(nil).GetHandle), ctx, handle)
 }
 
 // Set mocks base method.
 func (m *MockUserCache) Set(ctx context.Context, u domain.User) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Set", ctx, u)
-        ret0, _ := ret[0].(error)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, u)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Set indicates an expected call of Set.
 func (mr *MockUserCacheMockRecorder) Set(ctx, u any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockUserCache)(nil).Set), ctx, u)
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockUserCache)(nil).Set), ctx, u)
+}
+
+// SetHandle mocks base method.
+func (m *MockUserCache) SetHandle(ctx context.Context, handle string, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHandle", ctx, handle, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHandle indicates an expected call of SetHandle.
+func (mr *MockUserCacheMockRecorder) SetHandle(ctx, handle, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHandle", reflect.TypeOf((*MockUserCache)(nil).SetHandle), ctx, handle, id)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+// 缓存用户的角色和权限，角色变更时删除
+type RoleCache interface {
+	Get(ctx context.Context, userId int64) (domain.UserAuthz, error)
+	Set(ctx context.Context, authz domain.UserAuthz) error
+	Del(ctx context.Context, userId int64) error
+}
+
+type roleCache struct {
+	client         redis.Cmdable
+	expirationTime time.Duration
+}
+
+func NewRoleCache(client redis.Cmdable) RoleCache {
+	return &roleCache{
+		client:         client,
+		expirationTime: time.Minute * 15,
+	}
+}
+
+func (cache *roleCache) key(userId int64) string {
+	return fmt.Sprintf("user.authz.%v", userId)
+}
+
+func (cache *roleCache) Get(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	result, err := cache.client.Get(ctx, cache.key(userId)).Bytes()
+	if err != nil {
+		return domain.UserAuthz{}, err
+	}
+	var authz domain.UserAuthz
+	err = json.Unmarshal(result, &authz)
+	return authz, err
+}
+
+func (cache *roleCache) Set(ctx context.Context, authz domain.UserAuthz) error {
+	val, err := json.Marshal(authz)
+	if err != nil {
+		return err
+	}
+	return cache.client.Set(ctx, cache.key(authz.UserId), val, cache.expirationTime).Err()
+}
+
+func (cache *roleCache) Del(ctx context.Context, userId int64) error {
+	return cache.client.Del(ctx, cache.key(userId)).Err()
+}
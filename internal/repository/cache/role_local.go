@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+// 没有redis时用的角色缓存，改角色时只能删掉本机的缓存，和redis一样存json
+type localRoleCache struct {
+	store          *localStore
+	expirationTime time.Duration
+}
+
+func NewLocalRoleCache() RoleCache {
+	return &localRoleCache{store: newLocalStore(time.Minute * 15), expirationTime: time.Minute * 15}
+}
+
+func (cache *localRoleCache) Get(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	val, ok := cache.store.get(cache.key(userId))
+	if !ok {
+		return domain.UserAuthz{}, ErrKeyNotExist
+	}
+	var authz domain.UserAuthz
+	err := json.Unmarshal(val.([]byte), &authz)
+	return authz, err
+}
+
+func (cache *localRoleCache) Set(ctx context.Context, authz domain.UserAuthz) error {
+	val, err := json.Marshal(authz)
+	if err != nil {
+		return err
+	}
+	cache.store.set(cache.key(authz.UserId), val, cache.expirationTime)
+	return nil
+}
+
+func (cache *localRoleCache) Del(ctx context.Context, userId int64) error {
+	cache.store.del(cache.key(userId))
+	return nil
+}
+
+func (cache *localRoleCache) key(userId int64) string {
+	return strconv.FormatInt(userId, 10)
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/two_factor_challenge.lua
+var twoFactorChallengeScript string
+
+var (
+	ErrChallengeNotFound      = errors.New("登录凭证不存在或已过期")
+	ErrChallengeVerifyTooMany = errors.New("登录凭证校验次数过多")
+)
+
+// 两步验证的登录凭证，密码校验通过后发给前端，换取正式的token
+type TwoFactorChallengeCache interface {
+	Set(ctx context.Context, challenge string, userId int64, ttl time.Duration) error
+	// 返回凭证对应的用户，超过maxAttempts次凭证作废，这时也返回用户
+	Get(ctx context.Context, challenge string, maxAttempts int) (int64, error)
+	// 验证通过后删除，凭证只能用一次
+	Delete(ctx context.Context, challenge string) (bool, error)
+}
+
+type twoFactorChallengeCache struct {
+	client redis.Cmdable
+}
+
+func NewTwoFactorChallengeCache(client redis.Cmdable) TwoFactorChallengeCache {
+	return &twoFactorChallengeCache{client: client}
+}
+
+func (c *twoFactorChallengeCache) Set(ctx context.Context, challenge string, userId int64, ttl time.Duration) error {
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, c.key(challenge), "uid", userId, "cnt", 0)
+	pipe.Expire(ctx, c.key(challenge), ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *twoFactorChallengeCache) Get(ctx context.Context, challenge string, maxAttempts int) (int64, error) {
+	res, err := c.client.Eval(ctx, twoFactorChallengeScript, []string{c.key(challenge)}, maxAttempts).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	switch res[0] {
+	case -1:
+		return 0, ErrChallengeNotFound
+	case -2:
+		return res[1], ErrChallengeVerifyTooMany
+	default:
+		return res[1], nil
+	}
+}
+
+func (c *twoFactorChallengeCache) Delete(ctx context.Context, challenge string) (bool, error) {
+	n, err := c.client.Del(ctx, c.key(challenge)).Result()
+	return n > 0, err
+}
+
+func (c *twoFactorChallengeCache) key(challenge string) string {
+	return fmt.Sprintf("login:2fa:challenge:%s", challenge)
+}
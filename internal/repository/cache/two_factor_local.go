@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// 没有redis时用的两步验证登录凭证，只能在发凭证的那台机器上验证
+type localTwoFactorChallengeCache struct {
+	store *localStore
+}
+
+type localChallenge struct {
+	userId   int64
+	attempts int
+}
+
+func NewLocalTwoFactorChallengeCache() TwoFactorChallengeCache {
+	return &localTwoFactorChallengeCache{store: newLocalStore(time.Minute * 10)}
+}
+
+func (c *localTwoFactorChallengeCache) Set(ctx context.Context, challenge string, userId int64, ttl time.Duration) error {
+	c.store.set(challenge, localChallenge{userId: userId}, ttl)
+	return nil
+}
+
+// 和lua脚本一样，每校验一次记一次次数，试太多次凭证作废
+func (c *localTwoFactorChallengeCache) Get(ctx context.Context, challenge string, maxAttempts int) (int64, error) {
+	var ch localChallenge
+	found := false
+	c.store.update(challenge, func(val any, ok bool) (any, bool) {
+		if !ok {
+			return nil, false
+		}
+		found = true
+		ch = val.(localChallenge)
+		ch.attempts++
+		return ch, ch.attempts <= maxAttempts
+	})
+	if !found {
+		return 0, ErrChallengeNotFound
+	}
+	if ch.attempts > maxAttempts {
+		return ch.userId, ErrChallengeVerifyTooMany
+	}
+	return ch.userId, nil
+}
+
+func (c *localTwoFactorChallengeCache) Delete(ctx context.Context, challenge string) (bool, error) {
+	return c.store.del(challenge) > 0, nil
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalTwoFactorChallengeCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalTwoFactorChallengeCache()
+
+	_, err := c.Get(ctx, "c1", 2)
+	assert.Equal(t, ErrChallengeNotFound, err)
+
+	require.NoError(t, c.Set(ctx, "c1", 1, time.Minute))
+	for i := 0; i < 2; i++ {
+		uid, err := c.Get(ctx, "c1", 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), uid)
+	}
+	// 试太多次凭证作废，也返回用户
+	uid, err := c.Get(ctx, "c1", 2)
+	assert.Equal(t, ErrChallengeVerifyTooMany, err)
+	assert.Equal(t, int64(1), uid)
+	_, err = c.Get(ctx, "c1", 2)
+	assert.Equal(t, ErrChallengeNotFound, err)
+
+	// 只能用一次
+	require.NoError(t, c.Set(ctx, "c2", 2, time.Minute))
+	ok, err := c.Delete(ctx, "c2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = c.Delete(ctx, "c2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 过期
+	require.NoError(t, c.Set(ctx, "c3", 3, time.Millisecond*10))
+	time.Sleep(time.Millisecond * 20)
+	_, err = c.Get(ctx, "c3", 2)
+	assert.Equal(t, ErrChallengeNotFound, err)
+}
+
+// 微信绑定的nonce取一次就没了
+func TestLocalWeChatBindCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalWeChatBindCache()
+	require.NoError(t, c.Set(ctx, "state", 1, time.Minute))
+	uid, err := c.Take(ctx, "state")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), uid)
+	_, err = c.Take(ctx, "state")
+	assert.Equal(t, ErrBindStateNotFound, err)
+}
@@ -12,6 +12,11 @@ import (
 type UserCache interface {
 	Set(ctx context.Context, u domain.User) error
 	Get(ctx context.Context, id int64) (domain.User, error)
+	Del(ctx context.Context, id int64) error
+	// 用户名到id的映射，公开主页先查这个再查用户缓存
+	SetHandle(ctx context.Context, handle string, id int64) error
+	GetHandle(ctx context.Context, handle string) (int64, error)
+	DelHandle(ctx context.Context, handle string) error
 }
 
 var ErrKeyNotExist = redis.Nil
@@ -65,3 +70,23 @@ func (cache *userCache) Get(ctx context.Context, id int64) (domain.User, error)
 	}
 	return u, nil
 }
+
+func (cache *userCache) Del(ctx context.Context, id int64) error {
+	return cache.client.Del(ctx, cache.key(id)).Err()
+}
+
+func (cache *userCache) handleKey(handle string) string {
+	return fmt.Sprintf("user.handle.%s", handle)
+}
+
+func (cache *userCache) SetHandle(ctx context.Context, handle string, id int64) error {
+	return cache.client.Set(ctx, cache.handleKey(handle), id, cache.expirationTime).Err()
+}
+
+func (cache *userCache) GetHandle(ctx context.Context, handle string) (int64, error) {
+	return cache.client.Get(ctx, cache.handleKey(handle)).Int64()
+}
+
+func (cache *userCache) DelHandle(ctx context.Context, handle string) error {
+	return cache.client.Del(ctx, cache.handleKey(handle)).Err()
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+// 没有redis时用的用户缓存，多实例之间不共享，改了数据只能删掉本机的缓存
+// 和redis一样存json，用户里有map，不能让调用方拿到同一份
+type localUserCache struct {
+	store          *localStore
+	expirationTime time.Duration
+}
+
+func NewLocalUserCache() UserCache {
+	return &localUserCache{store: newLocalStore(time.Minute * 15), expirationTime: time.Minute * 15}
+}
+
+func (cache *localUserCache) Set(ctx context.Context, u domain.User) error {
+	val, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	cache.store.set(cache.key(u.Id), val, cache.expirationTime)
+	return nil
+}
+
+func (cache *localUserCache) Get(ctx context.Context, id int64) (domain.User, error) {
+	val, ok := cache.store.get(cache.key(id))
+	if !ok {
+		return domain.User{}, ErrKeyNotExist
+	}
+	var u domain.User
+	err := json.Unmarshal(val.([]byte), &u)
+	return u, err
+}
+
+func (cache *localUserCache) Del(ctx context.Context, id int64) error {
+	cache.store.del(cache.key(id))
+	return nil
+}
+
+func (cache *localUserCache) SetHandle(ctx context.Context, handle string, id int64) error {
+	cache.store.set(cache.handleKey(handle), id, cache.expirationTime)
+	return nil
+}
+
+func (cache *localUserCache) GetHandle(ctx context.Context, handle string) (int64, error) {
+	val, ok := cache.store.get(cache.handleKey(handle))
+	if !ok {
+		return 0, ErrKeyNotExist
+	}
+	return val.(int64), nil
+}
+
+func (cache *localUserCache) DelHandle(ctx context.Context, handle string) error {
+	cache.store.del(cache.handleKey(handle))
+	return nil
+}
+
+func (cache *localUserCache) key(id int64) string {
+	return "info:" + strconv.FormatInt(id, 10)
+}
+
+func (cache *localUserCache) handleKey(handle string) string {
+	return "handle:" + handle
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// 进程内的账号状态缓存，登录中间件每个请求都要查，不能每次都走redis
+// 过期时间就是封禁生效的最大延迟，所以要设得很短
+type UserStatusCache interface {
+	Get(ctx context.Context, userId int64) (domain.AccountStatus, error)
+	Set(ctx context.Context, userId int64, s domain.AccountStatus) error
+}
+
+type localUserStatusCache struct {
+	cache *bigcache.BigCache
+}
+
+func NewLocalUserStatusCache(expiration time.Duration) UserStatusCache {
+	cfg := bigcache.DefaultConfig(expiration)
+	// 过期的数据按过期时间清理，清理间隔决定了实际的最大延迟
+	cfg.CleanWindow = expiration
+	cache, err := bigcache.New(context.Background(), cfg)
+	if err != nil {
+		panic(err)
+	}
+	return &localUserStatusCache{cache: cache}
+}
+
+func (c *localUserStatusCache) Get(ctx context.Context, userId int64) (domain.AccountStatus, error) {
+	var s domain.AccountStatus
+	data, err := c.cache.Get(strconv.FormatInt(userId, 10))
+	if err == bigcache.ErrEntryNotFound {
+		return s, ErrKeyNotExist
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+func (c *localUserStatusCache) Set(ctx context.Context, userId int64, s domain.AccountStatus) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return c.cache.Set(strconv.FormatInt(userId, 10), data)
+}
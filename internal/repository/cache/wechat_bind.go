@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrBindStateNotFound = errors.New("绑定请求不存在或已过期")
+
+// 已登录用户发起绑定微信时，按扫码的state记下是谁发起的，回调时取出来
+// 只能取一次，避免state被拿去重复绑定
+type WeChatBindCache interface {
+	Set(ctx context.Context, state string, userId int64, ttl time.Duration) error
+	// 取出之后就删掉
+	Take(ctx context.Context, state string) (int64, error)
+}
+
+type weChatBindCache struct {
+	client redis.Cmdable
+}
+
+func NewWeChatBindCache(client redis.Cmdable) WeChatBindCache {
+	return &weChatBindCache{client: client}
+}
+
+func (c *weChatBindCache) Set(ctx context.Context, state string, userId int64, ttl time.Duration) error {
+	return c.client.Set(ctx, c.key(state), userId, ttl).Err()
+}
+
+func (c *weChatBindCache) Take(ctx context.Context, state string) (int64, error) {
+	userId, err := c.client.GetDel(ctx, c.key(state)).Int64()
+	if err == redis.Nil {
+		return 0, ErrBindStateNotFound
+	}
+	return userId, err
+}
+
+func (c *weChatBindCache) key(state string) string {
+	return fmt.Sprintf("oauth2:wechat:bind:%s", state)
+}
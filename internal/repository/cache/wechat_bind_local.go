@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// 没有redis时用的微信绑定nonce，扫码回调要落到发起绑定的那台机器上
+type localWeChatBindCache struct {
+	store *localStore
+}
+
+func NewLocalWeChatBindCache() WeChatBindCache {
+	return &localWeChatBindCache{store: newLocalStore(time.Minute * 10)}
+}
+
+func (c *localWeChatBindCache) Set(ctx context.Context, state string, userId int64, ttl time.Duration) error {
+	c.store.set(state, userId, ttl)
+	return nil
+}
+
+func (c *localWeChatBindCache) Take(ctx context.Context, state string) (int64, error) {
+	var userId int64
+	found := false
+	c.store.update(state, func(val any, ok bool) (any, bool) {
+		if ok {
+			userId, found = val.(int64), true
+		}
+		return nil, false
+	})
+	if !found {
+		return 0, ErrBindStateNotFound
+	}
+	return userId, nil
+}
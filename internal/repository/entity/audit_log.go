@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditLogEntity interface {
+	// 返回新记录的id
+	Create(ctx context.Context, l AuditLog) (int64, error)
+	// 操作执行完之后回填结果
+	UpdateResult(ctx context.Context, id int64, result string, errMsg string) error
+	// targetId为0表示不按被操作的用户过滤，按id倒序
+	Find(ctx context.Context, targetId int64, offset int, limit int) ([]AuditLog, int64, error)
+}
+
+// 操作audit_logs表的entity，除了回填结果不修改
+type auditLogEntity struct {
+	db *gorm.DB
+}
+
+func NewAuditLogEntity(db *gorm.DB) AuditLogEntity {
+	return &auditLogEntity{db: db}
+}
+
+func (entity *auditLogEntity) Create(ctx context.Context, l AuditLog) (int64, error) {
+	now := time.Now().UnixMilli()
+	l.CreateTime, l.UpdateTime = now, now
+	err := entity.db.WithContext(ctx).Create(&l).Error
+	return l.Id, err
+}
+
+func (entity *auditLogEntity) UpdateResult(ctx context.Context, id int64, result string, errMsg string) error {
+	return entity.db.WithContext(ctx).Model(&AuditLog{}).Where("id = ?", id).Updates(map[string]any{
+		"result":      result,
+		"error":       errMsg,
+		"update_time": time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *auditLogEntity) Find(ctx context.Context, targetId int64, offset int, limit int) ([]AuditLog, int64, error) {
+	query := entity.db.WithContext(ctx).Model(&AuditLog{})
+	if targetId > 0 {
+		query = query.Where("target_id = ?", targetId)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []AuditLog
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error
+	return logs, total, err
+}
+
+// 管理员操作的审计日志
+type AuditLog struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	OperatorId int64  `gorm:"index"`
+	Action     string `gorm:"type:varchar(64)"`
+	TargetId   int64  `gorm:"index"`
+	Detail     string `gorm:"type:varchar(1024)"`
+	Ip         string `gorm:"type:varchar(64)"`
+	// 浏览器信息
+	UserAgent string `gorm:"type:varchar(512)"`
+	// pending说明操作还没执行完，或者执行完回填失败了
+	Result string `gorm:"type:varchar(16)"`
+	Error  string `gorm:"type:varchar(256)"`
+
+	CreateTime int64
+	UpdateTime int64
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type DataExportEntity interface {
+	Create(ctx context.Context, e DataExport) (DataExport, error)
+	FindById(ctx context.Context, id int64) (DataExport, error)
+	// 用户最近的一次导出
+	FindLatest(ctx context.Context, userId int64) (DataExport, error)
+	MarkReady(ctx context.Context, id int64, file string, expireTime int64) error
+	MarkFailed(ctx context.Context, id int64) error
+	// 过期了、需要删文件的导出
+	FindExpired(ctx context.Context, now int64, limit int) ([]DataExport, error)
+	// 用户的所有导出，注销清理时用
+	FindByUser(ctx context.Context, userId int64) ([]DataExport, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+var ErrDataExportNotFound = gorm.ErrRecordNotFound
+
+// 导出的状态，和domain里的一致
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+type dataExportEntity struct {
+	db *gorm.DB
+}
+
+func NewDataExportEntity(db *gorm.DB) DataExportEntity {
+	return &dataExportEntity{db: db}
+}
+
+func (entity *dataExportEntity) Create(ctx context.Context, e DataExport) (DataExport, error) {
+	now := time.Now().UnixMilli()
+	e.CreateTime = now
+	e.UpdateTime = now
+	err := entity.db.WithContext(ctx).Create(&e).Error
+	return e, err
+}
+
+func (entity *dataExportEntity) FindById(ctx context.Context, id int64) (DataExport, error) {
+	var e DataExport
+	err := entity.db.WithContext(ctx).Where("id = ?", id).First(&e).Error
+	return e, err
+}
+
+func (entity *dataExportEntity) FindLatest(ctx context.Context, userId int64) (DataExport, error) {
+	var e DataExport
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Order("id DESC").First(&e).Error
+	return e, err
+}
+
+func (entity *dataExportEntity) MarkReady(ctx context.Context, id int64, file string, expireTime int64) error {
+	return entity.db.WithContext(ctx).Model(&DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      DataExportReady,
+		"file":        file,
+		"expire_time": expireTime,
+		"update_time": time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *dataExportEntity) MarkFailed(ctx context.Context, id int64) error {
+	return entity.db.WithContext(ctx).Model(&DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      DataExportFailed,
+		"update_time": time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *dataExportEntity) FindExpired(ctx context.Context, now int64, limit int) ([]DataExport, error) {
+	var es []DataExport
+	err := entity.db.WithContext(ctx).Where("expire_time > 0 AND expire_time <= ?", now).
+		Limit(limit).Find(&es).Error
+	return es, err
+}
+
+func (entity *dataExportEntity) FindByUser(ctx context.Context, userId int64) ([]DataExport, error) {
+	var es []DataExport
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Find(&es).Error
+	return es, err
+}
+
+func (entity *dataExportEntity) Delete(ctx context.Context, id int64) error {
+	return entity.db.WithContext(ctx).Where("id = ?", id).Delete(&DataExport{}).Error
+}
+
+// 个人数据导出表结构，归档文件放在磁盘上
+type DataExport struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	UserId int64  `gorm:"index"`
+	Status string `gorm:"type:varchar(16)"`
+	File   string `gorm:"type:varchar(255)"`
+	// 归档文件的过期时间，0表示还没生成
+	ExpireTime int64 `gorm:"index"`
+
+	CreateTime int64
+	UpdateTime int64
+}
@@ -0,0 +1,226 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowEntity interface {
+	// 关注，已经关注过返回ErrFollowExists，计数不变
+	Follow(ctx context.Context, follower int64, followee int64) error
+	// 取消关注，没关注过返回ErrFollowNotFound
+	Unfollow(ctx context.Context, follower int64, followee int64) error
+	// 按id倒序分页，cursor是上一页最后一条的id，0表示第一页
+	FindFollowers(ctx context.Context, followee int64, cursor int64, limit int) ([]FollowRelation, error)
+	FindFollowees(ctx context.Context, follower int64, cursor int64, limit int) ([]FollowRelation, error)
+	FindRelation(ctx context.Context, follower int64, followee int64) (FollowRelation, error)
+	// 没有记录返回零值
+	FindStatistic(ctx context.Context, userId int64) (FollowStatistic, error)
+	// 删掉用户所有的关注关系，返回计数变了的用户
+	RemoveUser(ctx context.Context, userId int64) ([]int64, error)
+	// 用户合并时把关注关系挪到另一个用户上，重复的和自己关注自己的丢掉，返回计数变了的用户
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) ([]int64, error)
+}
+
+var (
+	ErrFollowExists   = errors.New("已经关注过了")
+	ErrFollowNotFound = errors.New("没有关注过")
+)
+
+// 操作follow_relations、follow_statistics表的entity
+// 计数和关注关系在同一个事务里改，保证一致
+type followEntity struct {
+	db *gorm.DB
+}
+
+func NewFollowEntity(db *gorm.DB) FollowEntity {
+	return &followEntity{db: db}
+}
+
+func (entity *followEntity) Follow(ctx context.Context, follower int64, followee int64) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		err := tx.Create(&FollowRelation{Follower: follower, Followee: followee, CreateTime: now}).Error
+		if isUniqueConflict(err) {
+			return ErrFollowExists
+		}
+		if err != nil {
+			return err
+		}
+		if err = incrStatistic(tx, followee, "followers", 1, now); err != nil {
+			return err
+		}
+		return incrStatistic(tx, follower, "followees", 1, now)
+	})
+}
+
+func (entity *followEntity) Unfollow(ctx context.Context, follower int64, followee int64) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("follower = ? AND followee = ?", follower, followee).Delete(&FollowRelation{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrFollowNotFound
+		}
+		now := time.Now().UnixMilli()
+		if err := incrStatistic(tx, followee, "followers", -1, now); err != nil {
+			return err
+		}
+		return incrStatistic(tx, follower, "followees", -1, now)
+	})
+}
+
+// 计数行不存在就插入，存在就在原来的基础上加
+func incrStatistic(tx *gorm.DB, userId int64, column string, delta int64, now int64) error {
+	s := FollowStatistic{UserId: userId, UpdateTime: now}
+	if column == "followers" {
+		s.Followers = delta
+	} else {
+		s.Followees = delta
+	}
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			column:        gorm.Expr(column+" + ?", delta),
+			"update_time": now,
+		}),
+	}).Create(&s).Error
+}
+
+func (entity *followEntity) FindFollowers(ctx context.Context, followee int64, cursor int64, limit int) ([]FollowRelation, error) {
+	return entity.page(ctx, entity.db.Where("followee = ?", followee), cursor, limit)
+}
+
+func (entity *followEntity) FindFollowees(ctx context.Context, follower int64, cursor int64, limit int) ([]FollowRelation, error) {
+	return entity.page(ctx, entity.db.Where("follower = ?", follower), cursor, limit)
+}
+
+func (entity *followEntity) page(ctx context.Context, query *gorm.DB, cursor int64, limit int) ([]FollowRelation, error) {
+	query = query.WithContext(ctx)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	var res []FollowRelation
+	err := query.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (entity *followEntity) FindRelation(ctx context.Context, follower int64, followee int64) (FollowRelation, error) {
+	var r FollowRelation
+	err := entity.db.WithContext(ctx).Where("follower = ? AND followee = ?", follower, followee).First(&r).Error
+	return r, err
+}
+
+func (entity *followEntity) FindStatistic(ctx context.Context, userId int64) (FollowStatistic, error) {
+	var s FollowStatistic
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return FollowStatistic{UserId: userId}, nil
+	}
+	return s, err
+}
+
+func (entity *followEntity) RemoveUser(ctx context.Context, userId int64) ([]int64, error) {
+	var affected []int64
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		affected, err = relatedUsers(tx, userId)
+		if err != nil {
+			return err
+		}
+		err = tx.Where("follower = ? OR followee = ?", userId, userId).Delete(&FollowRelation{}).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("user_id = ?", userId).Delete(&FollowStatistic{}).Error; err != nil {
+			return err
+		}
+		return recount(tx, affected)
+	})
+	return append(affected, userId), err
+}
+
+func (entity *followEntity) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) ([]int64, error) {
+	var affected []int64
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		affected, err = relatedUsers(tx, fromUserId)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`INSERT IGNORE INTO follow_relations (follower, followee, create_time)
+SELECT ?, followee, create_time FROM follow_relations WHERE follower = ? AND followee <> ?`,
+			toUserId, fromUserId, toUserId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`INSERT IGNORE INTO follow_relations (follower, followee, create_time)
+SELECT follower, ?, create_time FROM follow_relations WHERE followee = ? AND follower <> ?`,
+			toUserId, fromUserId, toUserId).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("follower = ? OR followee = ?", fromUserId, fromUserId).Delete(&FollowRelation{}).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("user_id = ?", fromUserId).Delete(&FollowStatistic{}).Error; err != nil {
+			return err
+		}
+		affected = append(affected, toUserId)
+		return recount(tx, affected)
+	})
+	return append(affected, fromUserId), err
+}
+
+// 关注了userId或者被userId关注的用户
+func relatedUsers(tx *gorm.DB, userId int64) ([]int64, error) {
+	var ids []int64
+	err := tx.Raw(`SELECT followee FROM follow_relations WHERE follower = ?
+UNION SELECT follower FROM follow_relations WHERE followee = ?`, userId, userId).Scan(&ids).Error
+	return ids, err
+}
+
+// 批量改关系之后直接按关系表重新算计数
+func recount(tx *gorm.DB, userIds []int64) error {
+	now := time.Now().UnixMilli()
+	for _, id := range userIds {
+		var s FollowStatistic
+		s.UserId, s.UpdateTime = id, now
+		err := tx.Model(&FollowRelation{}).Where("followee = ?", id).Count(&s.Followers).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Model(&FollowRelation{}).Where("follower = ?", id).Count(&s.Followees).Error; err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"followers", "followees", "update_time"}),
+		}).Create(&s).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 关注关系，follower关注了followee
+type FollowRelation struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	// 查粉丝列表用
+	Followee   int64 `gorm:"uniqueIndex:follower_followee;index"`
+	CreateTime int64
+}
+
+// 粉丝数和关注数
+type FollowStatistic struct {
+	UserId     int64 `gorm:"primaryKey,autoIncrement:false"`
+	Followers  int64
+	Followees  int64
+	UpdateTime int64
+}
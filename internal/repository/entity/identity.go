@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 登录方式的提供方，和users表上的列对应
+const (
+	ProviderEmail  = "email"
+	ProviderPhone  = "phone"
+	ProviderWeChat = "wechat"
+)
+
+type IdentityEntity interface {
+	FindByUser(ctx context.Context, userId int64) ([]Identity, error)
+	FindBySubject(ctx context.Context, provider string, subject string) (Identity, error)
+	// 绑定新的登录方式，同时更新users表上对应的列
+	Bind(ctx context.Context, i Identity) error
+	// 解绑，同时清空users表上对应的列
+	Unbind(ctx context.Context, userId int64, id int64) error
+}
+
+var (
+	ErrIdentityDuplicate = errors.New("该账号已被绑定")
+	ErrIdentityNotFound  = gorm.ErrRecordNotFound
+)
+
+// 操作identities表的entity
+type identityEntity struct {
+	db *gorm.DB
+}
+
+func NewIdentityEntity(db *gorm.DB) IdentityEntity {
+	return &identityEntity{db: db}
+}
+
+func (entity *identityEntity) FindByUser(ctx context.Context, userId int64) ([]Identity, error) {
+	var res []Identity
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Order("id").Find(&res).Error
+	return res, err
+}
+
+func (entity *identityEntity) FindBySubject(ctx context.Context, provider string, subject string) (Identity, error) {
+	var i Identity
+	err := entity.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&i).Error
+	return i, err
+}
+
+func (entity *identityEntity) Bind(ctx context.Context, i Identity) error {
+	now := time.Now().UnixMilli()
+	i.CreateTime = now
+	i.UpdateTime = now
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&i).Error; err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", i.UserId).
+			Updates(userColumns(i.Provider, i.Subject, now)).Error
+	})
+	if isUniqueConflict(err) {
+		return ErrIdentityDuplicate
+	}
+	return err
+}
+
+func (entity *identityEntity) Unbind(ctx context.Context, userId int64, id int64) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var i Identity
+		if err := tx.Where("id = ? AND user_id = ?", id, userId).First(&i).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&i).Error; err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", userId).
+			Updates(userColumns(i.Provider, "", time.Now().UnixMilli())).Error
+	})
+}
+
+// users表上和登录方式对应的列，subject为空表示清空
+func userColumns(provider string, subject string, now int64) map[string]any {
+	var val any
+	if subject != "" {
+		val = subject
+	}
+	cols := map[string]any{"update_time": now}
+	switch provider {
+	case ProviderEmail:
+		cols["email"] = val
+	case ProviderPhone:
+		cols["phone"] = val
+	case ProviderWeChat:
+		// 这一列不是NULL，用空串表示没有
+		cols["we_chat_open_id"] = subject
+		if subject == "" {
+			cols["we_chat_union_id"] = ""
+		}
+	}
+	return cols
+}
+
+// 注册时根据users表上的列生成登录方式
+func identitiesOf(u User) []Identity {
+	var res []Identity
+	if u.Email.Valid {
+		res = append(res, Identity{Provider: ProviderEmail, Subject: u.Email.String, VerifyTime: u.EmailVerifyTime})
+	}
+	// 手机号和微信都是验证过才能注册的
+	if u.Phone.Valid {
+		res = append(res, Identity{Provider: ProviderPhone, Subject: u.Phone.String, VerifyTime: u.CreateTime})
+	}
+	if u.WeChatOpenId != "" {
+		res = append(res, Identity{Provider: ProviderWeChat, Subject: u.WeChatOpenId, VerifyTime: u.CreateTime})
+	}
+	for i := range res {
+		res[i].UserId = u.Id
+		res[i].CreateTime = u.CreateTime
+		res[i].UpdateTime = u.UpdateTime
+	}
+	return res
+}
+
+func isUniqueConflict(err error) bool {
+	var mySqlErr *mysql.MySQLError
+	// 用的mysql数据库，1062是唯一索引冲突
+	return errors.As(err, &mySqlErr) && mySqlErr.Number == 1062
+}
+
+// 把users表上已有的登录方式补到identities表里，重复执行没有影响
+func InitIdentities(db *gorm.DB) error {
+	return db.Exec(`INSERT IGNORE INTO identities (user_id, provider, subject, verify_time, create_time, update_time)
+SELECT id, ?, email, email_verify_time, create_time, update_time FROM users WHERE email IS NOT NULL
+UNION ALL
+SELECT id, ?, phone, create_time, create_time, update_time FROM users WHERE phone IS NOT NULL
+UNION ALL
+SELECT id, ?, we_chat_open_id, create_time, create_time, update_time FROM users WHERE we_chat_open_id <> ''`,
+		ProviderEmail, ProviderPhone, ProviderWeChat).Error
+}
+
+// 一个用户可以有多种登录方式，同一个登录方式只能属于一个用户
+type Identity struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	UserId   int64  `gorm:"index"`
+	Provider string `gorm:"type:varchar(32);uniqueIndex:idx_provider_subject"`
+	// 邮箱、手机号、微信openId
+	Subject string `gorm:"type:varchar(255);uniqueIndex:idx_provider_subject"`
+	// 验证通过的时间，0表示还没验证
+	VerifyTime int64
+
+	CreateTime int64
+	UpdateTime int64
+}
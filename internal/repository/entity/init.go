@@ -2,8 +2,16 @@ package entity
 
 import "gorm.io/gorm"
 
-// 自动初始化user表
+// 自动初始化表
 func InitTable(db *gorm.DB) error {
-	err := db.AutoMigrate(&User{})
-	return err
+	err := db.AutoMigrate(&User{}, &SecurityEvent{}, &Role{}, &RolePermission{}, &UserRole{}, &PersonalToken{}, &Identity{},
+		&SMS{}, &DataExport{}, &TwoFactor{}, &RecoveryCode{},
+		&FollowRelation{}, &FollowStatistic{}, &AuditLog{}, &LoginRecord{})
+	if err != nil {
+		return err
+	}
+	if err = InitIdentities(db); err != nil {
+		return err
+	}
+	return InitRoles(db)
 }
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type LoginRecordEntity interface {
+	Create(ctx context.Context, r LoginRecord) error
+	// 按id倒序分页，cursor是上一页最后一条的id，0表示第一页
+	FindByUser(ctx context.Context, userId int64, cursor int64, limit int) ([]LoginRecord, error)
+	// 导出个人数据用
+	FindAllByUser(ctx context.Context, userId int64) ([]LoginRecord, error)
+	Search(ctx context.Context, filter LoginRecordFilter, offset int, limit int) ([]LoginRecord, int64, error)
+	// 按保留策略删掉before之前的记录，每次最多limit条，返回删除的条数
+	DeleteBefore(ctx context.Context, before int64, limit int) (int64, error)
+	DeleteByUser(ctx context.Context, userId int64) error
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+// 管理后台查询登录记录的条件，零值的条件不生效
+type LoginRecordFilter struct {
+	UserId      int64
+	Account     string
+	Ip          string
+	Method      string
+	Result      string
+	CreatedFrom int64
+	CreatedTo   int64
+}
+
+// 操作login_records表的entity，只追加，除了按保留策略清理和注销清理不删除
+type loginRecordEntity struct {
+	db *gorm.DB
+}
+
+func NewLoginRecordEntity(db *gorm.DB) LoginRecordEntity {
+	return &loginRecordEntity{db: db}
+}
+
+func (entity *loginRecordEntity) Create(ctx context.Context, r LoginRecord) error {
+	r.CreateTime = time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Create(&r).Error
+}
+
+func (entity *loginRecordEntity) FindByUser(ctx context.Context, userId int64, cursor int64, limit int) ([]LoginRecord, error) {
+	query := entity.db.WithContext(ctx).Where("user_id = ?", userId)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	var records []LoginRecord
+	err := query.Order("id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+func (entity *loginRecordEntity) FindAllByUser(ctx context.Context, userId int64) ([]LoginRecord, error) {
+	var records []LoginRecord
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Order("id DESC").Find(&records).Error
+	return records, err
+}
+
+func (entity *loginRecordEntity) Search(ctx context.Context, filter LoginRecordFilter, offset int, limit int) ([]LoginRecord, int64, error) {
+	query := entity.db.WithContext(ctx).Model(&LoginRecord{})
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.Account != "" {
+		query = query.Where("account = ?", filter.Account)
+	}
+	if filter.Ip != "" {
+		query = query.Where("ip = ?", filter.Ip)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if filter.CreatedFrom > 0 {
+		query = query.Where("create_time >= ?", filter.CreatedFrom)
+	}
+	if filter.CreatedTo > 0 {
+		query = query.Where("create_time < ?", filter.CreatedTo)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var records []LoginRecord
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&records).Error
+	return records, total, err
+}
+
+func (entity *loginRecordEntity) DeleteBefore(ctx context.Context, before int64, limit int) (int64, error) {
+	// gorm的Delete会忽略Limit，先查出一批id再删
+	var ids []int64
+	err := entity.db.WithContext(ctx).Model(&LoginRecord{}).Where("create_time < ?", before).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	res := entity.db.WithContext(ctx).Where("id IN ?", ids).Delete(&LoginRecord{})
+	return res.RowsAffected, res.Error
+}
+
+func (entity *loginRecordEntity) DeleteByUser(ctx context.Context, userId int64) error {
+	return entity.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&LoginRecord{}).Error
+}
+
+func (entity *loginRecordEntity) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	return entity.db.WithContext(ctx).Model(&LoginRecord{}).Where("user_id = ?", fromUserId).
+		Update("user_id", toUserId).Error
+}
+
+// 登录记录
+type LoginRecord struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	UserId int64  `gorm:"index"`
+	Method string `gorm:"type:varchar(32)"`
+	Result string `gorm:"type:varchar(32)"`
+	// 邮箱、手机号，按账号查登录失败用
+	Account string `gorm:"type:varchar(128);index"`
+	Ip      string `gorm:"type:varchar(64);index"`
+	// 浏览器信息
+	UserAgent string `gorm:"type:varchar(512)"`
+	Ssid      string `gorm:"type:varchar(64)"`
+
+	// 按保留策略清理用
+	CreateTime int64 `gorm:"index"`
+}
@@ -1,3 +1,11 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/user.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/user.go -package=entitymocks -destination=./internal/repository/entity/mock/entity.mock.go
+//
+// Package entitymocks is a generated GoMock package.
 package entitymocks
 
 import (
@@ -10,97 +18,287 @@ import (
 
 // MockUserEntity is a mock of UserEntity interface.
 type MockUserEntity struct {
-        ctrl     *gomock.Controller
-        recorder *MockUserEntityMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockUserEntityMockRecorder
 }
 
 // MockUserEntityMockRecorder is the mock recorder for MockUserEntity.
 type MockUserEntityMockRecorder struct {
-        mock *MockUserEntity
+	mock *MockUserEntity
 }
 
 // NewMockUserEntity creates a new mock instance.
 func NewMockUserEntity(ctrl *gomock.Controller) *MockUserEntity {
-        mock := &MockUserEntity{ctrl: ctrl}
-        mock.recorder = &MockUserEntityMockRecorder{mock}
-        return mock
+	mock := &MockUserEntity{ctrl: ctrl}
+	mock.recorder = &MockUserEntityMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockUserEntity) EXPECT() *MockUserEntityMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // Create mocks base method.
 func (m *MockUserEntity) Create(ctx context.Context, u entity.User) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Create", ctx, u)
-        ret0, _ := ret[0].(error)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, u)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Create indicates an expected call of Create.
 func (mr *MockUserEntityMockRecorder) Create(ctx, u any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserEntity)(nil).Create), ctx, u)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserEntity)(nil).Create), ctx, u)
 }
 
 // FindByEmail mocks base method.
 func (m *MockUserEntity) FindByEmail(ctx context.Context, email string) (entity.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
-        ret0, _ := ret[0].(entity.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FindByEmail indicates an expected call of FindByEmail.
 func (mr *MockUserEntityMockRecorder) FindByEmail(ctx, email any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserEntity)(nil).FindByEmail), ctx, email)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserEntity)(nil).FindByEmail), ctx, email)
+}
+
+// FindByHandle mocks base method.
+func (m *MockUserEntity) FindByHandle(ctx context.Context, handle string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHandle", ctx, handle)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHandle indicates an expected call of FindByHandle.
+func (mr *MockUserEntityMockRecorder) FindByHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHandle", reflect.TypeOf((*MockUserEntity)(nil).FindByHandle), ctx, handle)
 }
 
 // FindById mocks base method.
 func (m *MockUserEntity) FindById(ctx context.Context, userId int64) (entity.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "FindById", ctx, userId)
-        ret0, _ := ret[0].(entity.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, userId)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FindById indicates an expected call of FindById.
 func (mr *MockUserEntityMockRecorder) FindById(ctx, userId any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserEntity)(nil).FindById), ctx, userId)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserEntity)(nil).FindById), ctx, userId)
 }
 
 // FindByPhone mocks base method.
 func (m *MockUserEntity) FindByPhone(ctx context.Context, phone string) (entity.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
-        ret0, _ := ret[0].(entity.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FindByPhone indicates an expected call of FindByPhone.
 func (mr *MockUserEntityMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserEntity)(nil).FindByPhone), ctx, phone)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserEntity)(nil).FindByPhone), ctx, phone)
+}
+
+// FindByWeChat mocks base method.
+func (m *MockUserEntity) FindByWeChat(ctx context.Context, openId string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByWeChat", ctx, openId)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByWeChat indicates an expected call of FindByWeChat.
+func (mr *MockUserEntityMockRecorder) FindByWeChat(ctx, openId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWeChat", reflect.TypeOf((*MockUserEntity)(nil).FindByWeChat), ctx, openId)
+}
+
+// FindPurgeable mocks base method.
+func (m *MockUserEntity) FindPurgeable(ctx context.Context, now int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPurgeable", ctx, now, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPurgeable indicates an expected call of FindPurgeable.
+func (mr *MockUserEntityMockRecorder) FindPurgeable(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPurgeable", reflect.TypeOf((*MockUserEntity)(nil).FindPurgeable), ctx, now, limit)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserEntity) MarkEmailVerified(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserEntityMockRecorder) MarkEmailVerified(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserEntity)(nil).MarkEmailVerified), ctx, userId)
+}
+
+// Merge mocks base method.
+func (m *MockUserEntity) Merge(ctx context.Context, targetId, sourceId int64) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, targetId, sourceId)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserEntityMockRecorder) Merge(ctx, targetId, sourceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUserEntity)(nil).Merge), ctx, targetId, sourceId)
+}
+
+// Purge mocks base method.
+func (m *MockUserEntity) Purge(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockUserEntityMockRecorder) Purge(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockUserEntity)(nil).Purge), ctx, userId)
+}
+
+// RequestDeletion mocks base method.
+func (m *MockUserEntity) RequestDeletion(ctx context.Context, userId, purgeAfter int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestDeletion", ctx, userId, purgeAfter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestDeletion indicates an expected call of RequestDeletion.
+func (mr *MockUserEntityMockRecorder) RequestDeletion(ctx, userId, purgeAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestDeletion", reflect.TypeOf((*MockUserEntity)(nil).RequestDeletion), ctx, userId, purgeAfter)
+}
+
+// Restore mocks base method.
+func (m *MockUserEntity) Restore(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserEntityMockRecorder) Restore(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserEntity)(nil).Restore), ctx, userId)
+}
+
+// Search mocks base method.
+func (m *MockUserEntity) Search(ctx context.Context, filter entity.UserFilter, offset, limit int) ([]entity.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUserEntityMockRecorder) Search(ctx, filter, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUserEntity)(nil).Search), ctx, filter, offset, limit)
 }
 
 // Update mocks base method.
 func (m *MockUserEntity) Update(ctx context.Context, userId int64, nickname, description string, birthday int64) (entity.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Update", ctx, userId, nickname, description, birthday)
-        ret0, _ := ret[0].(entity.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, nickname, description, birthday)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
 func (mr *MockUserEntityMockRecorder) Update(ctx, userId, nickname, description, birthday any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserEntity)(nil).Update), ctx, userId, nickname, description, birthday)
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserEntity)(nil).Update), ctx, userId, nickname, description, birthday)
+}
+
+// UpdateAvatar mocks base method.
+func (m *MockUserEntity) UpdateAvatar(ctx context.Context, userId int64, keys, avatar string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvatar", ctx, userId, keys, avatar)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAvatar indicates an expected call of UpdateAvatar.
+func (mr *MockUserEntityMockRecorder) UpdateAvatar(ctx, userId, keys, avatar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvatar", reflect.TypeOf((*MockUserEntity)(nil).UpdateAvatar), ctx, userId, keys, avatar)
+}
+
+// UpdateHandle mocks base method.
+func (m *MockUserEntity) UpdateHandle(ctx context.Context, userId int64, handle string, changedBefore int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHandle", ctx, userId, handle, changedBefore)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHandle indicates an expected call of UpdateHandle.
+func (mr *MockUserEntityMockRecorder) UpdateHandle(ctx, userId, handle, changedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHandle", reflect.TypeOf((*MockUserEntity)(nil).UpdateHandle), ctx, userId, handle, changedBefore)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserEntity) UpdatePassword(ctx context.Context, userId int64, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userId, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserEntityMockRecorder) UpdatePassword(ctx, userId, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserEntity)(nil).UpdatePassword), ctx, userId, hash)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockUserEntity) UpdateStatus(ctx context.Context, userId int64, status uint8, until int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, userId, status, until, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockUserEntityMockRecorder) UpdateStatus(ctx, userId, status, until, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockUserEntity)(nil).UpdateStatus), ctx, userId, status, until, reason)
+}
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/export.go -package=entitymocks -destination=./internal/repository/entity/mock/export.mock.go
+//
+// Package entitymocks is a generated GoMock package.
+package entitymocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportEntity is a mock of DataExportEntity interface.
+type MockDataExportEntity struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportEntityMockRecorder
+}
+
+// MockDataExportEntityMockRecorder is the mock recorder for MockDataExportEntity.
+type MockDataExportEntityMockRecorder struct {
+	mock *MockDataExportEntity
+}
+
+// NewMockDataExportEntity creates a new mock instance.
+func NewMockDataExportEntity(ctrl *gomock.Controller) *MockDataExportEntity {
+	mock := &MockDataExportEntity{ctrl: ctrl}
+	mock.recorder = &MockDataExportEntityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportEntity) EXPECT() *MockDataExportEntityMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDataExportEntity) Create(ctx context.Context, e entity.DataExport) (entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, e)
+	ret0, _ := ret[0].(entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportEntityMockRecorder) Create(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportEntity)(nil).Create), ctx, e)
+}
+
+// Delete mocks base method.
+func (m *MockDataExportEntity) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDataExportEntityMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDataExportEntity)(nil).Delete), ctx, id)
+}
+
+// FindById mocks base method.
+func (m *MockDataExportEntity) FindById(ctx context.Context, id int64) (entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockDataExportEntityMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockDataExportEntity)(nil).FindById), ctx, id)
+}
+
+// FindByUser mocks base method.
+func (m *MockDataExportEntity) FindByUser(ctx context.Context, userId int64) ([]entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockDataExportEntityMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockDataExportEntity)(nil).FindByUser), ctx, userId)
+}
+
+// FindExpired mocks base method.
+func (m *MockDataExportEntity) FindExpired(ctx context.Context, now int64, limit int) ([]entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired.
+func (mr *MockDataExportEntityMockRecorder) FindExpired(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockDataExportEntity)(nil).FindExpired), ctx, now, limit)
+}
+
+// FindLatest mocks base method.
+func (m *MockDataExportEntity) FindLatest(ctx context.Context, userId int64) (entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatest", ctx, userId)
+	ret0, _ := ret[0].(entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatest indicates an expected call of FindLatest.
+func (mr *MockDataExportEntityMockRecorder) FindLatest(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatest", reflect.TypeOf((*MockDataExportEntity)(nil).FindLatest), ctx, userId)
+}
+
+// MarkFailed mocks base method.
+func (m *MockDataExportEntity) MarkFailed(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockDataExportEntityMockRecorder) MarkFailed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockDataExportEntity)(nil).MarkFailed), ctx, id)
+}
+
+// MarkReady mocks base method.
+func (m *MockDataExportEntity) MarkReady(ctx context.Context, id int64, file string, expireTime int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReady", ctx, id, file, expireTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReady indicates an expected call of MarkReady.
+func (mr *MockDataExportEntityMockRecorder) MarkReady(ctx, id, file, expireTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReady", reflect.TypeOf((*MockDataExportEntity)(nil).MarkReady), ctx, id, file, expireTime)
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/follow.go -package=entitymocks -destination=./internal/repository/entity/mock/follow.mock.go
+//
+// Package entitymocks is a generated GoMock package.
+package entitymocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowEntity is a mock of FollowEntity interface.
+type MockFollowEntity struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowEntityMockRecorder
+}
+
+// MockFollowEntityMockRecorder is the mock recorder for MockFollowEntity.
+type MockFollowEntityMockRecorder struct {
+	mock *MockFollowEntity
+}
+
+// NewMockFollowEntity creates a new mock instance.
+func NewMockFollowEntity(ctrl *gomock.Controller) *MockFollowEntity {
+	mock := &MockFollowEntity{ctrl: ctrl}
+	mock.recorder = &MockFollowEntityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowEntity) EXPECT() *MockFollowEntityMockRecorder {
+	return m.recorder
+}
+
+// FindFollowees mocks base method.
+func (m *MockFollowEntity) FindFollowees(ctx context.Context, follower, cursor int64, limit int) ([]entity.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowees", ctx, follower, cursor, limit)
+	ret0, _ := ret[0].([]entity.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowees indicates an expected call of FindFollowees.
+func (mr *MockFollowEntityMockRecorder) FindFollowees(ctx, follower, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowees", reflect.TypeOf((*MockFollowEntity)(nil).FindFollowees), ctx, follower, cursor, limit)
+}
+
+// FindFollowers mocks base method.
+func (m *MockFollowEntity) FindFollowers(ctx context.Context, followee, cursor int64, limit int) ([]entity.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowers", ctx, followee, cursor, limit)
+	ret0, _ := ret[0].([]entity.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowers indicates an expected call of FindFollowers.
+func (mr *MockFollowEntityMockRecorder) FindFollowers(ctx, followee, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowers", reflect.TypeOf((*MockFollowEntity)(nil).FindFollowers), ctx, followee, cursor, limit)
+}
+
+// FindRelation mocks base method.
+func (m *MockFollowEntity) FindRelation(ctx context.Context, follower, followee int64) (entity.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRelation", ctx, follower, followee)
+	ret0, _ := ret[0].(entity.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRelation indicates an expected call of FindRelation.
+func (mr *MockFollowEntityMockRecorder) FindRelation(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRelation", reflect.TypeOf((*MockFollowEntity)(nil).FindRelation), ctx, follower, followee)
+}
+
+// FindStatistic mocks base method.
+func (m *MockFollowEntity) FindStatistic(ctx context.Context, userId int64) (entity.FollowStatistic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStatistic", ctx, userId)
+	ret0, _ := ret[0].(entity.FollowStatistic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStatistic indicates an expected call of FindStatistic.
+func (mr *MockFollowEntityMockRecorder) FindStatistic(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStatistic", reflect.TypeOf((*MockFollowEntity)(nil).FindStatistic), ctx, userId)
+}
+
+// Follow mocks base method.
+func (m *MockFollowEntity) Follow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowEntityMockRecorder) Follow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollowEntity)(nil).Follow), ctx, follower, followee)
+}
+
+// MoveUser mocks base method.
+func (m *MockFollowEntity) MoveUser(ctx context.Context, fromUserId, toUserId int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockFollowEntityMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockFollowEntity)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// RemoveUser mocks base method.
+func (m *MockFollowEntity) RemoveUser(ctx context.Context, userId int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUser", ctx, userId)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUser indicates an expected call of RemoveUser.
+func (mr *MockFollowEntityMockRecorder) RemoveUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUser", reflect.TypeOf((*MockFollowEntity)(nil).RemoveUser), ctx, userId)
+}
+
+// Unfollow mocks base method.
+func (m *MockFollowEntity) Unfollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockFollowEntityMockRecorder) Unfollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockFollowEntity)(nil).Unfollow), ctx, follower, followee)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/identity.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/identity.go -package=entitymocks -destination=./internal/repository/entity/mock/identity.mock.go
+//
+// Package entitymocks is a generated GoMock package.
+package entitymocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityEntity is a mock of IdentityEntity interface.
+type MockIdentityEntity struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityEntityMockRecorder
+}
+
+// MockIdentityEntityMockRecorder is the mock recorder for MockIdentityEntity.
+type MockIdentityEntityMockRecorder struct {
+	mock *MockIdentityEntity
+}
+
+// NewMockIdentityEntity creates a new mock instance.
+func NewMockIdentityEntity(ctrl *gomock.Controller) *MockIdentityEntity {
+	mock := &MockIdentityEntity{ctrl: ctrl}
+	mock.recorder = &MockIdentityEntityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityEntity) EXPECT() *MockIdentityEntityMockRecorder {
+	return m.recorder
+}
+
+// Bind mocks base method.
+func (m *MockIdentityEntity) Bind(ctx context.Context, i entity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bind", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bind indicates an expected call of Bind.
+func (mr *MockIdentityEntityMockRecorder) Bind(ctx, i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bind", reflect.TypeOf((*MockIdentityEntity)(nil).Bind), ctx, i)
+}
+
+// FindBySubject mocks base method.
+func (m *MockIdentityEntity) FindBySubject(ctx context.Context, provider, subject string) (entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySubject", ctx, provider, subject)
+	ret0, _ := ret[0].(entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySubject indicates an expected call of FindBySubject.
+func (mr *MockIdentityEntityMockRecorder) FindBySubject(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySubject", reflect.TypeOf((*MockIdentityEntity)(nil).FindBySubject), ctx, provider, subject)
+}
+
+// FindByUser mocks base method.
+func (m *MockIdentityEntity) FindByUser(ctx context.Context, userId int64) ([]entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockIdentityEntityMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockIdentityEntity)(nil).FindByUser), ctx, userId)
+}
+
+// Unbind mocks base method.
+func (m *MockIdentityEntity) Unbind(ctx context.Context, userId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unbind", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unbind indicates an expected call of Unbind.
+func (mr *MockIdentityEntityMockRecorder) Unbind(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unbind", reflect.TypeOf((*MockIdentityEntity)(nil).Unbind), ctx, userId, id)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/role.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/role.go -package=entitymocks -destination=./internal/repository/entity/mock/role.mock.go
+//
+// Package entitymocks is a generated GoMock package.
+package entitymocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleEntity is a mock of RoleEntity interface.
+type MockRoleEntity struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleEntityMockRecorder
+}
+
+// MockRoleEntityMockRecorder is the mock recorder for MockRoleEntity.
+type MockRoleEntityMockRecorder struct {
+	mock *MockRoleEntity
+}
+
+// NewMockRoleEntity creates a new mock instance.
+func NewMockRoleEntity(ctrl *gomock.Controller) *MockRoleEntity {
+	mock := &MockRoleEntity{ctrl: ctrl}
+	mock.recorder = &MockRoleEntityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleEntity) EXPECT() *MockRoleEntityMockRecorder {
+	return m.recorder
+}
+
+// AssignToUser mocks base method.
+func (m *MockRoleEntity) AssignToUser(ctx context.Context, userId, roleId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignToUser", ctx, userId, roleId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignToUser indicates an expected call of AssignToUser.
+func (mr *MockRoleEntityMockRecorder) AssignToUser(ctx, userId, roleId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignToUser", reflect.TypeOf((*MockRoleEntity)(nil).AssignToUser), ctx, userId, roleId)
+}
+
+// FindByName mocks base method.
+func (m *MockRoleEntity) FindByName(ctx context.Context, name string) (entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, name)
+	ret0, _ := ret[0].(entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockRoleEntityMockRecorder) FindByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockRoleEntity)(nil).FindByName), ctx, name)
+}
+
+// FindByUser mocks base method.
+func (m *MockRoleEntity) FindByUser(ctx context.Context, userId int64) ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockRoleEntityMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockRoleEntity)(nil).FindByUser), ctx, userId)
+}
+
+// FindPermissions mocks base method.
+func (m *MockRoleEntity) FindPermissions(ctx context.Context, roleIds []int64) ([]entity.RolePermission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPermissions", ctx, roleIds)
+	ret0, _ := ret[0].([]entity.RolePermission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPermissions indicates an expected call of FindPermissions.
+func (mr *MockRoleEntityMockRecorder) FindPermissions(ctx, roleIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPermissions", reflect.TypeOf((*MockRoleEntity)(nil).FindPermissions), ctx, roleIds)
+}
+
+// MoveUser mocks base method.
+func (m *MockRoleEntity) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockRoleEntityMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockRoleEntity)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// RevokeFromUser mocks base method.
+func (m *MockRoleEntity) RevokeFromUser(ctx context.Context, userId, roleId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFromUser", ctx, userId, roleId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFromUser indicates an expected call of RevokeFromUser.
+func (mr *MockRoleEntityMockRecorder) RevokeFromUser(ctx, userId, roleId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFromUser", reflect.TypeOf((*MockRoleEntity)(nil).RevokeFromUser), ctx, userId, roleId)
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/entity/token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/entity/token.go -package=entitymocks -destination=./internal/repository/entity/mock/token.mock.go
+//
+// Package entitymocks is a generated GoMock package.
+package entitymocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPersonalTokenEntity is a mock of PersonalTokenEntity interface.
+type MockPersonalTokenEntity struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalTokenEntityMockRecorder
+}
+
+// MockPersonalTokenEntityMockRecorder is the mock recorder for MockPersonalTokenEntity.
+type MockPersonalTokenEntityMockRecorder struct {
+	mock *MockPersonalTokenEntity
+}
+
+// NewMockPersonalTokenEntity creates a new mock instance.
+func NewMockPersonalTokenEntity(ctrl *gomock.Controller) *MockPersonalTokenEntity {
+	mock := &MockPersonalTokenEntity{ctrl: ctrl}
+	mock.recorder = &MockPersonalTokenEntityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalTokenEntity) EXPECT() *MockPersonalTokenEntityMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonalTokenEntity) Create(ctx context.Context, t entity.PersonalToken) (entity.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(entity.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalTokenEntityMockRecorder) Create(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalTokenEntity)(nil).Create), ctx, t)
+}
+
+// Delete mocks base method.
+func (m *MockPersonalTokenEntity) Delete(ctx context.Context, userId, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPersonalTokenEntityMockRecorder) Delete(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPersonalTokenEntity)(nil).Delete), ctx, userId, id)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockPersonalTokenEntity) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockPersonalTokenEntityMockRecorder) DeleteByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockPersonalTokenEntity)(nil).DeleteByUser), ctx, userId)
+}
+
+// FindByHash mocks base method.
+func (m *MockPersonalTokenEntity) FindByHash(ctx context.Context, hash string) (entity.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, hash)
+	ret0, _ := ret[0].(entity.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockPersonalTokenEntityMockRecorder) FindByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockPersonalTokenEntity)(nil).FindByHash), ctx, hash)
+}
+
+// FindByUser mocks base method.
+func (m *MockPersonalTokenEntity) FindByUser(ctx context.Context, userId int64) ([]entity.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]entity.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockPersonalTokenEntityMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockPersonalTokenEntity)(nil).FindByUser), ctx, userId)
+}
+
+// MoveUser mocks base method.
+func (m *MockPersonalTokenEntity) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockPersonalTokenEntityMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockPersonalTokenEntity)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockPersonalTokenEntity) UpdateLastUsed(ctx context.Context, id, lastUsed int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id, lastUsed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockPersonalTokenEntityMockRecorder) UpdateLastUsed(ctx, id, lastUsed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockPersonalTokenEntity)(nil).UpdateLastUsed), ctx, id, lastUsed)
+}
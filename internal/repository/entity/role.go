@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RoleEntity interface {
+	FindByName(ctx context.Context, name string) (Role, error)
+	FindByUser(ctx context.Context, userId int64) ([]Role, error)
+	FindPermissions(ctx context.Context, roleIds []int64) ([]RolePermission, error)
+	AssignToUser(ctx context.Context, userId int64, roleId int64) error
+	RevokeFromUser(ctx context.Context, userId int64, roleId int64) error
+	// 用户合并时把角色挪到另一个用户上，重复的角色只保留一份
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+var ErrRoleNotFound = gorm.ErrRecordNotFound
+
+// 操作roles、role_permissions、user_roles表的entity
+type roleEntity struct {
+	db *gorm.DB
+}
+
+func NewRoleEntity(db *gorm.DB) RoleEntity {
+	return &roleEntity{db: db}
+}
+
+func (entity *roleEntity) FindByName(ctx context.Context, name string) (Role, error) {
+	var r Role
+	err := entity.db.WithContext(ctx).Where("name = ?", name).First(&r).Error
+	return r, err
+}
+
+func (entity *roleEntity) FindByUser(ctx context.Context, userId int64) ([]Role, error) {
+	var roles []Role
+	err := entity.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Find(&roles).Error
+	return roles, err
+}
+
+func (entity *roleEntity) FindPermissions(ctx context.Context, roleIds []int64) ([]RolePermission, error) {
+	var perms []RolePermission
+	if len(roleIds) == 0 {
+		return perms, nil
+	}
+	err := entity.db.WithContext(ctx).Where("role_id IN ?", roleIds).Find(&perms).Error
+	return perms, err
+}
+
+// 已经分配过的忽略
+func (entity *roleEntity) AssignToUser(ctx context.Context, userId int64, roleId int64) error {
+	return entity.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&UserRole{
+		UserId:     userId,
+		RoleId:     roleId,
+		CreateTime: time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *roleEntity) RevokeFromUser(ctx context.Context, userId int64, roleId int64) error {
+	return entity.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userId, roleId).Delete(&UserRole{}).Error
+}
+
+func (entity *roleEntity) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT IGNORE INTO user_roles (user_id, role_id, create_time)
+SELECT ?, role_id, create_time FROM user_roles WHERE user_id = ?`, toUserId, fromUserId).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", fromUserId).Delete(&UserRole{}).Error
+	})
+}
+
+// 初始化内置角色，已经存在的不会覆盖
+func InitRoles(db *gorm.DB) error {
+	builtin := map[string][]string{
+		// 超级管理员拥有所有权限
+		"admin": {"*"},
+	}
+	for name, perms := range builtin {
+		var r Role
+		err := db.Where("name = ?", name).First(&r).Error
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			now := time.Now().UnixMilli()
+			r = Role{Name: name, CreateTime: now, UpdateTime: now}
+			if err := tx.Create(&r).Error; err != nil {
+				return err
+			}
+			for _, p := range perms {
+				if err := tx.Create(&RolePermission{RoleId: r.Id, Permission: p}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 角色表
+type Role struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Name        string `gorm:"type:varchar(64);unique"`
+	Description string `gorm:"size:256"`
+
+	CreateTime int64
+	UpdateTime int64
+}
+
+// 角色拥有的权限
+type RolePermission struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	RoleId     int64  `gorm:"uniqueIndex:idx_role_permission"`
+	Permission string `gorm:"type:varchar(128);uniqueIndex:idx_role_permission"`
+}
+
+// 用户和角色的关系
+type UserRole struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	UserId int64 `gorm:"uniqueIndex:idx_user_role"`
+	RoleId int64 `gorm:"uniqueIndex:idx_user_role"`
+
+	CreateTime int64
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SecurityEventEntity interface {
+	Create(ctx context.Context, e SecurityEvent) error
+	FindByUser(ctx context.Context, userId int64) ([]SecurityEvent, error)
+}
+
+// 操作security_events表的entity，只追加不修改
+type securityEventEntity struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventEntity(db *gorm.DB) SecurityEventEntity {
+	return &securityEventEntity{db: db}
+}
+
+func (entity *securityEventEntity) Create(ctx context.Context, e SecurityEvent) error {
+	e.CreateTime = time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Create(&e).Error
+}
+
+func (entity *securityEventEntity) FindByUser(ctx context.Context, userId int64) ([]SecurityEvent, error) {
+	var events []SecurityEvent
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Order("id DESC").Find(&events).Error
+	return events, err
+}
+
+// 安全事件表结构
+type SecurityEvent struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	UserId int64  `gorm:"index"`
+	Type   string `gorm:"type:varchar(64)"`
+	Ssid   string `gorm:"type:varchar(64)"`
+	Ip     string `gorm:"type:varchar(64)"`
+	// 浏览器信息
+	UserAgent string `gorm:"type:varchar(512)"`
+	Detail    string `gorm:"type:varchar(1024)"`
+
+	CreateTime int64
+}
@@ -6,12 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"slices"
+	"strings"
 )
 
 type SMSEntity interface {
 	Store(ctx context.Context, tplId string, args []string, numbers []string) error
 	FindRetryJob() ([]SMS, error)
 	SetJobSuccess(ctx context.Context, id int64) error
+	// 发给这个手机号的短信记录
+	FindByNumber(ctx context.Context, number string) ([]SMS, error)
+	// 从记录里去掉这个手机号，只发给它的记录直接删掉
+	RemoveNumber(ctx context.Context, number string) error
 }
 
 type smsEntity struct {
@@ -38,6 +44,38 @@ func (s *smsEntity) SetJobSuccess(ctx context.Context, id int64) error {
 	return s.db.Model(&SMS{}).WithContext(ctx).Where("id = ?", id).Update("status", RETRY_SUCCESS).Error
 }
 
+func (s *smsEntity) FindByNumber(ctx context.Context, number string) ([]SMS, error) {
+	var sms []SMS
+	// numbers是逗号分隔的，两边补上逗号再匹配，避免匹配到别的号码的一部分
+	err := s.db.WithContext(ctx).Where("CONCAT(',', numbers, ',') LIKE ?", "%,"+number+",%").
+		Order("id DESC").Find(&sms).Error
+	return sms, err
+}
+
+// 群发的记录还要给别的号码重试，只去掉这个号码
+func (s *smsEntity) RemoveNumber(ctx context.Context, number string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var records []SMS
+		err := tx.Where("CONCAT(',', numbers, ',') LIKE ?", "%,"+number+",%").Find(&records).Error
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			numbers := slices.DeleteFunc(r.Numbers, func(n string) bool { return n == number })
+			if len(numbers) == 0 {
+				err = tx.Where("id = ?", r.Id).Delete(&SMS{}).Error
+			} else {
+				// 不走BeforeSave，只改numbers
+				err = tx.Model(&SMS{}).Where("id = ?", r.Id).UpdateColumn("numbers", strings.Join(numbers, ",")).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func NewSMSEntity(db *gorm.DB) SMSEntity {
 	return &smsEntity{db: db}
 }
@@ -75,3 +113,21 @@ type SMS struct {
 	// 枚举类型
 	Status RetryStatus `gorm:"type:int"`
 }
+
+// Args和Numbers不落库，写入前拼成逗号分隔的字符串
+func (s *SMS) BeforeSave(tx *gorm.DB) error {
+	s.ArgsDB = strings.Join(s.Args, ",")
+	s.NumbersDB = strings.Join(s.Numbers, ",")
+	return nil
+}
+
+// 读出来之后再拆开
+func (s *SMS) AfterFind(tx *gorm.DB) error {
+	if s.ArgsDB != "" {
+		s.Args = strings.Split(s.ArgsDB, ",")
+	}
+	if s.NumbersDB != "" {
+		s.Numbers = strings.Split(s.NumbersDB, ",")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PersonalTokenEntity interface {
+	Create(ctx context.Context, t PersonalToken) (PersonalToken, error)
+	FindByHash(ctx context.Context, hash string) (PersonalToken, error)
+	FindByUser(ctx context.Context, userId int64) ([]PersonalToken, error)
+	// 只能删除自己的令牌，返回是否删除成功
+	Delete(ctx context.Context, userId int64, id int64) (bool, error)
+	UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error
+	// 删除用户所有的令牌，返回删除的个数
+	DeleteByUser(ctx context.Context, userId int64) (int64, error)
+	// 用户合并时把令牌挪到另一个用户上
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+var ErrPersonalTokenNotFound = gorm.ErrRecordNotFound
+
+// 操作personal_tokens表的entity
+type personalTokenEntity struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenEntity(db *gorm.DB) PersonalTokenEntity {
+	return &personalTokenEntity{db: db}
+}
+
+func (entity *personalTokenEntity) Create(ctx context.Context, t PersonalToken) (PersonalToken, error) {
+	now := time.Now().UnixMilli()
+	t.CreateTime = now
+	t.UpdateTime = now
+	err := entity.db.WithContext(ctx).Create(&t).Error
+	return t, err
+}
+
+func (entity *personalTokenEntity) FindByHash(ctx context.Context, hash string) (PersonalToken, error) {
+	var t PersonalToken
+	err := entity.db.WithContext(ctx).Where("hash = ?", hash).First(&t).Error
+	return t, err
+}
+
+func (entity *personalTokenEntity) FindByUser(ctx context.Context, userId int64) ([]PersonalToken, error) {
+	var tokens []PersonalToken
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (entity *personalTokenEntity) Delete(ctx context.Context, userId int64, id int64) (bool, error) {
+	res := entity.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userId).Delete(&PersonalToken{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (entity *personalTokenEntity) UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error {
+	return entity.db.WithContext(ctx).Model(&PersonalToken{}).Where("id = ?", id).Updates(map[string]any{
+		"last_used_time": lastUsed,
+		"update_time":    time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *personalTokenEntity) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	res := entity.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&PersonalToken{})
+	return res.RowsAffected, res.Error
+}
+
+func (entity *personalTokenEntity) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	return entity.db.WithContext(ctx).Model(&PersonalToken{}).Where("user_id = ?", fromUserId).Updates(map[string]any{
+		"user_id":     toUserId,
+		"update_time": time.Now().UnixMilli(),
+	}).Error
+}
+
+// 个人访问令牌表结构，只存明文的sha256
+type PersonalToken struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	UserId int64  `gorm:"index"`
+	Name   string `gorm:"type:varchar(128)"`
+	Hash   string `gorm:"type:char(64);uniqueIndex"`
+	Prefix string `gorm:"type:varchar(16)"`
+	// 逗号分隔
+	Scopes string `gorm:"type:varchar(512)"`
+
+	LastUsedTime int64
+	ExpireTime   int64
+	CreateTime   int64
+	UpdateTime   int64
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TwoFactorEntity interface {
+	FindByUser(ctx context.Context, userId int64) (TwoFactor, error)
+	// 开始绑定，已经启用的不能覆盖
+	SavePending(ctx context.Context, userId int64, secret string) error
+	// 确认绑定，同时写入新的恢复码
+	Enable(ctx context.Context, userId int64, step int64, codeHashes []string) error
+	// 关闭两步验证，恢复码一起删掉
+	Disable(ctx context.Context, userId int64) error
+	// 时间片比上次用的大才算成功，防止验证码重放
+	UseStep(ctx context.Context, userId int64, step int64) (bool, error)
+	// 恢复码只能用一次
+	UseRecoveryCode(ctx context.Context, userId int64, codeHash string) (bool, error)
+	// 作废所有旧的恢复码，换成新的
+	ReplaceRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error
+}
+
+var (
+	ErrTwoFactorNotFound = gorm.ErrRecordNotFound
+	ErrTwoFactorEnabled  = errors.New("两步验证已经启用")
+)
+
+// 操作two_factors和recovery_codes表的entity
+type twoFactorEntity struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorEntity(db *gorm.DB) TwoFactorEntity {
+	return &twoFactorEntity{db: db}
+}
+
+func (entity *twoFactorEntity) FindByUser(ctx context.Context, userId int64) (TwoFactor, error) {
+	var t TwoFactor
+	err := entity.db.WithContext(ctx).Where("user_id = ?", userId).First(&t).Error
+	return t, err
+}
+
+func (entity *twoFactorEntity) SavePending(ctx context.Context, userId int64, secret string) error {
+	now := time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var t TwoFactor
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(&t).Error
+		switch {
+		case err == ErrTwoFactorNotFound:
+			return tx.Create(&TwoFactor{UserId: userId, Secret: secret, CreateTime: now, UpdateTime: now}).Error
+		case err != nil:
+			return err
+		case t.EnableTime > 0:
+			return ErrTwoFactorEnabled
+		}
+		// 重新绑定，换一个密钥
+		return tx.Model(&t).Updates(map[string]any{
+			"secret":      secret,
+			"update_time": now,
+		}).Error
+	})
+}
+
+func (entity *twoFactorEntity) Enable(ctx context.Context, userId int64, step int64, codeHashes []string) error {
+	now := time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&TwoFactor{}).Where("user_id = ? AND enable_time = 0", userId).Updates(map[string]any{
+			"enable_time":    now,
+			"last_used_step": step,
+			"update_time":    now,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTwoFactorEnabled
+		}
+		return replaceRecoveryCodes(tx, userId, codeHashes, now)
+	})
+}
+
+func (entity *twoFactorEntity) Disable(ctx context.Context, userId int64) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&TwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&RecoveryCode{}).Error
+	})
+}
+
+func (entity *twoFactorEntity) UseStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	res := entity.db.WithContext(ctx).Model(&TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).Updates(map[string]any{
+		"last_used_step": step,
+		"update_time":    time.Now().UnixMilli(),
+	})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (entity *twoFactorEntity) UseRecoveryCode(ctx context.Context, userId int64, codeHash string) (bool, error) {
+	res := entity.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND hash = ? AND used_time = 0", userId, codeHash).
+		Update("used_time", time.Now().UnixMilli())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (entity *twoFactorEntity) ReplaceRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error {
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userId, codeHashes, time.Now().UnixMilli())
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userId int64, codeHashes []string, now int64) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, RecoveryCode{UserId: userId, Hash: hash, CreateTime: now})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// 两步验证表结构，认证器要用密钥算验证码，只能存明文
+type TwoFactor struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	UserId int64  `gorm:"uniqueIndex"`
+	Secret string `gorm:"type:varchar(64)"`
+	// 0表示还没确认
+	EnableTime   int64
+	LastUsedStep int64
+	CreateTime   int64
+	UpdateTime   int64
+}
+
+// 一次性恢复码，只存sha256
+type RecoveryCode struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	UserId     int64  `gorm:"index"`
+	Hash       string `gorm:"type:char(64)"`
+	UsedTime   int64
+	CreateTime int64
+}
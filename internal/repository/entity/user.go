@@ -4,8 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
 	"time"
 )
 
@@ -16,13 +17,47 @@ type UserEntity interface {
 	Update(ctx context.Context, userId int64, nickname string, description string, birthday int64) (User, error)
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindByWeChat(ctx context.Context, openId string) (User, error)
+	UpdatePassword(ctx context.Context, userId int64, hash string) error
+	MarkEmailVerified(ctx context.Context, userId int64) error
+	FindByHandle(ctx context.Context, handle string) (User, error)
+	// 设置用户名，changedBefore之后改过的不能再改，返回旧的用户名
+	UpdateHandle(ctx context.Context, userId int64, handle string, changedBefore int64) (string, error)
+	// 换头像，返回旧头像在存储里的key，调用方负责删掉
+	UpdateAvatar(ctx context.Context, userId int64, keys string, avatar string) (string, error)
+	// 把source合并到target上，返回合并后的target
+	Merge(ctx context.Context, targetId int64, sourceId int64) (User, error)
+	// 管理后台分页查询，不包括合并掉的墓碑，按id倒序
+	Search(ctx context.Context, filter UserFilter, offset int, limit int) ([]User, int64, error)
+	// 暂停使用时until是截止时间，其他状态传0
+	UpdateStatus(ctx context.Context, userId int64, status uint8, until int64, reason string) error
+
+	// 申请注销，purgeAfter之前登录可以恢复
+	RequestDeletion(ctx context.Context, userId int64, purgeAfter int64) error
+	Restore(ctx context.Context, userId int64) error
+	// 过了恢复期、还没清理的用户
+	FindPurgeable(ctx context.Context, now int64, limit int) ([]int64, error)
+	// 抹掉用户的个人信息，只留下id
+	Purge(ctx context.Context, userId int64) error
 }
 
 var (
 	ErrUserDuplciate = errors.New("用户已注册")
 	ErrUserNotFound  = gorm.ErrRecordNotFound
+	ErrUserMerged    = errors.New("用户已经被合并")
+	ErrUserBanned    = errors.New("用户已被封禁")
+	ErrMergeConflict = errors.New("两个用户绑定了同一类登录方式")
+	ErrHandleTaken   = errors.New("用户名已被占用")
+	ErrHandleTooSoon = errors.New("用户名修改太频繁")
 )
 
+// 管理后台查询用户的条件，零值的条件不生效
+type UserFilter struct {
+	Email       string
+	Phone       string
+	CreatedFrom int64
+	CreatedTo   int64
+}
+
 // 操作User表的entity
 type userEntity struct {
 	db *gorm.DB
@@ -39,14 +74,20 @@ func (entity *userEntity) Create(ctx context.Context, u User) error {
 	now := time.Now().UnixMilli()
 	u.CreateTime = now
 	u.UpdateTime = now
-	err := entity.db.WithContext(ctx).Create(&u).Error
-	if mySqlErr, ok := err.(*mysql.MySQLError); ok {
-		// 用的mysql数据库，断言成mysqlerror
-		const uniqueConflictErrNum uint16 = 1062
-		if mySqlErr.Number == uniqueConflictErrNum {
-			// 唯一索引异常
-			return ErrUserDuplciate
+	// 用户和登录方式一起写入
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&u).Error; err != nil {
+			return err
 		}
+		identities := identitiesOf(u)
+		if len(identities) == 0 {
+			return nil
+		}
+		return tx.Create(&identities).Error
+	})
+	if isUniqueConflict(err) {
+		// 唯一索引异常
+		return ErrUserDuplciate
 	}
 	return err
 }
@@ -87,16 +128,350 @@ func (entity *userEntity) Update(ctx context.Context, userId int64, nickname str
 	return user, err
 }
 
+// 手机号和微信都以identities表为准
 func (entity *userEntity) FindByPhone(ctx context.Context, phone string) (User, error) {
-	var u User
-	result := entity.db.WithContext(ctx).Where("phone = ?", phone).First(&u)
-	return u, result.Error
+	return entity.findByIdentity(ctx, ProviderPhone, phone)
 }
 
 func (entity *userEntity) FindByWeChat(ctx context.Context, openId string) (User, error) {
+	return entity.findByIdentity(ctx, ProviderWeChat, openId)
+}
+
+func (entity *userEntity) findByIdentity(ctx context.Context, provider string, subject string) (User, error) {
 	var u User
-	result := entity.db.WithContext(ctx).Where("openId = ?", openId).First(&u)
-	return u, result.Error
+	err := entity.db.WithContext(ctx).
+		Joins("JOIN identities ON identities.user_id = users.id").
+		Where("identities.provider = ? AND identities.subject = ?", provider, subject).
+		First(&u).Error
+	return u, err
+}
+
+func (entity *userEntity) UpdatePassword(ctx context.Context, userId int64, hash string) error {
+	return entity.db.WithContext(ctx).Model(&User{}).Where("id = ?", userId).Updates(map[string]any{
+		"password":    hash,
+		"update_time": time.Now().UnixMilli(),
+	}).Error
+}
+
+func (entity *userEntity) FindByHandle(ctx context.Context, handle string) (User, error) {
+	var u User
+	err := entity.db.WithContext(ctx).Where("handle = ?", handle).First(&u).Error
+	return u, err
+}
+
+func (entity *userEntity) UpdateHandle(ctx context.Context, userId int64, handle string, changedBefore int64) (string, error) {
+	var old User
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "handle", "handle_change_time").First(&old, userId).Error
+		if err != nil {
+			return err
+		}
+		if old.Handle.String == handle {
+			return nil
+		}
+		// 第一次设置不受限制
+		if old.Handle.Valid && old.HandleChangeTime > changedBefore {
+			return ErrHandleTooSoon
+		}
+		now := time.Now().UnixMilli()
+		return tx.Model(&User{}).Where("id = ?", userId).Updates(map[string]any{
+			"handle":             handle,
+			"handle_change_time": now,
+			"update_time":        now,
+		}).Error
+	})
+	if isUniqueConflict(err) {
+		return "", ErrHandleTaken
+	}
+	return old.Handle.String, err
+}
+
+func (entity *userEntity) Search(ctx context.Context, filter UserFilter, offset int, limit int) ([]User, int64, error) {
+	query := entity.db.WithContext(ctx).Model(&User{}).Where("merged_into = 0")
+	if filter.Email != "" {
+		query = query.Where("email LIKE ?", escapeLike(filter.Email)+"%")
+	}
+	if filter.Phone != "" {
+		query = query.Where("phone LIKE ?", escapeLike(filter.Phone)+"%")
+	}
+	if filter.CreatedFrom > 0 {
+		query = query.Where("create_time >= ?", filter.CreatedFrom)
+	}
+	if filter.CreatedTo > 0 {
+		query = query.Where("create_time < ?", filter.CreatedTo)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []User
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// 用户输入的%和_当作普通字符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (entity *userEntity) UpdateStatus(ctx context.Context, userId int64, status uint8, until int64, reason string) error {
+	res := entity.db.WithContext(ctx).Model(&User{}).Where("id = ? AND merged_into = 0", userId).Updates(map[string]any{
+		"status":        status,
+		"suspend_until": until,
+		"status_reason": reason,
+		"update_time":   time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (entity *userEntity) UpdateAvatar(ctx context.Context, userId int64, keys string, avatar string) (string, error) {
+	var old User
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "avatar_keys").First(&old, userId).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", userId).Updates(map[string]any{
+			"avatar_keys": keys,
+			"avatar":      avatar,
+			"update_time": time.Now().UnixMilli(),
+		}).Error
+	})
+	return old.AvatarKeys, err
+}
+
+func (entity *userEntity) MarkEmailVerified(ctx context.Context, userId int64) error {
+	now := time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&User{}).Where("id = ?", userId).Updates(map[string]any{
+			"email_verify_time": now,
+			"update_time":       now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Identity{}).Where("user_id = ? AND provider = ?", userId, ProviderEmail).Updates(map[string]any{
+			"verify_time": now,
+			"update_time": now,
+		}).Error
+	})
+}
+
+// 登录方式从source挪到target，target上空着的资料用source的补上，source只留一个墓碑
+func (entity *userEntity) Merge(ctx context.Context, targetId int64, sourceId int64) (User, error) {
+	var target User
+	err := entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁住两个用户，防止同时合并、绑定
+		var users []User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []int64{targetId, sourceId}).Find(&users).Error
+		if err != nil {
+			return err
+		}
+		if len(users) != 2 {
+			return ErrUserNotFound
+		}
+		source := users[0]
+		target = users[1]
+		if source.Id != sourceId {
+			source, target = target, source
+		}
+		if source.MergedInto != 0 || target.MergedInto != 0 {
+			return ErrUserMerged
+		}
+		now := time.Now().UnixMilli()
+		// 注销中的账号先登录恢复再合并
+		if source.DeleteTime != 0 || target.DeleteTime != 0 {
+			return ErrUserNotFound
+		}
+		// 封禁中的账号不能把登录方式、令牌挪到别的账号上绕过封禁
+		// status不为0就是封禁或者暂停使用，暂停的截止时间过了当作正常
+		if source.Status != 0 && (source.SuspendUntil == 0 || now < source.SuspendUntil) {
+			return ErrUserBanned
+		}
+
+		// users表上每种登录方式只有一列，同类的登录方式没法合并
+		var identities []Identity
+		err = tx.Where("user_id IN ?", []int64{targetId, sourceId}).Find(&identities).Error
+		if err != nil {
+			return err
+		}
+		owners := make(map[string]int64, len(identities))
+		for _, i := range identities {
+			if owner, ok := owners[i.Provider]; ok && owner != i.UserId {
+				return ErrMergeConflict
+			}
+			owners[i.Provider] = i.UserId
+		}
+
+		err = tx.Model(&Identity{}).Where("user_id = ?", sourceId).Updates(map[string]any{
+			"user_id":     targetId,
+			"update_time": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		// 先让出唯一索引的列，墓碑不能再登录
+		err = tx.Model(&User{}).Where("id = ?", sourceId).Updates(map[string]any{
+			"email":            nil,
+			"phone":            nil,
+			"password":         "",
+			"we_chat_open_id":  "",
+			"we_chat_union_id": "",
+			"handle":           nil,
+			"merged_into":      targetId,
+			"update_time":      now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		cols := mergedColumns(target, source)
+		cols["update_time"] = now
+		if err = tx.Model(&User{}).Where("id = ?", targetId).Updates(cols).Error; err != nil {
+			return err
+		}
+		return tx.First(&target, targetId).Error
+	})
+	return target, err
+}
+
+// target上没有的资料和登录方式，从source上拿
+func mergedColumns(target User, source User) map[string]any {
+	cols := map[string]any{}
+	if !target.Email.Valid && source.Email.Valid {
+		cols["email"] = source.Email
+		cols["email_verify_time"] = source.EmailVerifyTime
+	}
+	// 密码是配合邮箱用的
+	if target.Password == "" && source.Password != "" {
+		cols["password"] = source.Password
+	}
+	if !target.Phone.Valid && source.Phone.Valid {
+		cols["phone"] = source.Phone
+	}
+	if target.WeChatOpenId == "" && source.WeChatOpenId != "" {
+		cols["we_chat_open_id"] = source.WeChatOpenId
+		cols["we_chat_union_id"] = source.WeChatUnionId
+	}
+	if target.Nickname == "" {
+		cols["nickname"] = source.Nickname
+	}
+	if target.Birthday == 0 {
+		cols["birthday"] = source.Birthday
+	}
+	if target.Description == "" {
+		cols["description"] = source.Description
+	}
+	if !target.Handle.Valid && source.Handle.Valid {
+		cols["handle"] = source.Handle
+		cols["handle_change_time"] = source.HandleChangeTime
+	}
+	if target.Avatar == "" && source.Avatar != "" {
+		cols["avatar"] = source.Avatar
+		cols["avatar_keys"] = source.AvatarKeys
+	}
+	return cols
+}
+
+// 个人访问令牌不是会话，申请注销时直接删掉，恢复之后需要重新创建
+func (entity *userEntity) RequestDeletion(ctx context.Context, userId int64, purgeAfter int64) error {
+	now := time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&User{}).Where("id = ? AND delete_time = 0 AND merged_into = 0", userId).Updates(map[string]any{
+			"delete_time":      now,
+			"purge_after_time": purgeAfter,
+			"update_time":      now,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return tx.Where("user_id = ?", userId).Delete(&PersonalToken{}).Error
+	})
+}
+
+// 已经清理过的恢复不了
+func (entity *userEntity) Restore(ctx context.Context, userId int64) error {
+	res := entity.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND delete_time > 0 AND purge_time = 0", userId).Updates(map[string]any{
+		"delete_time":      0,
+		"purge_after_time": 0,
+		"update_time":      time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (entity *userEntity) FindPurgeable(ctx context.Context, now int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := entity.db.WithContext(ctx).Model(&User{}).
+		Where("delete_time > 0 AND purge_time = 0 AND purge_after_time <= ?", now).
+		Order("purge_after_time").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// 用户行保留下来，其他表靠它的id关联，个人信息全部抹掉
+func (entity *userEntity) Purge(ctx context.Context, userId int64) error {
+	now := time.Now().UnixMilli()
+	return entity.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&User{}).Where("id = ? AND delete_time > 0 AND purge_time = 0", userId).Updates(map[string]any{
+			"email":             nil,
+			"password":          "",
+			"email_verify_time": 0,
+			"nickname":          "",
+			"birthday":          0,
+			"description":       "",
+			"avatar":            "",
+			"avatar_keys":       "",
+			"handle":            nil,
+			"phone":             nil,
+			"we_chat_open_id":   "",
+			"we_chat_union_id":  "",
+			"purge_time":        now,
+			"update_time":       now,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		// 并发恢复了，或者已经清理过
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&Identity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&PersonalToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&TwoFactor{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		// 安全事件只追加，这里是唯一的例外：抹掉ip和设备信息
+		return tx.Model(&SecurityEvent{}).Where("user_id = ?", userId).Updates(map[string]any{
+			"ip":         "",
+			"user_agent": "",
+		}).Error
+	})
 }
 
 // user表结构
@@ -105,10 +480,21 @@ type User struct {
 	// NullString的scan方法从数据库中读取的值，转换成go中的值;
 	Email    sql.NullString `gorm:"unique"`
 	Password string
+	// 邮箱验证通过的时间，0表示还没验证
+	EmailVerifyTime int64
 
 	Nickname    string
 	Birthday    int64
 	Description string `gorm:"size:350"`
+	// 公开主页的用户名，统一存小写
+	Handle sql.NullString `gorm:"type:varchar(32);unique"`
+	// 上一次修改用户名的时间，用来限制修改频率
+	HandleChangeTime int64
+
+	// 各个尺寸头像的访问地址，json
+	Avatar string `gorm:"type:varchar(2048)"`
+	// 头像文件在存储里的key，逗号分隔，换头像的时候删掉旧的
+	AvatarKeys string `gorm:"type:varchar(1024)"`
 
 	// 手机号
 	Phone sql.NullString `gorm:"unique"`
@@ -117,6 +503,22 @@ type User struct {
 	WeChatOpenId  string `gorm:"type=varchar(1024),unique"`
 	WeChatUnionId string `gorm:"type=varchar(1024)"`
 
+	// 账号状态，0正常，1永久封禁，2暂停使用
+	Status uint8
+	// 暂停使用的截止时间
+	SuspendUntil int64
+	StatusReason string `gorm:"type:varchar(256)"`
+
+	// 被合并到了哪个用户，不为0表示这是一个墓碑
+	MergedInto int64 `gorm:"index"`
+
+	// 申请注销的时间，0表示正常
+	DeleteTime int64
+	// 恢复期的截止时间，过了之后清理个人信息
+	PurgeAfterTime int64 `gorm:"index"`
+	// 个人信息被清理的时间
+	PurgeTime int64
+
 	// 为了便于处理时间，时间统一用UTC+0下的时间戳
 	CreateTime int64
 	UpdateTime int64
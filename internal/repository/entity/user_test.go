@@ -9,6 +9,7 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 func TestUserEntity_Create(t *testing.T) {
@@ -30,7 +31,9 @@ func TestUserEntity_Create(t *testing.T) {
 				assert.Equal(t, err, nil)
 				// 对于创建、删除、改，主要是rowsAffteced和lastinsertid
 				mockRes := sqlmock.NewResult(int64(1), 1)
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `users` .*").WillReturnResult(mockRes)
+				mock.ExpectCommit()
 				return db
 			},
 
@@ -38,13 +41,31 @@ func TestUserEntity_Create(t *testing.T) {
 			user:      User{},
 			wantError: nil,
 		},
+		{
+			name: "创建用户同时写入登录方式",
+			sqlmock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				assert.Equal(t, err, nil)
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `users` .*").WillReturnResult(sqlmock.NewResult(int64(1), 1))
+				mock.ExpectExec("INSERT INTO `identities` .*").WillReturnResult(sqlmock.NewResult(int64(1), 1))
+				mock.ExpectCommit()
+				return db
+			},
+
+			ctx:       context.Background(),
+			user:      User{Phone: sql.NullString{String: "13800000000", Valid: true}},
+			wantError: nil,
+		},
 		{
 			name: "创建用户重复",
 			sqlmock: func(t *testing.T) *sql.DB {
 				db, mock, err := sqlmock.New()
 				assert.Equal(t, err, nil)
 				// 唯一索引冲突的error
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `users` .*").WillReturnError(&mysql2.MySQLError{Number: 1062})
+				mock.ExpectRollback()
 				return db
 			},
 
@@ -75,3 +96,56 @@ func TestUserEntity_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestUserEntity_Merge(t *testing.T) {
+	testCases := []struct {
+		name    string
+		sqlmock func(t *testing.T) *sql.DB
+
+		wantError error
+	}{
+		{
+			name: "被合并的账号封禁中",
+			sqlmock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				assert.Equal(t, err, nil)
+				mock.ExpectBegin()
+				rows := sqlmock.NewRows([]string{"id", "status", "suspend_until"}).
+					AddRow(1, 0, 0).AddRow(2, 1, 0)
+				mock.ExpectQuery("SELECT .* FROM `users` .* FOR UPDATE").WillReturnRows(rows)
+				mock.ExpectRollback()
+				return db
+			},
+			wantError: ErrUserBanned,
+		},
+		{
+			name: "被合并的账号暂停使用中",
+			sqlmock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				assert.Equal(t, err, nil)
+				mock.ExpectBegin()
+				rows := sqlmock.NewRows([]string{"id", "status", "suspend_until"}).
+					AddRow(1, 0, 0).AddRow(2, 2, time.Now().Add(time.Hour).UnixMilli())
+				mock.ExpectQuery("SELECT .* FROM `users` .* FOR UPDATE").WillReturnRows(rows)
+				mock.ExpectRollback()
+				return db
+			},
+			wantError: ErrUserBanned,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.sqlmock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				DisableAutomaticPing:   true,
+				SkipDefaultTransaction: true,
+			})
+			assert.Equal(t, err, nil)
+			_, err = NewUserEntity(db).Merge(context.Background(), 1, 2)
+			assert.Equal(t, err, tc.wantError)
+		})
+	}
+}
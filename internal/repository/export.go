@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/entity"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, userId int64) (domain.DataExport, error)
+	FindById(ctx context.Context, id int64) (domain.DataExport, error)
+	FindLatest(ctx context.Context, userId int64) (domain.DataExport, error)
+	MarkReady(ctx context.Context, id int64, file string, expireAt int64) error
+	MarkFailed(ctx context.Context, id int64) error
+	FindExpired(ctx context.Context, now int64, limit int) ([]domain.DataExport, error)
+	FindByUser(ctx context.Context, userId int64) ([]domain.DataExport, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+var ErrDataExportNotFound = entity.ErrDataExportNotFound
+
+type dataExportRepository struct {
+	entity entity.DataExportEntity
+}
+
+func NewDataExportRepository(entity entity.DataExportEntity) DataExportRepository {
+	return &dataExportRepository{entity: entity}
+}
+
+func (repo *dataExportRepository) Create(ctx context.Context, userId int64) (domain.DataExport, error) {
+	e, err := repo.entity.Create(ctx, entity.DataExport{UserId: userId, Status: entity.DataExportPending})
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *dataExportRepository) FindById(ctx context.Context, id int64) (domain.DataExport, error) {
+	e, err := repo.entity.FindById(ctx, id)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *dataExportRepository) FindLatest(ctx context.Context, userId int64) (domain.DataExport, error) {
+	e, err := repo.entity.FindLatest(ctx, userId)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *dataExportRepository) MarkReady(ctx context.Context, id int64, file string, expireAt int64) error {
+	return repo.entity.MarkReady(ctx, id, file, expireAt)
+}
+
+func (repo *dataExportRepository) MarkFailed(ctx context.Context, id int64) error {
+	return repo.entity.MarkFailed(ctx, id)
+}
+
+func (repo *dataExportRepository) FindExpired(ctx context.Context, now int64, limit int) ([]domain.DataExport, error) {
+	es, err := repo.entity.FindExpired(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return repo.entitiesToDomain(es), nil
+}
+
+func (repo *dataExportRepository) FindByUser(ctx context.Context, userId int64) ([]domain.DataExport, error) {
+	es, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return repo.entitiesToDomain(es), nil
+}
+
+func (repo *dataExportRepository) Delete(ctx context.Context, id int64) error {
+	return repo.entity.Delete(ctx, id)
+}
+
+func (repo *dataExportRepository) entitiesToDomain(es []entity.DataExport) []domain.DataExport {
+	res := make([]domain.DataExport, 0, len(es))
+	for _, e := range es {
+		res = append(res, repo.entityToDomain(e))
+	}
+	return res
+}
+
+func (repo *dataExportRepository) entityToDomain(e entity.DataExport) domain.DataExport {
+	return domain.DataExport{
+		Id:       e.Id,
+		UserId:   e.UserId,
+		Status:   e.Status,
+		File:     e.File,
+		ExpireAt: e.ExpireTime,
+		CreateAt: e.CreateTime,
+	}
+}
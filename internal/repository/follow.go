@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/entity"
+)
+
+type FollowRepository interface {
+	Follow(ctx context.Context, follower int64, followee int64) error
+	Unfollow(ctx context.Context, follower int64, followee int64) error
+	FindFollowers(ctx context.Context, followee int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	FindFollowees(ctx context.Context, follower int64, cursor int64, limit int) ([]domain.FollowRelation, error)
+	// 是否已经关注
+	Following(ctx context.Context, follower int64, followee int64) (bool, error)
+	// 走缓存
+	FindStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error)
+	RemoveUser(ctx context.Context, userId int64) error
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+var (
+	ErrFollowExists   = entity.ErrFollowExists
+	ErrFollowNotFound = entity.ErrFollowNotFound
+)
+
+type followRepository struct {
+	entity entity.FollowEntity
+	cache  cache.FollowCache
+}
+
+func NewFollowRepository(entity entity.FollowEntity, cache cache.FollowCache) FollowRepository {
+	return &followRepository{entity: entity, cache: cache}
+}
+
+// 计数改完数据库删缓存，两边的计数都变了
+func (repo *followRepository) Follow(ctx context.Context, follower int64, followee int64) error {
+	if err := repo.entity.Follow(ctx, follower, followee); err != nil {
+		return err
+	}
+	return repo.cache.DelStatistic(ctx, follower, followee)
+}
+
+func (repo *followRepository) Unfollow(ctx context.Context, follower int64, followee int64) error {
+	if err := repo.entity.Unfollow(ctx, follower, followee); err != nil {
+		return err
+	}
+	return repo.cache.DelStatistic(ctx, follower, followee)
+}
+
+func (repo *followRepository) FindFollowers(ctx context.Context, followee int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	res, err := repo.entity.FindFollowers(ctx, followee, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *followRepository) FindFollowees(ctx context.Context, follower int64, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	res, err := repo.entity.FindFollowees(ctx, follower, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomain(res), nil
+}
+
+func (repo *followRepository) Following(ctx context.Context, follower int64, followee int64) (bool, error) {
+	_, err := repo.entity.FindRelation(ctx, follower, followee)
+	if err == ErrUserNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (repo *followRepository) FindStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	s, err := repo.cache.GetStatistic(ctx, userId)
+	if err == nil {
+		return s, nil
+	}
+	e, err := repo.entity.FindStatistic(ctx, userId)
+	if err != nil {
+		return domain.FollowStatistic{}, err
+	}
+	s = domain.FollowStatistic{Followers: e.Followers, Followees: e.Followees}
+	// 写回缓存，忽视err
+	_ = repo.cache.SetStatistic(ctx, userId, s)
+	return s, nil
+}
+
+func (repo *followRepository) RemoveUser(ctx context.Context, userId int64) error {
+	affected, err := repo.entity.RemoveUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return repo.cache.DelStatistic(ctx, affected...)
+}
+
+func (repo *followRepository) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	affected, err := repo.entity.MoveUser(ctx, fromUserId, toUserId)
+	if err != nil {
+		return err
+	}
+	return repo.cache.DelStatistic(ctx, affected...)
+}
+
+func (repo *followRepository) toDomain(rs []entity.FollowRelation) []domain.FollowRelation {
+	res := make([]domain.FollowRelation, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, domain.FollowRelation{
+			Id:        r.Id,
+			Follower:  r.Follower,
+			Followee:  r.Followee,
+			CreatedAt: r.CreateTime,
+		})
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	cachemocks "webook/internal/repository/cache/mock"
+	"webook/internal/repository/entity"
+	entitymocks "webook/internal/repository/entity/mock"
+
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFollowRepository_FindStatistic(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache)
+
+		wantStatistic domain.FollowStatistic
+		wantError     error
+	}{
+		{
+			name: "缓存命中",
+			mock: func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache) {
+				e := entitymocks.NewMockFollowEntity(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().GetStatistic(gomock.Any(), int64(1)).Return(domain.FollowStatistic{Followers: 3, Followees: 2}, nil)
+				return e, c
+			},
+			wantStatistic: domain.FollowStatistic{Followers: 3, Followees: 2},
+		},
+		{
+			name: "缓存未命中，查数据库写回缓存",
+			mock: func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache) {
+				e := entitymocks.NewMockFollowEntity(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().GetStatistic(gomock.Any(), int64(1)).Return(domain.FollowStatistic{}, cache.ErrKeyNotExist)
+				e.EXPECT().FindStatistic(gomock.Any(), int64(1)).Return(entity.FollowStatistic{UserId: 1, Followers: 3, Followees: 2}, nil)
+				c.EXPECT().SetStatistic(gomock.Any(), int64(1), domain.FollowStatistic{Followers: 3, Followees: 2}).Return(nil)
+				return e, c
+			},
+			wantStatistic: domain.FollowStatistic{Followers: 3, Followees: 2},
+		},
+		{
+			name: "数据库出错",
+			mock: func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache) {
+				e := entitymocks.NewMockFollowEntity(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().GetStatistic(gomock.Any(), int64(1)).Return(domain.FollowStatistic{}, cache.ErrKeyNotExist)
+				e.EXPECT().FindStatistic(gomock.Any(), int64(1)).Return(entity.FollowStatistic{}, errors.New("db error"))
+				return e, c
+			},
+			wantError: errors.New("db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewFollowRepository(tc.mock(ctrl))
+			s, err := repo.FindStatistic(context.Background(), 1)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantStatistic, s)
+		})
+	}
+}
+
+func TestFollowRepository_Follow(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache)
+
+		wantError error
+	}{
+		{
+			name: "关注成功，两个人的计数缓存都删掉",
+			mock: func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache) {
+				e := entitymocks.NewMockFollowEntity(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				e.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(nil)
+				c.EXPECT().DelStatistic(gomock.Any(), int64(1), int64(2)).Return(nil)
+				return e, c
+			},
+		},
+		{
+			name: "已经关注过，不动缓存",
+			mock: func(ctrl *gomock.Controller) (entity.FollowEntity, cache.FollowCache) {
+				e := entitymocks.NewMockFollowEntity(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				e.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(entity.ErrFollowExists)
+				return e, c
+			},
+			wantError: ErrFollowExists,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewFollowRepository(tc.mock(ctrl))
+			err := repo.Follow(context.Background(), 1, 2)
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
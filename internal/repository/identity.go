@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/entity"
+)
+
+type IdentityRepository interface {
+	FindByUser(ctx context.Context, userId int64) ([]domain.Identity, error)
+	FindBySubject(ctx context.Context, provider string, subject string) (domain.Identity, error)
+	Bind(ctx context.Context, i domain.Identity) error
+	Unbind(ctx context.Context, userId int64, id int64) error
+	// 记下微信绑定请求是哪个用户发起的
+	SaveWeChatBind(ctx context.Context, state string, userId int64, ttl time.Duration) error
+	// 取出发起绑定的用户，只能取一次
+	TakeWeChatBind(ctx context.Context, state string) (int64, error)
+}
+
+var (
+	ErrIdentityDuplicate = entity.ErrIdentityDuplicate
+	ErrIdentityNotFound  = entity.ErrIdentityNotFound
+	ErrBindStateNotFound = cache.ErrBindStateNotFound
+)
+
+type identityRepository struct {
+	entity entity.IdentityEntity
+	// 绑定、解绑会改users表上的手机号等，要删用户缓存
+	userCache cache.UserCache
+	bindCache cache.WeChatBindCache
+}
+
+func NewIdentityRepository(entity entity.IdentityEntity, userCache cache.UserCache,
+	bindCache cache.WeChatBindCache) IdentityRepository {
+	return &identityRepository{entity: entity, userCache: userCache, bindCache: bindCache}
+}
+
+func (repo *identityRepository) SaveWeChatBind(ctx context.Context, state string, userId int64, ttl time.Duration) error {
+	return repo.bindCache.Set(ctx, state, userId, ttl)
+}
+
+func (repo *identityRepository) TakeWeChatBind(ctx context.Context, state string) (int64, error) {
+	return repo.bindCache.Take(ctx, state)
+}
+
+func (repo *identityRepository) FindByUser(ctx context.Context, userId int64) ([]domain.Identity, error) {
+	es, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Identity, 0, len(es))
+	for _, e := range es {
+		res = append(res, repo.entityToDomain(e))
+	}
+	return res, nil
+}
+
+func (repo *identityRepository) FindBySubject(ctx context.Context, provider string, subject string) (domain.Identity, error) {
+	e, err := repo.entity.FindBySubject(ctx, provider, subject)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *identityRepository) Bind(ctx context.Context, i domain.Identity) error {
+	err := repo.entity.Bind(ctx, entity.Identity{
+		UserId:     i.UserId,
+		Provider:   i.Provider,
+		Subject:    i.Subject,
+		VerifyTime: i.VerifiedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return repo.userCache.Del(ctx, i.UserId)
+}
+
+func (repo *identityRepository) Unbind(ctx context.Context, userId int64, id int64) error {
+	if err := repo.entity.Unbind(ctx, userId, id); err != nil {
+		return err
+	}
+	return repo.userCache.Del(ctx, userId)
+}
+
+func (repo *identityRepository) entityToDomain(e entity.Identity) domain.Identity {
+	return domain.Identity{
+		Id:         e.Id,
+		UserId:     e.UserId,
+		Provider:   e.Provider,
+		Subject:    e.Subject,
+		VerifiedAt: e.VerifyTime,
+		CreateAt:   e.CreateTime,
+	}
+}
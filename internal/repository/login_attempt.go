@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+var (
+	ErrAttemptLocked    = cache.ErrAttemptLocked
+	ErrAttemptIpBlocked = cache.ErrAttemptIpBlocked
+)
+
+type LoginAttemptRepository interface {
+	Reserve(ctx context.Context, account string, ip string, ipBlockAfter int64,
+		lockSchedule []time.Duration) (domain.LoginAttempt, error)
+	Release(ctx context.Context, attempt domain.LoginAttempt) error
+	IncrFailure(ctx context.Context, account string, ip string) (int64, int64, error)
+	Failures(ctx context.Context, account string, ip string) (int64, int64, error)
+	Lock(ctx context.Context, account string, d time.Duration) error
+	LockedUntil(ctx context.Context, account string) (int64, error)
+	Reset(ctx context.Context, account string) error
+}
+
+// 失败次数只需要短时间保存，只放在redis里
+type cachedLoginAttemptRepository struct {
+	cache cache.LoginAttemptCache
+}
+
+func NewLoginAttemptRepository(c cache.LoginAttemptCache) LoginAttemptRepository {
+	return &cachedLoginAttemptRepository{cache: c}
+}
+
+func (r *cachedLoginAttemptRepository) Reserve(ctx context.Context, account string, ip string, ipBlockAfter int64,
+	lockSchedule []time.Duration) (domain.LoginAttempt, error) {
+	return r.cache.Reserve(ctx, account, ip, ipBlockAfter, lockSchedule)
+}
+
+func (r *cachedLoginAttemptRepository) Release(ctx context.Context, attempt domain.LoginAttempt) error {
+	return r.cache.Release(ctx, attempt)
+}
+
+func (r *cachedLoginAttemptRepository) IncrFailure(ctx context.Context, account string, ip string) (int64, int64, error) {
+	return r.cache.IncrFailure(ctx, account, ip)
+}
+
+func (r *cachedLoginAttemptRepository) Failures(ctx context.Context, account string, ip string) (int64, int64, error) {
+	return r.cache.Failures(ctx, account, ip)
+}
+
+func (r *cachedLoginAttemptRepository) Lock(ctx context.Context, account string, d time.Duration) error {
+	return r.cache.Lock(ctx, account, d)
+}
+
+func (r *cachedLoginAttemptRepository) LockedUntil(ctx context.Context, account string) (int64, error) {
+	return r.cache.LockedUntil(ctx, account)
+}
+
+func (r *cachedLoginAttemptRepository) Reset(ctx context.Context, account string) error {
+	return r.cache.Reset(ctx, account)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/entity"
+)
+
+type LoginRecordRepository interface {
+	Create(ctx context.Context, r domain.LoginRecord) error
+	FindByUser(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.LoginRecord, error)
+	FindAllByUser(ctx context.Context, userId int64) ([]domain.LoginRecord, error)
+	Search(ctx context.Context, filter domain.LoginRecordFilter, offset int, limit int) ([]domain.LoginRecord, int64, error)
+	DeleteBefore(ctx context.Context, before int64, limit int) (int64, error)
+	DeleteByUser(ctx context.Context, userId int64) error
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+type loginRecordRepository struct {
+	entity entity.LoginRecordEntity
+}
+
+func NewLoginRecordRepository(entity entity.LoginRecordEntity) LoginRecordRepository {
+	return &loginRecordRepository{entity: entity}
+}
+
+func (repo *loginRecordRepository) Create(ctx context.Context, r domain.LoginRecord) error {
+	return repo.entity.Create(ctx, entity.LoginRecord{
+		UserId:    r.UserId,
+		Method:    r.Method,
+		Result:    r.Result,
+		Account:   r.Account,
+		Ip:        r.Ip,
+		UserAgent: r.UserAgent,
+		Ssid:      r.Ssid,
+	})
+}
+
+func (repo *loginRecordRepository) FindByUser(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.LoginRecord, error) {
+	rs, err := repo.entity.FindByUser(ctx, userId, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomain(rs), nil
+}
+
+func (repo *loginRecordRepository) FindAllByUser(ctx context.Context, userId int64) ([]domain.LoginRecord, error) {
+	rs, err := repo.entity.FindAllByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return repo.toDomain(rs), nil
+}
+
+func (repo *loginRecordRepository) Search(ctx context.Context, filter domain.LoginRecordFilter, offset int, limit int) ([]domain.LoginRecord, int64, error) {
+	rs, total, err := repo.entity.Search(ctx, entity.LoginRecordFilter{
+		UserId:      filter.UserId,
+		Account:     filter.Account,
+		Ip:          filter.Ip,
+		Method:      filter.Method,
+		Result:      filter.Result,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+	}, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return repo.toDomain(rs), total, nil
+}
+
+func (repo *loginRecordRepository) DeleteBefore(ctx context.Context, before int64, limit int) (int64, error) {
+	return repo.entity.DeleteBefore(ctx, before, limit)
+}
+
+func (repo *loginRecordRepository) DeleteByUser(ctx context.Context, userId int64) error {
+	return repo.entity.DeleteByUser(ctx, userId)
+}
+
+func (repo *loginRecordRepository) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	return repo.entity.MoveUser(ctx, fromUserId, toUserId)
+}
+
+func (repo *loginRecordRepository) toDomain(rs []entity.LoginRecord) []domain.LoginRecord {
+	res := make([]domain.LoginRecord, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, domain.LoginRecord{
+			Id:        r.Id,
+			UserId:    r.UserId,
+			Method:    r.Method,
+			Result:    r.Result,
+			Account:   r.Account,
+			Ip:        r.Ip,
+			UserAgent: r.UserAgent,
+			Ssid:      r.Ssid,
+			CreatedAt: r.CreateTime,
+		})
+	}
+	return res
+}
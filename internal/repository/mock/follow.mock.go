@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/follow.go -package=repomocks -destination=./internal/repository/mock/follow.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowRepository is a mock of FollowRepository interface.
+type MockFollowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRepositoryMockRecorder
+}
+
+// MockFollowRepositoryMockRecorder is the mock recorder for MockFollowRepository.
+type MockFollowRepositoryMockRecorder struct {
+	mock *MockFollowRepository
+}
+
+// NewMockFollowRepository creates a new mock instance.
+func NewMockFollowRepository(ctrl *gomock.Controller) *MockFollowRepository {
+	mock := &MockFollowRepository{ctrl: ctrl}
+	mock.recorder = &MockFollowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRepository) EXPECT() *MockFollowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindFollowees mocks base method.
+func (m *MockFollowRepository) FindFollowees(ctx context.Context, follower, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowees", ctx, follower, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowees indicates an expected call of FindFollowees.
+func (mr *MockFollowRepositoryMockRecorder) FindFollowees(ctx, follower, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowees", reflect.TypeOf((*MockFollowRepository)(nil).FindFollowees), ctx, follower, cursor, limit)
+}
+
+// FindFollowers mocks base method.
+func (m *MockFollowRepository) FindFollowers(ctx context.Context, followee, cursor int64, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFollowers", ctx, followee, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFollowers indicates an expected call of FindFollowers.
+func (mr *MockFollowRepositoryMockRecorder) FindFollowers(ctx, followee, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFollowers", reflect.TypeOf((*MockFollowRepository)(nil).FindFollowers), ctx, followee, cursor, limit)
+}
+
+// FindStatistic mocks base method.
+func (m *MockFollowRepository) FindStatistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStatistic", ctx, userId)
+	ret0, _ := ret[0].(domain.FollowStatistic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStatistic indicates an expected call of FindStatistic.
+func (mr *MockFollowRepositoryMockRecorder) FindStatistic(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStatistic", reflect.TypeOf((*MockFollowRepository)(nil).FindStatistic), ctx, userId)
+}
+
+// Follow mocks base method.
+func (m *MockFollowRepository) Follow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowRepositoryMockRecorder) Follow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollowRepository)(nil).Follow), ctx, follower, followee)
+}
+
+// Following mocks base method.
+func (m *MockFollowRepository) Following(ctx context.Context, follower, followee int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Following", ctx, follower, followee)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Following indicates an expected call of Following.
+func (mr *MockFollowRepositoryMockRecorder) Following(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Following", reflect.TypeOf((*MockFollowRepository)(nil).Following), ctx, follower, followee)
+}
+
+// MoveUser mocks base method.
+func (m *MockFollowRepository) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockFollowRepositoryMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockFollowRepository)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// RemoveUser mocks base method.
+func (m *MockFollowRepository) RemoveUser(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUser indicates an expected call of RemoveUser.
+func (mr *MockFollowRepositoryMockRecorder) RemoveUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUser", reflect.TypeOf((*MockFollowRepository)(nil).RemoveUser), ctx, userId)
+}
+
+// Unfollow mocks base method.
+func (m *MockFollowRepository) Unfollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockFollowRepositoryMockRecorder) Unfollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockFollowRepository)(nil).Unfollow), ctx, follower, followee)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/identity.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/identity.go -package=repomocks -destination=./internal/repository/mock/identity.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Bind mocks base method.
+func (m *MockIdentityRepository) Bind(ctx context.Context, i domain.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bind", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bind indicates an expected call of Bind.
+func (mr *MockIdentityRepositoryMockRecorder) Bind(ctx, i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bind", reflect.TypeOf((*MockIdentityRepository)(nil).Bind), ctx, i)
+}
+
+// FindBySubject mocks base method.
+func (m *MockIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySubject", ctx, provider, subject)
+	ret0, _ := ret[0].(domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySubject indicates an expected call of FindBySubject.
+func (mr *MockIdentityRepositoryMockRecorder) FindBySubject(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySubject", reflect.TypeOf((*MockIdentityRepository)(nil).FindBySubject), ctx, provider, subject)
+}
+
+// FindByUser mocks base method.
+func (m *MockIdentityRepository) FindByUser(ctx context.Context, userId int64) ([]domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockIdentityRepositoryMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockIdentityRepository)(nil).FindByUser), ctx, userId)
+}
+
+// SaveWeChatBind mocks base method.
+func (m *MockIdentityRepository) SaveWeChatBind(ctx context.Context, state string, userId int64, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWeChatBind", ctx, state, userId, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWeChatBind indicates an expected call of SaveWeChatBind.
+func (mr *MockIdentityRepositoryMockRecorder) SaveWeChatBind(ctx, state, userId, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWeChatBind", reflect.TypeOf((*MockIdentityRepository)(nil).SaveWeChatBind), ctx, state, userId, ttl)
+}
+
+// TakeWeChatBind mocks base method.
+func (m *MockIdentityRepository) TakeWeChatBind(ctx context.Context, state string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeWeChatBind", ctx, state)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeWeChatBind indicates an expected call of TakeWeChatBind.
+func (mr *MockIdentityRepositoryMockRecorder) TakeWeChatBind(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeWeChatBind", reflect.TypeOf((*MockIdentityRepository)(nil).TakeWeChatBind), ctx, state)
+}
+
+// Unbind mocks base method.
+func (m *MockIdentityRepository) Unbind(ctx context.Context, userId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unbind", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unbind indicates an expected call of Unbind.
+func (mr *MockIdentityRepositoryMockRecorder) Unbind(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unbind", reflect.TypeOf((*MockIdentityRepository)(nil).Unbind), ctx, userId, id)
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/login_record.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/login_record.go -package=repomocks -destination=./internal/repository/mock/login_record.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginRecordRepository is a mock of LoginRecordRepository interface.
+type MockLoginRecordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginRecordRepositoryMockRecorder
+}
+
+// MockLoginRecordRepositoryMockRecorder is the mock recorder for MockLoginRecordRepository.
+type MockLoginRecordRepositoryMockRecorder struct {
+	mock *MockLoginRecordRepository
+}
+
+// NewMockLoginRecordRepository creates a new mock instance.
+func NewMockLoginRecordRepository(ctrl *gomock.Controller) *MockLoginRecordRepository {
+	mock := &MockLoginRecordRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginRecordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginRecordRepository) EXPECT() *MockLoginRecordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLoginRecordRepository) Create(ctx context.Context, r domain.LoginRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLoginRecordRepositoryMockRecorder) Create(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLoginRecordRepository)(nil).Create), ctx, r)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockLoginRecordRepository) DeleteBefore(ctx context.Context, before int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockLoginRecordRepositoryMockRecorder) DeleteBefore(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockLoginRecordRepository)(nil).DeleteBefore), ctx, before, limit)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockLoginRecordRepository) DeleteByUser(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockLoginRecordRepositoryMockRecorder) DeleteByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockLoginRecordRepository)(nil).DeleteByUser), ctx, userId)
+}
+
+// FindAllByUser mocks base method.
+func (m *MockLoginRecordRepository) FindAllByUser(ctx context.Context, userId int64) ([]domain.LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByUser", ctx, userId)
+	ret0, _ := ret[0].([]domain.LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByUser indicates an expected call of FindAllByUser.
+func (mr *MockLoginRecordRepositoryMockRecorder) FindAllByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByUser", reflect.TypeOf((*MockLoginRecordRepository)(nil).FindAllByUser), ctx, userId)
+}
+
+// FindByUser mocks base method.
+func (m *MockLoginRecordRepository) FindByUser(ctx context.Context, userId, cursor int64, limit int) ([]domain.LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId, cursor, limit)
+	ret0, _ := ret[0].([]domain.LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockLoginRecordRepositoryMockRecorder) FindByUser(ctx, userId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockLoginRecordRepository)(nil).FindByUser), ctx, userId, cursor, limit)
+}
+
+// MoveUser mocks base method.
+func (m *MockLoginRecordRepository) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockLoginRecordRepositoryMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockLoginRecordRepository)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// Search mocks base method.
+func (m *MockLoginRecordRepository) Search(ctx context.Context, filter domain.LoginRecordFilter, offset, limit int) ([]domain.LoginRecord, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]domain.LoginRecord)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockLoginRecordRepositoryMockRecorder) Search(ctx, filter, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockLoginRecordRepository)(nil).Search), ctx, filter, offset, limit)
+}
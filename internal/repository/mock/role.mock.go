@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/role.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/role.go -package=repomocks -destination=./internal/repository/mock/role.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockRoleRepository) Assign(ctx context.Context, userId int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, userId, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockRoleRepositoryMockRecorder) Assign(ctx, userId, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRoleRepository)(nil).Assign), ctx, userId, roleName)
+}
+
+// FindAuthz mocks base method.
+func (m *MockRoleRepository) FindAuthz(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAuthz", ctx, userId)
+	ret0, _ := ret[0].(domain.UserAuthz)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAuthz indicates an expected call of FindAuthz.
+func (mr *MockRoleRepositoryMockRecorder) FindAuthz(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAuthz", reflect.TypeOf((*MockRoleRepository)(nil).FindAuthz), ctx, userId)
+}
+
+// MoveUser mocks base method.
+func (m *MockRoleRepository) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockRoleRepositoryMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockRoleRepository)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// Revoke mocks base method.
+func (m *MockRoleRepository) Revoke(ctx context.Context, userId int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userId, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRoleRepositoryMockRecorder) Revoke(ctx, userId, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoleRepository)(nil).Revoke), ctx, userId, roleName)
+}
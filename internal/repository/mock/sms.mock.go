@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/sms.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/sms.go -package=repomocks -destination=internal/repository/mock/sms.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entity "webook/internal/repository/entity"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSmsRepository is a mock of SmsRepository interface.
+type MockSmsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSmsRepositoryMockRecorder
+}
+
+// MockSmsRepositoryMockRecorder is the mock recorder for MockSmsRepository.
+type MockSmsRepositoryMockRecorder struct {
+	mock *MockSmsRepository
+}
+
+// NewMockSmsRepository creates a new mock instance.
+func NewMockSmsRepository(ctrl *gomock.Controller) *MockSmsRepository {
+	mock := &MockSmsRepository{ctrl: ctrl}
+	mock.recorder = &MockSmsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSmsRepository) EXPECT() *MockSmsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByNumber mocks base method.
+func (m *MockSmsRepository) FindByNumber(ctx context.Context, number string) ([]entity.SMS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNumber", ctx, number)
+	ret0, _ := ret[0].([]entity.SMS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNumber indicates an expected call of FindByNumber.
+func (mr *MockSmsRepositoryMockRecorder) FindByNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNumber", reflect.TypeOf((*MockSmsRepository)(nil).FindByNumber), ctx, number)
+}
+
+// FindRetryJob mocks base method.
+func (m *MockSmsRepository) FindRetryJob() ([]entity.SMS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRetryJob")
+	ret0, _ := ret[0].([]entity.SMS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRetryJob indicates an expected call of FindRetryJob.
+func (mr *MockSmsRepositoryMockRecorder) FindRetryJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRetryJob", reflect.TypeOf((*MockSmsRepository)(nil).FindRetryJob))
+}
+
+// RemoveNumber mocks base method.
+func (m *MockSmsRepository) RemoveNumber(ctx context.Context, number string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNumber", ctx, number)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNumber indicates an expected call of RemoveNumber.
+func (mr *MockSmsRepositoryMockRecorder) RemoveNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNumber", reflect.TypeOf((*MockSmsRepository)(nil).RemoveNumber), ctx, number)
+}
+
+// SetJobSuccess mocks base method.
+func (m *MockSmsRepository) SetJobSuccess(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetJobSuccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobSuccess indicates an expected call of SetJobSuccess.
+func (mr *MockSmsRepositoryMockRecorder) SetJobSuccess(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobSuccess", reflect.TypeOf((*MockSmsRepository)(nil).SetJobSuccess), ctx, id)
+}
+
+// Store mocks base method.
+func (m *MockSmsRepository) Store(ctx context.Context, tplId string, args, numbers []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", ctx, tplId, args, numbers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockSmsRepositoryMockRecorder) Store(ctx, tplId, args, numbers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockSmsRepository)(nil).Store), ctx, tplId, args, numbers)
+}
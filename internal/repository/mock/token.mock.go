@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/token.go -package=repomocks -destination=./internal/repository/mock/token.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPersonalTokenRepository is a mock of PersonalTokenRepository interface.
+type MockPersonalTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalTokenRepositoryMockRecorder
+}
+
+// MockPersonalTokenRepositoryMockRecorder is the mock recorder for MockPersonalTokenRepository.
+type MockPersonalTokenRepositoryMockRecorder struct {
+	mock *MockPersonalTokenRepository
+}
+
+// NewMockPersonalTokenRepository creates a new mock instance.
+func NewMockPersonalTokenRepository(ctrl *gomock.Controller) *MockPersonalTokenRepository {
+	mock := &MockPersonalTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockPersonalTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalTokenRepository) EXPECT() *MockPersonalTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonalTokenRepository) Create(ctx context.Context, t domain.PersonalToken, hash string) (domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t, hash)
+	ret0, _ := ret[0].(domain.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalTokenRepositoryMockRecorder) Create(ctx, t, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalTokenRepository)(nil).Create), ctx, t, hash)
+}
+
+// Delete mocks base method.
+func (m *MockPersonalTokenRepository) Delete(ctx context.Context, userId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPersonalTokenRepositoryMockRecorder) Delete(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPersonalTokenRepository)(nil).Delete), ctx, userId, id)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockPersonalTokenRepository) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockPersonalTokenRepositoryMockRecorder) DeleteByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockPersonalTokenRepository)(nil).DeleteByUser), ctx, userId)
+}
+
+// FindByHash mocks base method.
+func (m *MockPersonalTokenRepository) FindByHash(ctx context.Context, hash string) (domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, hash)
+	ret0, _ := ret[0].(domain.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockPersonalTokenRepositoryMockRecorder) FindByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockPersonalTokenRepository)(nil).FindByHash), ctx, hash)
+}
+
+// FindByUser mocks base method.
+func (m *MockPersonalTokenRepository) FindByUser(ctx context.Context, userId int64) ([]domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId)
+	ret0, _ := ret[0].([]domain.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockPersonalTokenRepositoryMockRecorder) FindByUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockPersonalTokenRepository)(nil).FindByUser), ctx, userId)
+}
+
+// MoveUser mocks base method.
+func (m *MockPersonalTokenRepository) MoveUser(ctx context.Context, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUser", ctx, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUser indicates an expected call of MoveUser.
+func (mr *MockPersonalTokenRepositoryMockRecorder) MoveUser(ctx, fromUserId, toUserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUser", reflect.TypeOf((*MockPersonalTokenRepository)(nil).MoveUser), ctx, fromUserId, toUserId)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockPersonalTokenRepository) UpdateLastUsed(ctx context.Context, id, lastUsed int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id, lastUsed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockPersonalTokenRepositoryMockRecorder) UpdateLastUsed(ctx, id, lastUsed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockPersonalTokenRepository)(nil).UpdateLastUsed), ctx, id, lastUsed)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/user_status.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/user_status.go -package=repomocks -destination=./internal/repository/mock/user_status.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserStatusRepository is a mock of UserStatusRepository interface.
+type MockUserStatusRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatusRepositoryMockRecorder
+}
+
+// MockUserStatusRepositoryMockRecorder is the mock recorder for MockUserStatusRepository.
+type MockUserStatusRepositoryMockRecorder struct {
+	mock *MockUserStatusRepository
+}
+
+// NewMockUserStatusRepository creates a new mock instance.
+func NewMockUserStatusRepository(ctrl *gomock.Controller) *MockUserStatusRepository {
+	mock := &MockUserStatusRepository{ctrl: ctrl}
+	mock.recorder = &MockUserStatusRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatusRepository) EXPECT() *MockUserStatusRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindStatus mocks base method.
+func (m *MockUserStatusRepository) FindStatus(ctx context.Context, userId int64) (domain.AccountStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStatus", ctx, userId)
+	ret0, _ := ret[0].(domain.AccountStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStatus indicates an expected call of FindStatus.
+func (mr *MockUserStatusRepositoryMockRecorder) FindStatus(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStatus", reflect.TypeOf((*MockUserStatusRepository)(nil).FindStatus), ctx, userId)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/entity"
+)
+
+type RoleRepository interface {
+	FindAuthz(ctx context.Context, userId int64) (domain.UserAuthz, error)
+	Assign(ctx context.Context, userId int64, roleName string) error
+	Revoke(ctx context.Context, userId int64, roleName string) error
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+var ErrRoleNotFound = entity.ErrRoleNotFound
+
+type roleRepository struct {
+	entity entity.RoleEntity
+	cache  cache.RoleCache
+}
+
+func NewRoleRepository(entity entity.RoleEntity, cache cache.RoleCache) RoleRepository {
+	return &roleRepository{entity: entity, cache: cache}
+}
+
+func (repo *roleRepository) FindAuthz(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	authz, err := repo.cache.Get(ctx, userId)
+	if err == nil {
+		return authz, nil
+	}
+	roles, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return domain.UserAuthz{}, err
+	}
+	roleIds := make([]int64, 0, len(roles))
+	authz = domain.UserAuthz{UserId: userId, Roles: make([]string, 0, len(roles))}
+	for _, r := range roles {
+		roleIds = append(roleIds, r.Id)
+		authz.Roles = append(authz.Roles, r.Name)
+	}
+	perms, err := repo.entity.FindPermissions(ctx, roleIds)
+	if err != nil {
+		return domain.UserAuthz{}, err
+	}
+	// 多个角色可能有相同的权限，去重
+	seen := make(map[string]struct{}, len(perms))
+	authz.Permissions = make([]string, 0, len(perms))
+	for _, p := range perms {
+		if _, ok := seen[p.Permission]; ok {
+			continue
+		}
+		seen[p.Permission] = struct{}{}
+		authz.Permissions = append(authz.Permissions, p.Permission)
+	}
+	// 写回缓存，忽略err
+	_ = repo.cache.Set(ctx, authz)
+	return authz, nil
+}
+
+// 先改数据库再删缓存，下一次请求就能看到新的角色
+func (repo *roleRepository) Assign(ctx context.Context, userId int64, roleName string) error {
+	r, err := repo.entity.FindByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	if err = repo.entity.AssignToUser(ctx, userId, r.Id); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+// 两个用户的角色都变了，都要删缓存
+func (repo *roleRepository) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	if err := repo.entity.MoveUser(ctx, fromUserId, toUserId); err != nil {
+		return err
+	}
+	if err := repo.cache.Del(ctx, fromUserId); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, toUserId)
+}
+
+func (repo *roleRepository) Revoke(ctx context.Context, userId int64, roleName string) error {
+	r, err := repo.entity.FindByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	if err = repo.entity.RevokeFromUser(ctx, userId, r.Id); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
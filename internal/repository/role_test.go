@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	cachemocks "webook/internal/repository/cache/mock"
+	"webook/internal/repository/entity"
+	entitymocks "webook/internal/repository/entity/mock"
+
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoleRepository_FindAuthz(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (entity.RoleEntity, cache.RoleCache)
+
+		userId int64
+
+		wantAuthz domain.UserAuthz
+		wantError error
+	}{
+		{
+			name: "缓存命中",
+			mock: func(ctrl *gomock.Controller) (entity.RoleEntity, cache.RoleCache) {
+				e := entitymocks.NewMockRoleEntity(ctrl)
+				c := cachemocks.NewMockRoleCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), int64(1)).Return(domain.UserAuthz{UserId: 1, Roles: []string{"admin"}, Permissions: []string{"*"}}, nil)
+				return e, c
+			},
+			userId:    1,
+			wantAuthz: domain.UserAuthz{UserId: 1, Roles: []string{"admin"}, Permissions: []string{"*"}},
+		},
+		{
+			name: "缓存未命中，权限去重后写回缓存",
+			mock: func(ctrl *gomock.Controller) (entity.RoleEntity, cache.RoleCache) {
+				e := entitymocks.NewMockRoleEntity(ctrl)
+				c := cachemocks.NewMockRoleCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), int64(1)).Return(domain.UserAuthz{}, cache.ErrKeyNotExist)
+				e.EXPECT().FindByUser(gomock.Any(), int64(1)).Return([]entity.Role{{Id: 1, Name: "auditor"}, {Id: 2, Name: "operator"}}, nil)
+				e.EXPECT().FindPermissions(gomock.Any(), []int64{1, 2}).Return([]entity.RolePermission{
+					{RoleId: 1, Permission: "user:view"},
+					{RoleId: 2, Permission: "user:view"},
+					{RoleId: 2, Permission: "user:ban"},
+				}, nil)
+				c.EXPECT().Set(gomock.Any(), domain.UserAuthz{
+					UserId:      1,
+					Roles:       []string{"auditor", "operator"},
+					Permissions: []string{"user:view", "user:ban"},
+				}).Return(nil)
+				return e, c
+			},
+			userId: 1,
+			wantAuthz: domain.UserAuthz{
+				UserId:      1,
+				Roles:       []string{"auditor", "operator"},
+				Permissions: []string{"user:view", "user:ban"},
+			},
+		},
+		{
+			name: "数据库出错",
+			mock: func(ctrl *gomock.Controller) (entity.RoleEntity, cache.RoleCache) {
+				e := entitymocks.NewMockRoleEntity(ctrl)
+				c := cachemocks.NewMockRoleCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), int64(1)).Return(domain.UserAuthz{}, cache.ErrKeyNotExist)
+				e.EXPECT().FindByUser(gomock.Any(), int64(1)).Return(nil, errors.New("db error"))
+				return e, c
+			},
+			userId:    1,
+			wantError: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewRoleRepository(tc.mock(ctrl))
+			authz, err := repo.FindAuthz(context.Background(), tc.userId)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantAuthz, authz)
+		})
+	}
+}
+
+func TestRoleRepository_Assign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	e := entitymocks.NewMockRoleEntity(ctrl)
+	c := cachemocks.NewMockRoleCache(ctrl)
+	// 先写数据库，再删缓存
+	gomock.InOrder(
+		e.EXPECT().FindByName(gomock.Any(), "admin").Return(entity.Role{Id: 3, Name: "admin"}, nil),
+		e.EXPECT().AssignToUser(gomock.Any(), int64(1), int64(3)).Return(nil),
+		c.EXPECT().Del(gomock.Any(), int64(1)).Return(nil),
+	)
+	err := NewRoleRepository(e, c).Assign(context.Background(), 1, "admin")
+	assert.Equal(t, nil, err)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/entity"
+)
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, e domain.SecurityEvent) error
+	FindByUser(ctx context.Context, userId int64) ([]domain.SecurityEvent, error)
+}
+
+type securityEventRepository struct {
+	entity entity.SecurityEventEntity
+}
+
+func NewSecurityEventRepository(entity entity.SecurityEventEntity) SecurityEventRepository {
+	return &securityEventRepository{entity: entity}
+}
+
+func (repo *securityEventRepository) Create(ctx context.Context, e domain.SecurityEvent) error {
+	return repo.entity.Create(ctx, entity.SecurityEvent{
+		UserId:    e.UserId,
+		Type:      e.Type,
+		Ssid:      e.Ssid,
+		Ip:        e.Ip,
+		UserAgent: e.UserAgent,
+		Detail:    e.Detail,
+	})
+}
+
+func (repo *securityEventRepository) FindByUser(ctx context.Context, userId int64) ([]domain.SecurityEvent, error) {
+	es, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]domain.SecurityEvent, 0, len(es))
+	for _, e := range es {
+		events = append(events, domain.SecurityEvent{
+			Id:        e.Id,
+			UserId:    e.UserId,
+			Type:      e.Type,
+			Ssid:      e.Ssid,
+			Ip:        e.Ip,
+			UserAgent: e.UserAgent,
+			Detail:    e.Detail,
+			CreateAt:  e.CreateTime,
+		})
+	}
+	return events, nil
+}
@@ -9,6 +9,9 @@ type SmsRepository interface {
 	Store(ctx context.Context, tplId string, args []string, numbers []string) error
 	FindRetryJob() ([]entity.SMS, error)
 	SetJobSuccess(ctx context.Context, id int64) error
+	FindByNumber(ctx context.Context, number string) ([]entity.SMS, error)
+	// 注销清理时去掉这个手机号
+	RemoveNumber(ctx context.Context, number string) error
 }
 
 type smsRepository struct {
@@ -30,3 +33,11 @@ func (s *smsRepository) FindRetryJob() ([]entity.SMS, error) {
 func (s *smsRepository) SetJobSuccess(ctx context.Context, id int64) error {
 	return s.e.SetJobSuccess(ctx, id)
 }
+
+func (s *smsRepository) FindByNumber(ctx context.Context, number string) ([]entity.SMS, error) {
+	return s.e.FindByNumber(ctx, number)
+}
+
+func (s *smsRepository) RemoveNumber(ctx context.Context, number string) error {
+	return s.e.RemoveNumber(ctx, number)
+}
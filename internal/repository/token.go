@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"webook/internal/domain"
+	"webook/internal/repository/entity"
+)
+
+type PersonalTokenRepository interface {
+	// hash是令牌明文的摘要，明文不落库
+	Create(ctx context.Context, t domain.PersonalToken, hash string) (domain.PersonalToken, error)
+	FindByHash(ctx context.Context, hash string) (domain.PersonalToken, error)
+	FindByUser(ctx context.Context, userId int64) ([]domain.PersonalToken, error)
+	Delete(ctx context.Context, userId int64, id int64) error
+	DeleteByUser(ctx context.Context, userId int64) (int64, error)
+	UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error
+	MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error
+}
+
+var ErrPersonalTokenNotFound = entity.ErrPersonalTokenNotFound
+
+type personalTokenRepository struct {
+	entity entity.PersonalTokenEntity
+}
+
+func NewPersonalTokenRepository(entity entity.PersonalTokenEntity) PersonalTokenRepository {
+	return &personalTokenRepository{entity: entity}
+}
+
+func (repo *personalTokenRepository) MoveUser(ctx context.Context, fromUserId int64, toUserId int64) error {
+	return repo.entity.MoveUser(ctx, fromUserId, toUserId)
+}
+
+func (repo *personalTokenRepository) Create(ctx context.Context, t domain.PersonalToken, hash string) (domain.PersonalToken, error) {
+	e, err := repo.entity.Create(ctx, entity.PersonalToken{
+		UserId:     t.UserId,
+		Name:       t.Name,
+		Hash:       hash,
+		Prefix:     t.Prefix,
+		Scopes:     strings.Join(t.Scopes, ","),
+		ExpireTime: t.ExpireAt,
+	})
+	if err != nil {
+		return domain.PersonalToken{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *personalTokenRepository) FindByHash(ctx context.Context, hash string) (domain.PersonalToken, error) {
+	e, err := repo.entity.FindByHash(ctx, hash)
+	if err != nil {
+		return domain.PersonalToken{}, err
+	}
+	return repo.entityToDomain(e), nil
+}
+
+func (repo *personalTokenRepository) FindByUser(ctx context.Context, userId int64) ([]domain.PersonalToken, error) {
+	es, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]domain.PersonalToken, 0, len(es))
+	for _, e := range es {
+		tokens = append(tokens, repo.entityToDomain(e))
+	}
+	return tokens, nil
+}
+
+func (repo *personalTokenRepository) Delete(ctx context.Context, userId int64, id int64) error {
+	ok, err := repo.entity.Delete(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPersonalTokenNotFound
+	}
+	return nil
+}
+
+func (repo *personalTokenRepository) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	return repo.entity.DeleteByUser(ctx, userId)
+}
+
+func (repo *personalTokenRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsed int64) error {
+	return repo.entity.UpdateLastUsed(ctx, id, lastUsed)
+}
+
+func (repo *personalTokenRepository) entityToDomain(e entity.PersonalToken) domain.PersonalToken {
+	var scopes []string
+	if e.Scopes != "" {
+		scopes = strings.Split(e.Scopes, ",")
+	}
+	return domain.PersonalToken{
+		Id:         e.Id,
+		UserId:     e.UserId,
+		Name:       e.Name,
+		Scopes:     scopes,
+		Prefix:     e.Prefix,
+		LastUsedAt: e.LastUsedTime,
+		ExpireAt:   e.ExpireTime,
+		CreateAt:   e.CreateTime,
+	}
+}
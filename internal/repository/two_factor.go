@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/entity"
+)
+
+type TwoFactorRepository interface {
+	FindByUser(ctx context.Context, userId int64) (domain.TwoFactor, error)
+	SavePending(ctx context.Context, userId int64, secret string) error
+	Enable(ctx context.Context, userId int64, step int64, codeHashes []string) error
+	Disable(ctx context.Context, userId int64) error
+	UseStep(ctx context.Context, userId int64, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userId int64, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error
+
+	// 登录凭证只放在redis里
+	SetChallenge(ctx context.Context, challenge string, userId int64, ttl time.Duration) error
+	GetChallenge(ctx context.Context, challenge string, maxAttempts int) (int64, error)
+	DeleteChallenge(ctx context.Context, challenge string) (bool, error)
+}
+
+var (
+	ErrTwoFactorNotFound      = entity.ErrTwoFactorNotFound
+	ErrTwoFactorEnabled       = entity.ErrTwoFactorEnabled
+	ErrChallengeNotFound      = cache.ErrChallengeNotFound
+	ErrChallengeVerifyTooMany = cache.ErrChallengeVerifyTooMany
+)
+
+type twoFactorRepository struct {
+	entity entity.TwoFactorEntity
+	cache  cache.TwoFactorChallengeCache
+}
+
+func NewTwoFactorRepository(entity entity.TwoFactorEntity, c cache.TwoFactorChallengeCache) TwoFactorRepository {
+	return &twoFactorRepository{entity: entity, cache: c}
+}
+
+func (repo *twoFactorRepository) FindByUser(ctx context.Context, userId int64) (domain.TwoFactor, error) {
+	e, err := repo.entity.FindByUser(ctx, userId)
+	if err != nil {
+		return domain.TwoFactor{}, err
+	}
+	return domain.TwoFactor{
+		UserId:       e.UserId,
+		Secret:       e.Secret,
+		EnabledAt:    e.EnableTime,
+		LastUsedStep: e.LastUsedStep,
+	}, nil
+}
+
+func (repo *twoFactorRepository) SavePending(ctx context.Context, userId int64, secret string) error {
+	return repo.entity.SavePending(ctx, userId, secret)
+}
+
+func (repo *twoFactorRepository) Enable(ctx context.Context, userId int64, step int64, codeHashes []string) error {
+	return repo.entity.Enable(ctx, userId, step, codeHashes)
+}
+
+func (repo *twoFactorRepository) Disable(ctx context.Context, userId int64) error {
+	return repo.entity.Disable(ctx, userId)
+}
+
+func (repo *twoFactorRepository) UseStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	return repo.entity.UseStep(ctx, userId, step)
+}
+
+func (repo *twoFactorRepository) UseRecoveryCode(ctx context.Context, userId int64, codeHash string) (bool, error) {
+	return repo.entity.UseRecoveryCode(ctx, userId, codeHash)
+}
+
+func (repo *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error {
+	return repo.entity.ReplaceRecoveryCodes(ctx, userId, codeHashes)
+}
+
+func (repo *twoFactorRepository) SetChallenge(ctx context.Context, challenge string, userId int64, ttl time.Duration) error {
+	return repo.cache.Set(ctx, challenge, userId, ttl)
+}
+
+func (repo *twoFactorRepository) GetChallenge(ctx context.Context, challenge string, maxAttempts int) (int64, error) {
+	return repo.cache.Get(ctx, challenge, maxAttempts)
+}
+
+func (repo *twoFactorRepository) DeleteChallenge(ctx context.Context, challenge string) (bool, error) {
+	return repo.cache.Delete(ctx, challenge)
+}
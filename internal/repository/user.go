@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"webook/internal/domain"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/entity"
@@ -15,10 +17,34 @@ type UserRepository interface {
 	Update(ctx context.Context, userId int64, nickname string, description string, birthday int64) (domain.User, error)
 	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	FindByWeChat(ctx context.Context, openId string) (domain.User, error)
+	UpdatePassword(ctx context.Context, userId int64, hash string) error
+	// 缓存里不存密码，直接查数据库
+	FindPasswordHash(ctx context.Context, userId int64) (string, error)
+	MarkEmailVerified(ctx context.Context, userId int64) error
+	// 走缓存，公开主页用
+	FindByHandle(ctx context.Context, handle string) (domain.User, error)
+	// changedBefore之后改过用户名的不能再改
+	UpdateHandle(ctx context.Context, userId int64, handle string, changedBefore int64) error
+	// 换头像，返回旧头像文件的key
+	UpdateAvatar(ctx context.Context, userId int64, keys []string, urls map[string]string) ([]string, error)
+	// 把source合并到target上，返回合并后的target
+	Merge(ctx context.Context, targetId int64, sourceId int64) (domain.User, error)
+	// 管理后台用，直接查数据库
+	Search(ctx context.Context, filter domain.UserFilter, offset int, limit int) ([]domain.User, int64, error)
+	UpdateStatus(ctx context.Context, userId int64, status domain.UserStatus) error
+	RequestDeletion(ctx context.Context, userId int64, purgeAt int64) error
+	Restore(ctx context.Context, userId int64) error
+	FindPurgeable(ctx context.Context, now int64, limit int) ([]int64, error)
+	Purge(ctx context.Context, userId int64) error
 }
 
 var ErrUserDuplicate = entity.ErrUserDuplciate
 var ErrUserNotFound = entity.ErrUserNotFound
+var ErrUserMerged = entity.ErrUserMerged
+var ErrUserBanned = entity.ErrUserBanned
+var ErrMergeConflict = entity.ErrMergeConflict
+var ErrHandleTaken = entity.ErrHandleTaken
+var ErrHandleTooSoon = entity.ErrHandleTooSoon
 
 type userRepository struct {
 	entity entity.UserEntity
@@ -41,6 +67,156 @@ func (repo *userRepository) FindByEmail(ctx context.Context, email string) (doma
 	return repo.entityToDomain(ue), nil
 }
 
+// 缓存里不存密码，不需要处理缓存
+func (repo *userRepository) UpdatePassword(ctx context.Context, userId int64, hash string) error {
+	return repo.entity.UpdatePassword(ctx, userId, hash)
+}
+
+func (repo *userRepository) FindPasswordHash(ctx context.Context, userId int64) (string, error) {
+	ue, err := repo.entity.FindById(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	return ue.Password, nil
+}
+
+// 缓存里有头像地址，改完数据库删缓存
+func (repo *userRepository) UpdateAvatar(ctx context.Context, userId int64, keys []string, urls map[string]string) ([]string, error) {
+	var avatar string
+	if len(urls) > 0 {
+		val, err := json.Marshal(urls)
+		if err != nil {
+			return nil, err
+		}
+		avatar = string(val)
+	}
+	old, err := repo.entity.UpdateAvatar(ctx, userId, strings.Join(keys, ","), avatar)
+	if err != nil {
+		return nil, err
+	}
+	if err = repo.cache.Del(ctx, userId); err != nil {
+		return nil, err
+	}
+	if old == "" {
+		return nil, nil
+	}
+	return strings.Split(old, ","), nil
+}
+
+// 先用映射找到id，再走用户缓存
+func (repo *userRepository) FindByHandle(ctx context.Context, handle string) (domain.User, error) {
+	id, err := repo.cache.GetHandle(ctx, handle)
+	if err == nil {
+		u, er := repo.FindById(ctx, id)
+		// 映射过期了，比如用户名已经改了，回去查数据库
+		if er == nil && u.Handle == handle {
+			return u, nil
+		}
+		if er != nil && er != ErrUserNotFound {
+			return domain.User{}, er
+		}
+		_ = repo.cache.DelHandle(ctx, handle)
+	}
+	ue, err := repo.entity.FindByHandle(ctx, handle)
+	if err != nil {
+		return domain.User{}, err
+	}
+	user := repo.entityToDomain(ue)
+	// 写回缓存，忽视err
+	_ = repo.cache.Set(ctx, user)
+	_ = repo.cache.SetHandle(ctx, handle, user.Id)
+	return user, nil
+}
+
+// 旧用户名的映射和用户缓存都要删
+func (repo *userRepository) UpdateHandle(ctx context.Context, userId int64, handle string, changedBefore int64) error {
+	old, err := repo.entity.UpdateHandle(ctx, userId, handle, changedBefore)
+	if err != nil {
+		return err
+	}
+	if old != "" && old != handle {
+		if err = repo.cache.DelHandle(ctx, old); err != nil {
+			return err
+		}
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+// 缓存里有验证状态，改完数据库删缓存
+func (repo *userRepository) MarkEmailVerified(ctx context.Context, userId int64) error {
+	if err := repo.entity.MarkEmailVerified(ctx, userId); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+// 两个用户的资料都变了，都要删缓存
+func (repo *userRepository) Merge(ctx context.Context, targetId int64, sourceId int64) (domain.User, error) {
+	ue, err := repo.entity.Merge(ctx, targetId, sourceId)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err = repo.cache.Del(ctx, sourceId); err != nil {
+		return domain.User{}, err
+	}
+	if err = repo.cache.Del(ctx, targetId); err != nil {
+		return domain.User{}, err
+	}
+	return repo.entityToDomain(ue), nil
+}
+
+func (repo *userRepository) Search(ctx context.Context, filter domain.UserFilter, offset int, limit int) ([]domain.User, int64, error) {
+	ues, total, err := repo.entity.Search(ctx, entity.UserFilter{
+		Email:       filter.Email,
+		Phone:       filter.Phone,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+	}, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	users := make([]domain.User, 0, len(ues))
+	for _, ue := range ues {
+		users = append(users, repo.entityToDomain(ue))
+	}
+	return users, total, nil
+}
+
+// 状态在缓存里，改完数据库删缓存
+func (repo *userRepository) UpdateStatus(ctx context.Context, userId int64, status domain.UserStatus) error {
+	err := repo.entity.UpdateStatus(ctx, userId, status.Status, status.SuspendedUntil, status.StatusReason)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+// 注销状态在缓存里，改完数据库都要删缓存
+func (repo *userRepository) RequestDeletion(ctx context.Context, userId int64, purgeAt int64) error {
+	if err := repo.entity.RequestDeletion(ctx, userId, purgeAt); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+func (repo *userRepository) Restore(ctx context.Context, userId int64) error {
+	if err := repo.entity.Restore(ctx, userId); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
+func (repo *userRepository) FindPurgeable(ctx context.Context, now int64, limit int) ([]int64, error) {
+	return repo.entity.FindPurgeable(ctx, now, limit)
+}
+
+func (repo *userRepository) Purge(ctx context.Context, userId int64) error {
+	if err := repo.entity.Purge(ctx, userId); err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userId)
+}
+
 func (repo *userRepository) FindById(ctx context.Context, userId int64) (domain.User, error) {
 	// 先去缓存里面找
 	u, err := repo.cache.Get(ctx, userId)
@@ -102,7 +278,29 @@ func (repo *userRepository) entityToDomain(ue entity.User) domain.User {
 		CreatetAt:     ue.CreateTime,
 		WeChatOpenId:  ue.WeChatOpenId,
 		WeChatUnionId: ue.WeChatUnionId,
+		Handle:        ue.Handle.String,
+		EmailVerified: ue.EmailVerifyTime > 0,
+		UserStatus: domain.UserStatus{
+			Status:         ue.Status,
+			SuspendedUntil: ue.SuspendUntil,
+			StatusReason:   ue.StatusReason,
+		},
+		DeletedAt: ue.DeleteTime,
+		PurgeAt:   ue.PurgeAfterTime,
+		Avatar:    avatarURLs(ue.Avatar),
+	}
+}
+
+// 数据库里存的是json，解析失败当作没有头像
+func avatarURLs(avatar string) map[string]string {
+	if avatar == "" {
+		return nil
+	}
+	var urls map[string]string
+	if err := json.Unmarshal([]byte(avatar), &urls); err != nil {
+		return nil
 	}
+	return urls
 }
 
 func (repo *userRepository) domainToEntity(ud domain.User) entity.User {
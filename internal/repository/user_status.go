@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+// 账号状态，先查本地缓存，再走用户资料的redis缓存
+type UserStatusRepository interface {
+	FindStatus(ctx context.Context, userId int64) (domain.AccountStatus, error)
+}
+
+type userStatusRepository struct {
+	users UserRepository
+	local cache.UserStatusCache
+}
+
+func NewUserStatusRepository(users UserRepository, local cache.UserStatusCache) UserStatusRepository {
+	return &userStatusRepository{users: users, local: local}
+}
+
+func (repo *userStatusRepository) FindStatus(ctx context.Context, userId int64) (domain.AccountStatus, error) {
+	s, err := repo.local.Get(ctx, userId)
+	if err == nil {
+		return s, nil
+	}
+	u, err := repo.users.FindById(ctx, userId)
+	if err != nil {
+		return domain.AccountStatus{}, err
+	}
+	s = domain.AccountStatus{UserStatus: u.UserStatus, PendingDeletion: u.Deleted()}
+	// 写回缓存，忽视err
+	_ = repo.local.Set(ctx, userId, s)
+	return s, nil
+}
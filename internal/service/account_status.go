@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"time"
+	"webook/internal/repository"
+)
+
+// 登录中间件每个请求都会查，状态缓存在本地，封禁、注销几秒内生效
+type AccountStatusService interface {
+	// 被封禁、暂停或者正在注销的账号不能使用
+	Unusable(ctx context.Context, userId int64) (bool, error)
+}
+
+type accountStatusService struct {
+	repo repository.UserStatusRepository
+}
+
+func NewAccountStatusService(repo repository.UserStatusRepository) AccountStatusService {
+	return &accountStatusService{repo: repo}
+}
+
+func (s *accountStatusService) Unusable(ctx context.Context, userId int64) (bool, error) {
+	status, err := s.repo.FindStatus(ctx, userId)
+	// 查不到的用户当作不能使用
+	if err == repository.ErrUserNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return status.Unusable(time.Now().UnixMilli()), nil
+}
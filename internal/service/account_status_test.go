@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+	"webook/internal/service/password"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountStatusService_Unusable(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserStatusRepository
+
+		wantUnusable bool
+		wantError    error
+	}{
+		{
+			name: "正常",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{}, nil)
+				return repo
+			},
+		},
+		{
+			name: "永久封禁",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{UserStatus: domain.UserStatus{Status: domain.UserStatusBanned}}, nil)
+				return repo
+			},
+			wantUnusable: true,
+		},
+		{
+			name: "暂停使用中",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{UserStatus: domain.UserStatus{
+					Status:         domain.UserStatusSuspended,
+					SuspendedUntil: now.Add(time.Hour).UnixMilli(),
+				}}, nil)
+				return repo
+			},
+			wantUnusable: true,
+		},
+		{
+			name: "暂停已经过期",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{UserStatus: domain.UserStatus{
+					Status:         domain.UserStatusSuspended,
+					SuspendedUntil: now.Add(-time.Hour).UnixMilli(),
+				}}, nil)
+				return repo
+			},
+		},
+		{
+			name: "正在注销，恢复之前个人访问令牌也不能用",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{PendingDeletion: true}, nil)
+				return repo
+			},
+			wantUnusable: true,
+		},
+		{
+			name: "用户不存在",
+			mock: func(ctrl *gomock.Controller) repository.UserStatusRepository {
+				repo := repomocks.NewMockUserStatusRepository(ctrl)
+				repo.EXPECT().FindStatus(gomock.Any(), int64(1)).Return(domain.AccountStatus{}, repository.ErrUserNotFound)
+				return repo
+			},
+			wantUnusable: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			unusable, err := NewAccountStatusService(tc.mock(ctrl)).Unusable(context.Background(), 1)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantUnusable, unusable)
+		})
+	}
+}
+
+// 所有登录方式都要拦住被封禁的账号，返回用户用来展示原因
+func TestUserService_FindOrCreateBanned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	banned := domain.User{Id: 1, Phone: "13800000000", UserStatus: domain.UserStatus{
+		Status:       domain.UserStatusBanned,
+		StatusReason: "spam",
+	}}
+	repo := repomocks.NewMockUserRepository(ctrl)
+	repo.EXPECT().FindByPhone(gomock.Any(), "13800000000").Return(banned, nil)
+	svc := NewUserService(repo, password.NewHasher(password.NewBcrypt(4)))
+	u, err := svc.FindOrCreate(context.Background(), "13800000000")
+	assert.Equal(t, ErrUserBanned, err)
+	assert.Equal(t, "spam", u.StatusReason)
+}
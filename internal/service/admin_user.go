@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var ErrSuspendUntilPassed = errors.New("暂停使用的截止时间已经过了")
+
+// 管理后台每页的条数
+const (
+	adminPageSize    = 20
+	adminMaxPageSize = 100
+)
+
+// 审计日志里失败原因的最大长度，和表字段一致
+const auditErrorMaxLen = 256
+
+// 下线用户的所有会话，ijwt.Handler实现了这个接口
+type SessionRevoker interface {
+	RevokeAllSessions(ctx context.Context, userId int64) error
+}
+
+// 管理后台的用户管理，每个操作都先写审计日志，写不进去就不执行，执行完再回填结果
+type AdminUserService interface {
+	// page从1开始，返回总数
+	Search(ctx context.Context, op domain.AuditOperator, filter domain.UserFilter, page int, pageSize int) ([]domain.User, int64, error)
+	FindUser(ctx context.Context, op domain.AuditOperator, userId int64) (domain.User, error)
+	// until为0表示永久封禁，否则暂停使用到until，封禁之后下线所有会话
+	Ban(ctx context.Context, op domain.AuditOperator, userId int64, until int64, reason string) error
+	Unban(ctx context.Context, op domain.AuditOperator, userId int64) error
+	// 下线所有会话
+	ForceLogout(ctx context.Context, op domain.AuditOperator, userId int64) error
+	ResetTwoFactor(ctx context.Context, op domain.AuditOperator, userId int64) error
+	// 清空密码，用户只能通过忘记密码重新设置，已经登录的设备也要下线
+	ResetPassword(ctx context.Context, op domain.AuditOperator, userId int64) error
+	// targetId为0表示所有用户
+	AuditLogs(ctx context.Context, targetId int64, page int, pageSize int) ([]domain.AuditLog, int64, error)
+}
+
+type adminUserService struct {
+	repo          repository.UserRepository
+	twoFactorRepo repository.TwoFactorRepository
+	auditRepo     repository.AuditLogRepository
+	sessions      SessionRevoker
+}
+
+func NewAdminUserService(repo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository,
+	auditRepo repository.AuditLogRepository, sessions SessionRevoker) AdminUserService {
+	return &adminUserService{repo: repo, twoFactorRepo: twoFactorRepo, auditRepo: auditRepo, sessions: sessions}
+}
+
+func (s *adminUserService) Search(ctx context.Context, op domain.AuditOperator, filter domain.UserFilter,
+	page int, pageSize int) ([]domain.User, int64, error) {
+	var (
+		users []domain.User
+		total int64
+	)
+	err := s.audited(ctx, op, domain.AuditActionUserSearch, 0, fmt.Sprintf("%+v", filter), func() error {
+		offset, limit := adminPage(page, pageSize)
+		var err error
+		users, total, err = s.repo.Search(ctx, filter, offset, limit)
+		return err
+	})
+	return users, total, err
+}
+
+func (s *adminUserService) FindUser(ctx context.Context, op domain.AuditOperator, userId int64) (domain.User, error) {
+	var u domain.User
+	err := s.audited(ctx, op, domain.AuditActionUserView, userId, "", func() error {
+		var err error
+		u, err = s.repo.FindById(ctx, userId)
+		if err == repository.ErrUserNotFound {
+			return ErrUserNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (s *adminUserService) Ban(ctx context.Context, op domain.AuditOperator, userId int64, until int64, reason string) error {
+	status := domain.UserStatus{Status: domain.UserStatusBanned, StatusReason: reason}
+	if until > 0 {
+		if until <= time.Now().UnixMilli() {
+			return ErrSuspendUntilPassed
+		}
+		status.Status, status.SuspendedUntil = domain.UserStatusSuspended, until
+	}
+	detail := fmt.Sprintf("until=%d reason=%s", until, reason)
+	return s.audited(ctx, op, domain.AuditActionUserBan, userId, detail, func() error {
+		if err := s.updateStatus(ctx, userId, status); err != nil {
+			return err
+		}
+		return s.sessions.RevokeAllSessions(ctx, userId)
+	})
+}
+
+func (s *adminUserService) Unban(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	return s.audited(ctx, op, domain.AuditActionUserUnban, userId, "", func() error {
+		return s.updateStatus(ctx, userId, domain.UserStatus{Status: domain.UserStatusActive})
+	})
+}
+
+func (s *adminUserService) updateStatus(ctx context.Context, userId int64, status domain.UserStatus) error {
+	err := s.repo.UpdateStatus(ctx, userId, status)
+	if err == repository.ErrUserNotFound {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *adminUserService) ForceLogout(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	if _, err := s.repo.FindById(ctx, userId); err != nil {
+		if err == repository.ErrUserNotFound {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return s.audited(ctx, op, domain.AuditActionUserLogout, userId, "", func() error {
+		return s.sessions.RevokeAllSessions(ctx, userId)
+	})
+}
+
+func (s *adminUserService) ResetTwoFactor(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	return s.audited(ctx, op, domain.AuditActionUserResetTwoFactor, userId, "", func() error {
+		return s.twoFactorRepo.Disable(ctx, userId)
+	})
+}
+
+func (s *adminUserService) ResetPassword(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	return s.audited(ctx, op, domain.AuditActionUserResetPassword, userId, "", func() error {
+		if err := s.repo.UpdatePassword(ctx, userId, ""); err != nil {
+			return err
+		}
+		return s.sessions.RevokeAllSessions(ctx, userId)
+	})
+}
+
+func (s *adminUserService) AuditLogs(ctx context.Context, targetId int64, page int, pageSize int) ([]domain.AuditLog, int64, error) {
+	offset, limit := adminPage(page, pageSize)
+	return s.auditRepo.Find(ctx, targetId, offset, limit)
+}
+
+// 先记一条pending的审计日志，写不进去就不执行fn，执行完回填结果
+// 回填失败只打日志，操作已经做了，pending的记录说明结果未知
+func (s *adminUserService) audited(ctx context.Context, op domain.AuditOperator, action string, targetId int64,
+	detail string, fn func() error) error {
+	id, err := s.auditRepo.Create(ctx, domain.AuditLog{
+		OperatorId: op.UserId,
+		Action:     action,
+		TargetId:   targetId,
+		Detail:     detail,
+		Ip:         op.Ip,
+		UserAgent:  op.UserAgent,
+		Result:     domain.AuditResultPending,
+	})
+	if err != nil {
+		return err
+	}
+	err = fn()
+	result, errMsg := domain.AuditResultSuccess, ""
+	if err != nil {
+		result, errMsg = domain.AuditResultFailed, truncateRunes(err.Error(), auditErrorMaxLen)
+	}
+	if er := s.auditRepo.UpdateResult(ctx, id, result, errMsg); er != nil {
+		log.Println("回填审计日志结果失败", id, er)
+	}
+	return err
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func adminPage(page int, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = adminPageSize
+	}
+	if pageSize > adminMaxPageSize {
+		pageSize = adminMaxPageSize
+	}
+	return (page - 1) * pageSize, pageSize
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// 记录下线了哪些用户
+type fakeSessionRevoker struct {
+	revoked []int64
+	err     error
+}
+
+func (f *fakeSessionRevoker) RevokeAllSessions(ctx context.Context, userId int64) error {
+	f.revoked = append(f.revoked, userId)
+	return f.err
+}
+
+func TestAdminUserService_Ban(t *testing.T) {
+	op := domain.AuditOperator{UserId: 100, Ip: "127.0.0.1"}
+	future := time.Now().Add(time.Hour).UnixMilli()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository)
+		// 为0表示永久封禁
+		until     int64
+		revokeErr error
+
+		wantError   error
+		wantRevoked []int64
+	}{
+		{
+			name: "先写审计日志再封禁，下线之后回填成功",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				gomock.InOrder(
+					auditRepo.EXPECT().Create(gomock.Any(), domain.AuditLog{
+						OperatorId: 100,
+						Action:     domain.AuditActionUserBan,
+						TargetId:   1,
+						Detail:     "until=0 reason=spam",
+						Ip:         "127.0.0.1",
+						Result:     domain.AuditResultPending,
+					}).Return(int64(10), nil),
+					repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.UserStatus{
+						Status:       domain.UserStatusBanned,
+						StatusReason: "spam",
+					}).Return(nil),
+					auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultSuccess, "").Return(nil),
+				)
+				return repo, auditRepo
+			},
+			wantRevoked: []int64{1},
+		},
+		{
+			name: "暂停使用",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(10), nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.UserStatus{
+					Status:         domain.UserStatusSuspended,
+					SuspendedUntil: future,
+					StatusReason:   "spam",
+				}).Return(nil)
+				auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultSuccess, "").Return(nil)
+				return repo, auditRepo
+			},
+			until:       future,
+			wantRevoked: []int64{1},
+		},
+		{
+			name: "截止时间已经过了",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				return repomocks.NewMockUserRepository(ctrl), repomocks.NewMockAuditLogRepository(ctrl)
+			},
+			until:     time.Now().Add(-time.Hour).UnixMilli(),
+			wantError: ErrSuspendUntilPassed,
+		},
+		{
+			name: "审计日志写失败不封禁",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db error"))
+				return repomocks.NewMockUserRepository(ctrl), auditRepo
+			},
+			wantError: errors.New("db error"),
+		},
+		{
+			name: "用户不存在，回填失败",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(10), nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), gomock.Any()).Return(repository.ErrUserNotFound)
+				auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultFailed,
+					ErrUserNotFound.Error()).Return(nil)
+				return repo, auditRepo
+			},
+			wantError: ErrUserNotFound,
+		},
+		{
+			name: "下线失败也算操作失败",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(10), nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultFailed, "redis error").Return(nil)
+				return repo, auditRepo
+			},
+			revokeErr:   errors.New("redis error"),
+			wantError:   errors.New("redis error"),
+			wantRevoked: []int64{1},
+		},
+		{
+			name: "回填失败不影响结果",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.AuditLogRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+				auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(10), nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultSuccess, "").
+					Return(errors.New("db error"))
+				return repo, auditRepo
+			},
+			wantRevoked: []int64{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, auditRepo := tc.mock(ctrl)
+			sessions := &fakeSessionRevoker{err: tc.revokeErr}
+			svc := NewAdminUserService(repo, repomocks.NewMockTwoFactorRepository(ctrl), auditRepo, sessions)
+			err := svc.Ban(context.Background(), op, 1, tc.until, "spam")
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantRevoked, sessions.revoked)
+		})
+	}
+}
+
+func TestAdminUserService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockUserRepository(ctrl)
+	auditRepo := repomocks.NewMockAuditLogRepository(ctrl)
+	gomock.InOrder(
+		auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, l domain.AuditLog) {
+				assert.Equal(t, domain.AuditActionUserResetPassword, l.Action)
+			}).Return(int64(10), nil),
+		// 清空密码，只能走忘记密码
+		repo.EXPECT().UpdatePassword(gomock.Any(), int64(1), "").Return(nil),
+		auditRepo.EXPECT().UpdateResult(gomock.Any(), int64(10), domain.AuditResultSuccess, "").Return(nil),
+	)
+	sessions := &fakeSessionRevoker{}
+	svc := NewAdminUserService(repo, repomocks.NewMockTwoFactorRepository(ctrl), auditRepo, sessions)
+	assert.NoError(t, svc.ResetPassword(context.Background(), domain.AuditOperator{UserId: 100}, 1))
+	assert.Equal(t, []int64{1}, sessions.revoked)
+}
+
+func TestAdminPage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		page     int
+		pageSize int
+
+		wantOffset int
+		wantLimit  int
+	}{
+		{name: "默认值", wantOffset: 0, wantLimit: adminPageSize},
+		{name: "第三页", page: 3, pageSize: 10, wantOffset: 20, wantLimit: 10},
+		{name: "每页太多", page: 2, pageSize: 1000, wantOffset: adminMaxPageSize, wantLimit: adminMaxPageSize},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, limit := adminPage(tc.page, tc.pageSize)
+			assert.Equal(t, tc.wantOffset, offset)
+			assert.Equal(t, tc.wantLimit, limit)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"webook/internal/repository"
+	"webook/internal/service/storage"
+)
+
+var (
+	ErrAvatarTooLarge = errors.New("头像图片太大")
+	ErrAvatarType     = errors.New("不支持的图片格式")
+	ErrAvatarInvalid  = errors.New("图片已损坏")
+)
+
+// 生成的头像尺寸，都是正方形
+var avatarVariants = []struct {
+	Name string
+	Size int
+}{
+	{Name: "small", Size: 64},
+	{Name: "medium", Size: 200},
+	{Name: "large", Size: 512},
+}
+
+// 按文件内容识别出来的类型，不信任客户端传的Content-Type
+var avatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// 上传限制
+type AvatarPolicy struct {
+	// 文件大小，字节
+	MaxSize int64
+	// 宽高的上限，防止很小的文件解码出巨大的图片
+	MaxDimension int
+}
+
+type AvatarService interface {
+	// 校验图片，生成各个尺寸保存到存储里，返回新头像的地址
+	Upload(ctx context.Context, userId int64, data []byte) (map[string]string, error)
+	// 清理注销用户时删掉头像文件
+	UserPurgedListener
+}
+
+type avatarService struct {
+	repo    repository.UserRepository
+	storage storage.Service
+	policy  AvatarPolicy
+}
+
+func NewAvatarService(repo repository.UserRepository, storage storage.Service, policy AvatarPolicy) AvatarService {
+	return &avatarService{repo: repo, storage: storage, policy: policy}
+}
+
+func (s *avatarService) Upload(ctx context.Context, userId int64, data []byte) (map[string]string, error) {
+	img, contentType, err := s.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	ext, encode := avatarEncoder(contentType)
+	prefix, err := avatarPrefix(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(avatarVariants))
+	urls := make(map[string]string, len(avatarVariants))
+	for _, v := range avatarVariants {
+		var buf bytes.Buffer
+		if err = encode(&buf, resizeSquare(img, v.Size)); err != nil {
+			s.deleteKeys(ctx, keys)
+			return nil, err
+		}
+		key := fmt.Sprintf("%s_%s.%s", prefix, v.Name, ext)
+		if err = s.storage.Put(ctx, key, "image/"+ext, buf.Bytes()); err != nil {
+			s.deleteKeys(ctx, keys)
+			return nil, err
+		}
+		keys = append(keys, key)
+		urls[v.Name] = s.storage.URL(key)
+	}
+
+	old, err := s.repo.UpdateAvatar(ctx, userId, keys, urls)
+	if err != nil {
+		s.deleteKeys(ctx, keys)
+		return nil, err
+	}
+	s.deleteKeys(ctx, old)
+	return urls, nil
+}
+
+func (s *avatarService) OnUserPurged(ctx context.Context, userId int64) error {
+	old, err := s.repo.UpdateAvatar(ctx, userId, nil, nil)
+	if err != nil {
+		return err
+	}
+	s.deleteKeys(ctx, old)
+	return nil
+}
+
+func (s *avatarService) decode(data []byte) (image.Image, string, error) {
+	if int64(len(data)) > s.policy.MaxSize {
+		return nil, "", ErrAvatarTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !avatarTypes[contentType] {
+		return nil, "", ErrAvatarType
+	}
+	// 先只读头部拿宽高，太大的不解码
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrAvatarInvalid
+	}
+	if cfg.Width > s.policy.MaxDimension || cfg.Height > s.policy.MaxDimension {
+		return nil, "", ErrAvatarTooLarge
+	}
+	// gif只取第一帧
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrAvatarInvalid
+	}
+	return img, contentType, nil
+}
+
+// 旧文件删不掉不影响换头像，只记日志
+func (s *avatarService) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Println("删除头像文件失败", key, err)
+		}
+	}
+}
+
+// 每次上传用新的文件名，CDN上的旧缓存不会串
+func avatarPrefix(userId int64) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("avatars/%d/%s", userId, hex.EncodeToString(buf)), nil
+}
+
+// jpeg还是jpeg，png和gif可能有透明通道，统一转成png
+func avatarEncoder(contentType string) (string, func(buf *bytes.Buffer, img image.Image) error) {
+	if contentType == "image/jpeg" {
+		return "jpeg", func(buf *bytes.Buffer, img image.Image) error {
+			return jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+		}
+	}
+	return "png", func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	}
+}
+
+// 从中间裁出正方形再缩放，缩小时取每个格子里像素的平均值，原图比目标小就不放大
+func resizeSquare(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	if size > side {
+		size = side
+	}
+	// 转成RGBA，后面直接读像素数组
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	offset := image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2)
+	draw.Draw(square, square.Bounds(), src, offset, draw.Src)
+	if size == side {
+		return square
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		y0, y1 := dy*side/size, (dy+1)*side/size
+		for dx := 0; dx < size; dx++ {
+			x0, x1 := dx*side/size, (dx+1)*side/size
+			var r, g, bl, a, n int
+			for y := y0; y < y1; y++ {
+				row := square.Pix[y*square.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r, g, bl, a = r+int(p[0]), g+int(p[1]), bl+int(p[2]), a+int(p[3])
+					n++
+				}
+			}
+			i := dy*dst.Stride + dx*4
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(bl/n), uint8(a/n)
+		}
+	}
+	return dst
+}
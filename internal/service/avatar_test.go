@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// 内存里的存储，测试用
+type memoryStorage struct {
+	files map[string][]byte
+}
+
+func (m *memoryStorage) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	m.files[key] = data
+	return nil
+}
+
+func (m *memoryStorage) Delete(ctx context.Context, key string) error {
+	delete(m.files, key)
+	return nil
+}
+
+func (m *memoryStorage) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func testPNG(t *testing.T, w int, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+var testAvatarPolicy = AvatarPolicy{MaxSize: 1 << 20, MaxDimension: 1024}
+
+func TestAvatarService_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := &memoryStorage{files: map[string][]byte{"avatars/1/old_small.png": []byte("old")}}
+	repo := repomocks.NewMockUserRepository(ctrl)
+	repo.EXPECT().UpdateAvatar(gomock.Any(), int64(1), gomock.Len(3), gomock.Len(3)).
+		Return([]string{"avatars/1/old_small.png"}, nil)
+	svc := NewAvatarService(repo, store, testAvatarPolicy)
+
+	urls, err := svc.Upload(context.Background(), 1, testPNG(t, 600, 300))
+	require.NoError(t, err)
+	// 旧文件删掉了，只剩三个新的
+	assert.Len(t, store.files, 3)
+	for name, size := range map[string]int{"small": 64, "medium": 200, "large": 300} {
+		key := strings.TrimPrefix(urls[name], "https://cdn.example.com/")
+		img, err := png.Decode(bytes.NewReader(store.files[key]))
+		require.NoError(t, err, name)
+		// 裁成正方形，比原图大的不放大
+		assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds(), name)
+	}
+}
+
+func TestAvatarService_UploadInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+
+		wantError error
+	}{
+		{name: "不是图片", data: []byte("hello world"), wantError: ErrAvatarType},
+		{name: "文件太大", data: make([]byte, testAvatarPolicy.MaxSize+1), wantError: ErrAvatarTooLarge},
+		{name: "宽高太大", data: testPNG(t, 2000, 10), wantError: ErrAvatarTooLarge},
+		{name: "图片损坏", data: testPNG(t, 10, 10)[:60], wantError: ErrAvatarInvalid},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := &memoryStorage{files: map[string][]byte{}}
+			svc := NewAvatarService(repomocks.NewMockUserRepository(ctrl), store, testAvatarPolicy)
+			_, err := svc.Upload(context.Background(), 1, tc.data)
+			assert.Equal(t, tc.wantError, err)
+			assert.Empty(t, store.files)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package local
+
+import (
+	"context"
+)
+
+// 本地开发用，不接第三方验证码服务，只要带了票据就算通过，不能用于线上
+type Service struct {
+}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Verify(ctx context.Context, ticket string, ip string) (bool, error) {
+	return ticket != "", nil
+}
@@ -0,0 +1,61 @@
+package siteverify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 兼容siteverify协议的人机验证服务，reCAPTCHA、hCaptcha、Cloudflare Turnstile都是这个协议
+type Service struct {
+	client *http.Client
+	// 比如 https://challenges.cloudflare.com/turnstile/v0/siteverify
+	verifyURL string
+	secret    string
+}
+
+func NewService(verifyURL string, secret string) (*Service, error) {
+	if verifyURL == "" || secret == "" {
+		return nil, errors.New("人机验证缺少校验地址或密钥")
+	}
+	return &Service{
+		client:    &http.Client{Timeout: time.Second * 5},
+		verifyURL: verifyURL,
+		secret:    secret,
+	}, nil
+}
+
+func (s *Service) Verify(ctx context.Context, ticket string, ip string) (bool, error) {
+	if ticket == "" {
+		return false, nil
+	}
+	form := url.Values{}
+	form.Set("secret", s.secret)
+	form.Set("response", ticket)
+	form.Set("remoteip", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("人机验证服务返回 %d", resp.StatusCode)
+	}
+	var res struct {
+		Success bool `json:"success"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return false, err
+	}
+	return res.Success, nil
+}
@@ -0,0 +1,49 @@
+package siteverify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "1.2.3.4", r.PostForm.Get("remoteip"))
+		if r.PostForm.Get("response") == "good" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer server.Close()
+
+	svc, err := NewService(server.URL, "secret")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		ticket string
+		wantOk bool
+	}{
+		{name: "票据有效", ticket: "good", wantOk: true},
+		{name: "随便填的票据", ticket: "x"},
+		{name: "没有票据", ticket: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := svc.Verify(context.Background(), tc.ticket, "1.2.3.4")
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOk, ok)
+		})
+	}
+
+	// 没有密钥不能创建
+	_, err = NewService(server.URL, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,8 @@
+package captcha
+
+import "context"
+
+type Service interface {
+	// 校验前端人机验证拿到的票据，ip是用户的ip
+	Verify(ctx context.Context, ticket string, ip string) (bool, error)
+}
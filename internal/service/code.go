@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"webook/internal/repository"
+	"webook/internal/service/email"
 	"webook/internal/service/sms"
 )
 
@@ -42,7 +43,7 @@ func NewCodeService(r repository.CodeRepository, sms sms.Service) CodeService {
 // 4. 发送
 // 由于在redis中存储code，故按照分层，需要cache和repo
 func (c *codeService) Send(ctx context.Context, biz string, phone string) error {
-	code := c.generateCode()
+	code := generateCode()
 	err := c.repo.Send(ctx, biz, phone, code)
 	if err != nil {
 		// 存在问题
@@ -62,9 +63,51 @@ func (c *codeService) Verify(ctx context.Context,
 	return ok, nil
 }
 
-func (c *codeService) generateCode() string {
+func generateCode() string {
 	// 产生0-999999的随机数
 	code := rand.Intn(1000000)
 	// 不足6位，前面补0
 	return fmt.Sprintf("%06d", code)
 }
+
+// 邮箱验证码，和短信验证码共用CodeRepository，发送频率和校验次数的限制一样
+// biz要和短信的区分开，比如reset_password
+type EmailCodeService interface {
+	Send(ctx context.Context, biz string, email string) error
+	Verify(ctx context.Context, biz string, email string, code string) (bool, error)
+}
+
+// 不同业务的邮件里怎么描述用途
+var emailCodePurposes = map[string]string{
+	"reset_password": "重置密码",
+	"verify_email":   "验证注册邮箱",
+}
+
+type emailCodeService struct {
+	repo  repository.CodeRepository
+	email email.Service
+}
+
+func NewEmailCodeService(r repository.CodeRepository, email email.Service) EmailCodeService {
+	return &emailCodeService{
+		repo:  r,
+		email: email,
+	}
+}
+
+func (c *emailCodeService) Send(ctx context.Context, biz string, addr string) error {
+	code := generateCode()
+	if err := c.repo.Send(ctx, biz, addr, code); err != nil {
+		return err
+	}
+	purpose, ok := emailCodePurposes[biz]
+	if !ok {
+		purpose = "验证身份"
+	}
+	body := fmt.Sprintf("你正在%s，验证码是 %s，10分钟内有效。如果不是你本人操作，请忽略这封邮件。", purpose, code)
+	return c.email.Send(ctx, addr, "webook验证码", body)
+}
+
+func (c *emailCodeService) Verify(ctx context.Context, biz string, addr string, code string) (bool, error) {
+	return c.repo.Verify(ctx, biz, addr, code)
+}
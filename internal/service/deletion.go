@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"webook/internal/repository"
+)
+
+// 每批清理的用户数
+const purgeBatchSize = 100
+
+// 用户的数据不全在users表里（比如存储里的头像文件），这些模块实现这个接口
+// 在抹掉users表之前调用，要求幂等，失败了下一轮清理会重试
+type UserPurgedListener interface {
+	OnUserPurged(ctx context.Context, userId int64) error
+}
+
+// 用户自助注销
+type AccountDeletionService interface {
+	// 申请注销，返回恢复期的截止时间
+	RequestDeletion(ctx context.Context, userId int64) (int64, error)
+	// 清理所有过了恢复期的用户，返回清理的个数
+	PurgeExpired(ctx context.Context) (int, error)
+	// 定时清理，阻塞直到ctx结束
+	RunPurge(ctx context.Context, interval time.Duration)
+}
+
+type accountDeletionService struct {
+	repo repository.UserRepository
+	// 恢复期，期间登录可以恢复账号
+	gracePeriod time.Duration
+	listeners   []UserPurgedListener
+}
+
+func NewAccountDeletionService(repo repository.UserRepository, gracePeriod time.Duration,
+	listeners []UserPurgedListener) AccountDeletionService {
+	return &accountDeletionService{repo: repo, gracePeriod: gracePeriod, listeners: listeners}
+}
+
+func (s *accountDeletionService) RequestDeletion(ctx context.Context, userId int64) (int64, error) {
+	purgeAt := time.Now().Add(s.gracePeriod).UnixMilli()
+	err := s.repo.RequestDeletion(ctx, userId, purgeAt)
+	if err == repository.ErrUserNotFound {
+		return 0, ErrUserNotFound
+	}
+	return purgeAt, err
+}
+
+func (s *accountDeletionService) PurgeExpired(ctx context.Context) (int, error) {
+	count := 0
+	for {
+		ids, err := s.repo.FindPurgeable(ctx, time.Now().UnixMilli(), purgeBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, id := range ids {
+			for _, l := range s.listeners {
+				if err = l.OnUserPurged(ctx, id); err != nil {
+					return count, err
+				}
+			}
+			if err = s.repo.Purge(ctx, id); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(ids) < purgeBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// 多个实例同时跑也没关系，清理是幂等的
+func (s *accountDeletionService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PurgeExpired(ctx)
+			if err != nil {
+				log.Println("清理注销用户失败", err)
+			}
+			if n > 0 {
+				log.Printf("清理了%d个注销用户", n)
+			}
+		}
+	}
+}
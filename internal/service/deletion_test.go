@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+	"webook/internal/service/password"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountDeletionService_PurgeExpired(t *testing.T) {
+	full := make([]int64, purgeBatchSize)
+	for i := range full {
+		full[i] = int64(i + 1)
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+		// 为空表示没有监听者
+		listener purgeListenerFunc
+
+		wantCount int
+		wantError error
+	}{
+		{
+			name: "一批清理完",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindPurgeable(gomock.Any(), gomock.Any(), purgeBatchSize).Return([]int64{1, 2}, nil)
+				repo.EXPECT().Purge(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().Purge(gomock.Any(), int64(2)).Return(nil)
+				return repo
+			},
+			wantCount: 2,
+		},
+		{
+			name: "满一批继续查下一批",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				gomock.InOrder(
+					repo.EXPECT().FindPurgeable(gomock.Any(), gomock.Any(), purgeBatchSize).Return(full, nil),
+					repo.EXPECT().FindPurgeable(gomock.Any(), gomock.Any(), purgeBatchSize).Return(nil, nil),
+				)
+				repo.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil).Times(purgeBatchSize)
+				return repo
+			},
+			wantCount: purgeBatchSize,
+		},
+		{
+			name: "清理失败",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindPurgeable(gomock.Any(), gomock.Any(), purgeBatchSize).Return([]int64{1, 2}, nil)
+				repo.EXPECT().Purge(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().Purge(gomock.Any(), int64(2)).Return(errors.New("mock db error"))
+				return repo
+			},
+			wantCount: 1,
+			wantError: errors.New("mock db error"),
+		},
+		{
+			name: "监听者失败，不抹掉users表",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindPurgeable(gomock.Any(), gomock.Any(), purgeBatchSize).Return([]int64{1, 2}, nil)
+				repo.EXPECT().Purge(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			listener: func(ctx context.Context, userId int64) error {
+				if userId == 2 {
+					return errors.New("mock storage error")
+				}
+				return nil
+			},
+			wantCount: 1,
+			wantError: errors.New("mock storage error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			var listeners []UserPurgedListener
+			if tc.listener != nil {
+				listeners = append(listeners, tc.listener)
+			}
+			svc := NewAccountDeletionService(tc.mock(ctrl), time.Hour, listeners)
+			n, err := svc.PurgeExpired(context.Background())
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantCount, n)
+		})
+	}
+}
+
+type purgeListenerFunc func(ctx context.Context, userId int64) error
+
+func (f purgeListenerFunc) OnUserPurged(ctx context.Context, userId int64) error {
+	return f(ctx, userId)
+}
+
+// 第一步登录只检查，不恢复账号
+func TestUserService_FindOrCreateDeleted(t *testing.T) {
+	const phone = "13800000000"
+	now := time.Now().UnixMilli()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		wantUser  domain.User
+		wantError error
+	}{
+		{
+			name: "恢复期内登录，等完整登录之后再恢复",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), phone).
+					Return(domain.User{Id: 1, Phone: phone, DeletedAt: now, PurgeAt: now + 100000}, nil)
+				return repo
+			},
+			wantUser: domain.User{Id: 1, Phone: phone, DeletedAt: now, PurgeAt: now + 100000},
+		},
+		{
+			name: "过了恢复期",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByPhone(gomock.Any(), phone).
+					Return(domain.User{Id: 1, Phone: phone, DeletedAt: now - 200000, PurgeAt: now - 100000}, nil)
+				return repo
+			},
+			wantError: ErrUserDeleted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl), password.NewHasher(password.NewBcrypt(10)))
+			u, err := svc.FindOrCreate(context.Background(), phone)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantUser, u)
+		})
+	}
+}
+
+// 完整登录之后恢复账号
+func TestUserService_Restore(t *testing.T) {
+	now := time.Now().UnixMilli()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		wantError error
+	}{
+		{
+			name: "恢复期内登录",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.User{Id: 1, DeletedAt: now, PurgeAt: now + 100000}, nil)
+				repo.EXPECT().Restore(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "没有注销",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1}, nil)
+				return repo
+			},
+		},
+		{
+			name: "两步验证的时候过了恢复期",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.User{Id: 1, DeletedAt: now - 200000, PurgeAt: now - 100000}, nil)
+				return repo
+			},
+			wantError: ErrUserDeleted,
+		},
+		{
+			name: "恢复的时候刚好被清理",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.User{Id: 1, DeletedAt: now, PurgeAt: now + 100000}, nil)
+				repo.EXPECT().Restore(gomock.Any(), int64(1)).Return(repository.ErrUserNotFound)
+				return repo
+			},
+			wantError: ErrUserDeleted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserService(tc.mock(ctrl), password.NewHasher(password.NewBcrypt(10)))
+			assert.Equal(t, tc.wantError, svc.Restore(context.Background(), 1))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// 不真正发邮件，只记在内存里并打印出来，本地开发和测试用
+type MemoryService struct {
+	lock     sync.Mutex
+	messages []Message
+}
+
+func NewMemoryService() *MemoryService {
+	return &MemoryService{}
+}
+
+func (s *MemoryService) Send(ctx context.Context, to string, subject string, body string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.messages = append(s.messages, Message{To: to, Subject: subject, Body: body})
+	fmt.Printf("发送邮件给 %s：%s\n%s\n", to, subject, body)
+	return nil
+}
+
+// 已经发送的邮件，按发送顺序
+func (s *MemoryService) Messages() []Message {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	res := make([]Message, len(s.messages))
+	copy(res, s.messages)
+	return res
+}
@@ -0,0 +1,60 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidHeader = errors.New("邮件头不能包含换行")
+
+// 通过SMTP服务器发送邮件，服务器支持STARTTLS时会自动加密
+type Service struct {
+	addr string
+	auth smtp.Auth
+	// 邮件头里的发件人，可以带名字
+	from string
+	// SMTP协议里的发件地址，只能是邮箱
+	envelopeFrom string
+}
+
+func NewService(host string, port int, username string, password string, from string) *Service {
+	envelopeFrom := from
+	if addr, err := mail.ParseAddress(from); err == nil {
+		envelopeFrom = addr.Address
+	}
+	return &Service{
+		addr:         net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:         smtp.PlainAuth("", username, password, host),
+		from:         from,
+		envelopeFrom: envelopeFrom,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, to string, subject string, body string) error {
+	// 防止邮件头注入
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	// net/smtp不支持ctx，发送前检查一下有没有被取消
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.envelopeFrom, []string{to}, msg.Bytes())
+}
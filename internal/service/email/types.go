@@ -0,0 +1,8 @@
+package email
+
+import "context"
+
+type Service interface {
+	// ctx，收件人，主题，纯文本正文
+	Send(ctx context.Context, to string, subject string, body string) error
+}
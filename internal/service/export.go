@@ -0,0 +1,371 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+const (
+	// 生成归档的超时时间
+	exportBuildTimeout = time.Minute * 5
+	// 超过这个时间还在生成中，认为生成的实例挂了，可以重新申请
+	exportStuckAfter = time.Minute * 30
+	// 这个时间内生成过的直接复用，避免反复打包
+	exportReuseWithin = time.Hour
+	// 每批清理的过期归档数
+	exportCleanupBatchSize = 100
+	// 关注关系按页读出来
+	exportFollowPageSize = 500
+	// 下载地址
+	exportDownloadPath = "/user/export/download"
+)
+
+var (
+	ErrDataExportNotFound = errors.New("导出不存在")
+	ErrDataExportNotReady = errors.New("导出还没有生成好")
+	ErrDownloadLinkWrong  = errors.New("下载链接无效或已过期")
+)
+
+// 个人数据导出，把存储的关于用户的所有数据打包成zip
+type DataExportService interface {
+	// 异步生成归档，有正在生成的、或者刚生成好的就直接返回它
+	Request(ctx context.Context, userId int64) (domain.DataExport, error)
+	Find(ctx context.Context, userId int64, id int64) (domain.DataExport, error)
+	// 带签名、会过期的下载地址，只有生成好的导出才有
+	DownloadURL(export domain.DataExport) (string, error)
+	// 校验下载地址上的签名，返回归档文件
+	Open(ctx context.Context, id int64, expires int64, sig string) (domain.DataExport, error)
+	// 删除过期的归档，阻塞直到ctx结束
+	RunCleanup(ctx context.Context, interval time.Duration)
+	// 注销清理时删掉归档文件，不等过期
+	UserPurgedListener
+}
+
+// 导出时需要读的数据
+type DataExportSources struct {
+	Users      repository.UserRepository
+	Identities repository.IdentityRepository
+	Security   repository.SecurityEventRepository
+	Sms        repository.SmsRepository
+	Tokens     repository.PersonalTokenRepository
+	Logins     repository.LoginRecordRepository
+	Follows    repository.FollowRepository
+}
+
+type dataExportService struct {
+	repo    repository.DataExportRepository
+	sources DataExportSources
+	// 归档文件存放的目录
+	dir string
+	// 下载地址的签名密钥
+	signKey []byte
+	// 归档保留多久
+	retention time.Duration
+	// 下载地址多久过期
+	linkExpiration time.Duration
+}
+
+func NewDataExportService(repo repository.DataExportRepository, sources DataExportSources, dir string,
+	signKey []byte, retention time.Duration, linkExpiration time.Duration) DataExportService {
+	return &dataExportService{
+		repo:           repo,
+		sources:        sources,
+		dir:            dir,
+		signKey:        signKey,
+		retention:      retention,
+		linkExpiration: linkExpiration,
+	}
+}
+
+func (s *dataExportService) Request(ctx context.Context, userId int64) (domain.DataExport, error) {
+	latest, err := s.repo.FindLatest(ctx, userId)
+	switch err {
+	case nil:
+		age := time.Since(time.UnixMilli(latest.CreateAt))
+		if latest.Status == domain.DataExportPending && age < exportStuckAfter ||
+			latest.Status == domain.DataExportReady && age < exportReuseWithin {
+			return latest, nil
+		}
+	case repository.ErrDataExportNotFound:
+	default:
+		return domain.DataExport{}, err
+	}
+	export, err := s.repo.Create(ctx, userId)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), exportBuildTimeout)
+		defer cancel()
+		if er := s.build(ctx, export); er != nil {
+			log.Println("生成个人数据导出失败", export.Id, er)
+			// 可能是超时了，不能再用原来的ctx
+			if er = s.repo.MarkFailed(context.Background(), export.Id); er != nil {
+				log.Println("标记导出失败出错", export.Id, er)
+			}
+		}
+	}()
+	return export, nil
+}
+
+func (s *dataExportService) Find(ctx context.Context, userId int64, id int64) (domain.DataExport, error) {
+	export, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrDataExportNotFound {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	// 不能看别人的导出
+	if export.UserId != userId {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	return export, nil
+}
+
+func (s *dataExportService) DownloadURL(export domain.DataExport) (string, error) {
+	if export.Status != domain.DataExportReady {
+		return "", ErrDataExportNotReady
+	}
+	expires := time.Now().Add(s.linkExpiration).UnixMilli()
+	// 链接不能比归档活得久
+	if expires > export.ExpireAt {
+		expires = export.ExpireAt
+	}
+	q := url.Values{}
+	q.Set("id", strconv.FormatInt(export.Id, 10))
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.sign(export.Id, expires))
+	return exportDownloadPath + "?" + q.Encode(), nil
+}
+
+func (s *dataExportService) Open(ctx context.Context, id int64, expires int64, sig string) (domain.DataExport, error) {
+	if !hmac.Equal([]byte(sig), []byte(s.sign(id, expires))) || time.Now().UnixMilli() >= expires {
+		return domain.DataExport{}, ErrDownloadLinkWrong
+	}
+	export, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrDataExportNotFound {
+		return domain.DataExport{}, ErrDownloadLinkWrong
+	}
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	if export.Status != domain.DataExportReady || time.Now().UnixMilli() >= export.ExpireAt {
+		return domain.DataExport{}, ErrDownloadLinkWrong
+	}
+	return export, nil
+}
+
+func (s *dataExportService) sign(id int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.signKey)
+	_, _ = fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 收集数据写到zip里，文件名带随机串，防止被猜到
+func (s *dataExportService) build(ctx context.Context, export domain.DataExport) error {
+	files, err := s.collect(ctx, export.UserId)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	suffix := make([]byte, 16)
+	if _, err = rand.Read(suffix); err != nil {
+		return err
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("%d-%s.zip", export.Id, hex.EncodeToString(suffix)))
+	// 先写临时文件，写完再改名，下载时不会拿到半个文件
+	tmp := name + ".tmp"
+	if err = writeZip(tmp, files); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err = os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	expireAt := time.Now().Add(s.retention).UnixMilli()
+	if err = s.repo.MarkReady(ctx, export.Id, name, expireAt); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return nil
+}
+
+// 导出的短信记录，模板参数里有验证码，不导出
+type exportedSms struct {
+	Id      int64    `json:"id"`
+	TplId   string   `json:"tplId"`
+	Numbers []string `json:"numbers"`
+	Status  int      `json:"status"`
+}
+
+// 文件名到内容
+func (s *dataExportService) collect(ctx context.Context, userId int64) (map[string]any, error) {
+	u, err := s.sources.Users.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.sources.Identities.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.sources.Security.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.sources.Tokens.FindByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	logins, err := s.sources.Logins.FindAllByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := collectFollows(ctx, userId, s.sources.Follows.FindFollowers)
+	if err != nil {
+		return nil, err
+	}
+	followees, err := collectFollows(ctx, userId, s.sources.Follows.FindFollowees)
+	if err != nil {
+		return nil, err
+	}
+	sms := []exportedSms{}
+	for _, i := range identities {
+		if i.Provider != domain.IdentityPhone {
+			continue
+		}
+		records, err := s.sources.Sms.FindByNumber(ctx, i.Subject)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			sms = append(sms, exportedSms{Id: r.Id, TplId: r.TplId, Numbers: []string{i.Subject}, Status: int(r.Status)})
+		}
+	}
+	return map[string]any{
+		"profile.json":         u,
+		"identities.json":      identities,
+		"security_events.json": events,
+		"sms.json":             sms,
+		"personal_tokens.json": tokens,
+		"login_history.json":   logins,
+		"followers.json":       followers,
+		"followees.json":       followees,
+	}, nil
+}
+
+// 按游标翻完所有的关注关系
+func collectFollows(ctx context.Context, userId int64,
+	find func(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, error)) ([]domain.FollowRelation, error) {
+	res := []domain.FollowRelation{}
+	var cursor int64
+	for {
+		page, err := find(ctx, userId, cursor, exportFollowPageSize)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		if len(page) < exportFollowPageSize {
+			return res, nil
+		}
+		cursor = page[len(page)-1].Id
+	}
+}
+
+func writeZip(name string, files map[string]any) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for fileName, content := range files {
+		fw, err := w.Create(fileName)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(content); err != nil {
+			return err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// 删掉过期的归档文件和记录
+func (s *dataExportService) cleanup(ctx context.Context) (int, error) {
+	count := 0
+	for {
+		exports, err := s.repo.FindExpired(ctx, time.Now().UnixMilli(), exportCleanupBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, e := range exports {
+			if err = os.Remove(e.File); err != nil && !os.IsNotExist(err) {
+				return count, err
+			}
+			if err = s.repo.Delete(ctx, e.Id); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(exports) < exportCleanupBatchSize {
+			return count, nil
+		}
+	}
+}
+
+func (s *dataExportService) OnUserPurged(ctx context.Context, userId int64) error {
+	exports, err := s.repo.FindByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, e := range exports {
+		if e.File != "" {
+			if err = os.Remove(e.File); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err = s.repo.Delete(ctx, e.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *dataExportService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.cleanup(ctx); err != nil {
+				log.Println("清理过期的个人数据导出失败", err)
+			}
+		}
+	}
+}
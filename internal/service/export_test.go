@@ -0,0 +1,200 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/repository/entity"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDataExportService_Build(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := repomocks.NewMockUserRepository(ctrl)
+	users.EXPECT().FindById(gomock.Any(), int64(9)).Return(domain.User{Id: 9, Phone: "13800000000"}, nil)
+	identities := repomocks.NewMockIdentityRepository(ctrl)
+	identities.EXPECT().FindByUser(gomock.Any(), int64(9)).Return([]domain.Identity{
+		{Id: 1, Provider: domain.IdentityPhone, Subject: "13800000000"},
+		{Id: 2, Provider: domain.IdentityWeChat, Subject: "openid"},
+	}, nil)
+	security := repomocks.NewMockSecurityEventRepository(ctrl)
+	security.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(nil, nil)
+	sms := repomocks.NewMockSmsRepository(ctrl)
+	// 只查手机号的短信记录
+	sms.EXPECT().FindByNumber(gomock.Any(), "13800000000").
+		Return([]entity.SMS{{Id: 3, TplId: "123", Args: []string{"123456"}}}, nil)
+	tokens := repomocks.NewMockPersonalTokenRepository(ctrl)
+	tokens.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(nil, nil)
+	logins := repomocks.NewMockLoginRecordRepository(ctrl)
+	logins.EXPECT().FindAllByUser(gomock.Any(), int64(9)).Return(nil, nil)
+	// 关注关系要翻页读完
+	follows := repomocks.NewMockFollowRepository(ctrl)
+	fullPage := make([]domain.FollowRelation, exportFollowPageSize)
+	for i := range fullPage {
+		fullPage[i] = domain.FollowRelation{Id: int64(1000 - i), Follower: int64(100 + i), Followee: 9}
+	}
+	follows.EXPECT().FindFollowers(gomock.Any(), int64(9), int64(0), exportFollowPageSize).Return(fullPage, nil)
+	follows.EXPECT().FindFollowers(gomock.Any(), int64(9), fullPage[len(fullPage)-1].Id, exportFollowPageSize).
+		Return([]domain.FollowRelation{{Id: 1, Follower: 10, Followee: 9}}, nil)
+	follows.EXPECT().FindFollowees(gomock.Any(), int64(9), int64(0), exportFollowPageSize).
+		Return([]domain.FollowRelation{{Id: 2, Follower: 9, Followee: 11}}, nil)
+	repo := repomocks.NewMockDataExportRepository(ctrl)
+	var file string
+	repo.EXPECT().MarkReady(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64, f string, expireAt int64) error {
+			file = f
+			return nil
+		})
+
+	svc := NewDataExportService(repo, DataExportSources{
+		Users:      users,
+		Identities: identities,
+		Security:   security,
+		Sms:        sms,
+		Tokens:     tokens,
+		Logins:     logins,
+		Follows:    follows,
+	}, t.TempDir(), []byte("key"), time.Hour, time.Minute).(*dataExportService)
+	err := svc.build(context.Background(), domain.DataExport{Id: 1, UserId: 9})
+	require.NoError(t, err)
+
+	r, err := zip.OpenReader(file)
+	require.NoError(t, err)
+	defer r.Close()
+	var names []string
+	files := map[string]*zip.File{}
+	for _, f := range r.File {
+		names = append(names, f.Name)
+		files[f.Name] = f
+	}
+	assert.ElementsMatch(t, []string{"profile.json", "identities.json", "security_events.json",
+		"sms.json", "personal_tokens.json", "login_history.json", "followers.json", "followees.json"}, names)
+
+	var followers, followees []domain.FollowRelation
+	readZipJSON(t, files["followers.json"], &followers)
+	readZipJSON(t, files["followees.json"], &followees)
+	assert.Len(t, followers, exportFollowPageSize+1)
+	assert.Equal(t, []domain.FollowRelation{{Id: 2, Follower: 9, Followee: 11}}, followees)
+}
+
+func readZipJSON(t *testing.T, f *zip.File, v any) {
+	require.NotNil(t, f)
+	rc, err := f.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	require.NoError(t, json.NewDecoder(rc).Decode(v))
+}
+
+func TestDataExportService_Open(t *testing.T) {
+	now := time.Now()
+	ready := domain.DataExport{Id: 1, UserId: 9, Status: domain.DataExportReady,
+		File: "/tmp/1.zip", ExpireAt: now.Add(time.Hour).UnixMilli()}
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.DataExportRepository
+		// 改动签名好的下载地址
+		tamper func(q url.Values)
+
+		wantError error
+	}{
+		{
+			name: "签名正确",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				repo := repomocks.NewMockDataExportRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(ready, nil)
+				return repo
+			},
+			tamper: func(q url.Values) {},
+		},
+		{
+			name: "改了id",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				return repomocks.NewMockDataExportRepository(ctrl)
+			},
+			tamper: func(q url.Values) {
+				q.Set("id", "2")
+			},
+			wantError: ErrDownloadLinkWrong,
+		},
+		{
+			name: "延长了过期时间",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				return repomocks.NewMockDataExportRepository(ctrl)
+			},
+			tamper: func(q url.Values) {
+				q.Set("expires", strconv.FormatInt(now.Add(time.Hour*24).UnixMilli(), 10))
+			},
+			wantError: ErrDownloadLinkWrong,
+		},
+		{
+			name: "链接过期",
+			mock: func(ctrl *gomock.Controller) repository.DataExportRepository {
+				return repomocks.NewMockDataExportRepository(ctrl)
+			},
+			tamper: func(q url.Values) {
+				expires := now.Add(-time.Minute).UnixMilli()
+				q.Set("expires", strconv.FormatInt(expires, 10))
+				q.Set("sig", (&dataExportService{signKey: []byte("key")}).sign(1, expires))
+			},
+			wantError: ErrDownloadLinkWrong,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewDataExportService(tc.mock(ctrl), DataExportSources{}, t.TempDir(),
+				[]byte("key"), time.Hour, time.Minute)
+			link, err := svc.DownloadURL(ready)
+			require.NoError(t, err)
+			u, err := url.Parse(link)
+			require.NoError(t, err)
+			q := u.Query()
+			tc.tamper(q)
+			id, _ := strconv.ParseInt(q.Get("id"), 10, 64)
+			expires, _ := strconv.ParseInt(q.Get("expires"), 10, 64)
+			_, err = svc.Open(context.Background(), id, expires, q.Get("sig"))
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
+
+// 注销清理时归档文件和记录都删掉，文件已经不在了也不算错
+func TestDataExportService_OnUserPurged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1.zip")
+	require.NoError(t, os.WriteFile(file, []byte("zip"), 0o600))
+
+	repo := repomocks.NewMockDataExportRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return([]domain.DataExport{
+		{Id: 1, UserId: 9, Status: domain.DataExportReady, File: file},
+		{Id: 2, UserId: 9, Status: domain.DataExportReady, File: filepath.Join(dir, "2.zip")},
+		{Id: 3, UserId: 9, Status: domain.DataExportPending},
+	}, nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+
+	svc := NewDataExportService(repo, DataExportSources{}, dir, []byte("key"), time.Hour, time.Minute)
+	require.NoError(t, svc.OnUserPurged(context.Background(), 9))
+	_, err := os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// 关注列表每页的条数
+const (
+	followPageSize    = 20
+	followMaxPageSize = 100
+)
+
+var ErrFollowSelf = errors.New("不能关注自己")
+
+// 关注关系
+type FollowService interface {
+	// 重复关注、重复取消都当作成功
+	Follow(ctx context.Context, follower int64, followee int64) error
+	Unfollow(ctx context.Context, follower int64, followee int64) error
+	// cursor为0表示第一页，返回下一页的cursor，0表示没有下一页
+	Followers(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, int64, error)
+	Followees(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, int64, error)
+	Following(ctx context.Context, follower int64, followee int64) (bool, error)
+	Statistic(ctx context.Context, userId int64) (domain.FollowStatistic, error)
+	// 注销清理时删掉关注关系
+	UserPurgedListener
+	// 合并账号时把关注关系挪过去
+	UserMergedListener
+}
+
+type followService struct {
+	repo     repository.FollowRepository
+	userRepo repository.UserRepository
+}
+
+func NewFollowService(repo repository.FollowRepository, userRepo repository.UserRepository) FollowService {
+	return &followService{repo: repo, userRepo: userRepo}
+}
+
+func (s *followService) Follow(ctx context.Context, follower int64, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	// 正在注销的用户不能被关注
+	u, err := s.userRepo.FindById(ctx, followee)
+	if err == repository.ErrUserNotFound || (err == nil && u.Deleted()) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	err = s.repo.Follow(ctx, follower, followee)
+	if err == repository.ErrFollowExists {
+		return nil
+	}
+	return err
+}
+
+func (s *followService) Unfollow(ctx context.Context, follower int64, followee int64) error {
+	err := s.repo.Unfollow(ctx, follower, followee)
+	if err == repository.ErrFollowNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *followService) Followers(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, int64, error) {
+	limit = followLimit(limit)
+	res, err := s.repo.FindFollowers(ctx, userId, cursor, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, nextFollowCursor(res, limit), nil
+}
+
+func (s *followService) Followees(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, int64, error) {
+	limit = followLimit(limit)
+	res, err := s.repo.FindFollowees(ctx, userId, cursor, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, nextFollowCursor(res, limit), nil
+}
+
+func (s *followService) Following(ctx context.Context, follower int64, followee int64) (bool, error) {
+	return s.repo.Following(ctx, follower, followee)
+}
+
+func (s *followService) Statistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	return s.repo.FindStatistic(ctx, userId)
+}
+
+func (s *followService) OnUserPurged(ctx context.Context, userId int64) error {
+	return s.repo.RemoveUser(ctx, userId)
+}
+
+func (s *followService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	return s.repo.MoveUser(ctx, evt.SourceId, evt.TargetId)
+}
+
+func followLimit(limit int) int {
+	if limit <= 0 {
+		return followPageSize
+	}
+	if limit > followMaxPageSize {
+		return followMaxPageSize
+	}
+	return limit
+}
+
+// 不满一页说明后面没有了
+func nextFollowCursor(res []domain.FollowRelation, limit int) int64 {
+	if len(res) < limit {
+		return 0
+	}
+	return res[len(res)-1].Id
+}
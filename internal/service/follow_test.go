@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFollowService_Follow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository)
+		followee int64
+
+		wantError error
+	}{
+		{
+			name: "关注成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(nil)
+				return repo, userRepo
+			},
+			followee: 2,
+		},
+		{
+			name: "重复关注当作成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(repository.ErrFollowExists)
+				return repo, userRepo
+			},
+			followee: 2,
+		},
+		{
+			name: "关注自己",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				return repomocks.NewMockFollowRepository(ctrl), repomocks.NewMockUserRepository(ctrl)
+			},
+			followee:  1,
+			wantError: ErrFollowSelf,
+		},
+		{
+			name: "用户不存在",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{}, repository.ErrUserNotFound)
+				return repomocks.NewMockFollowRepository(ctrl), userRepo
+			},
+			followee:  2,
+			wantError: ErrUserNotFound,
+		},
+		{
+			name: "用户正在注销",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2, DeletedAt: 1}, nil)
+				return repomocks.NewMockFollowRepository(ctrl), userRepo
+			},
+			followee:  2,
+			wantError: ErrUserNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowService(tc.mock(ctrl))
+			err := svc.Follow(context.Background(), 1, tc.followee)
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
+
+func TestFollowService_Followers(t *testing.T) {
+	page := func(n int) []domain.FollowRelation {
+		res := make([]domain.FollowRelation, n)
+		for i := range res {
+			res[i] = domain.FollowRelation{Id: int64(100 - i), Followee: 1}
+		}
+		return res
+	}
+	testCases := []struct {
+		name  string
+		mock  func(ctrl *gomock.Controller) repository.FollowRepository
+		limit int
+
+		wantCount  int
+		wantCursor int64
+	}{
+		{
+			name: "满一页返回最后一条的id",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FindFollowers(gomock.Any(), int64(1), int64(0), 2).Return(page(2), nil)
+				return repo
+			},
+			limit:      2,
+			wantCount:  2,
+			wantCursor: 99,
+		},
+		{
+			name: "不满一页没有下一页",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FindFollowers(gomock.Any(), int64(1), int64(0), 2).Return(page(1), nil)
+				return repo
+			},
+			limit:     2,
+			wantCount: 1,
+		},
+		{
+			name: "没传limit用默认值",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FindFollowers(gomock.Any(), int64(1), int64(0), followPageSize).Return(nil, nil)
+				return repo
+			},
+		},
+		{
+			name: "limit太大",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FindFollowers(gomock.Any(), int64(1), int64(0), followMaxPageSize).Return(nil, nil)
+				return repo
+			},
+			limit: 1000,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl))
+			res, cursor, err := svc.Followers(context.Background(), 1, 0, tc.limit)
+			assert.NoError(t, err)
+			assert.Len(t, res, tc.wantCount)
+			assert.Equal(t, tc.wantCursor, cursor)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrIdentityTaken    = errors.New("已经绑定到其他账号")
+	ErrIdentityExists   = errors.New("已经绑定过同类型的登录方式")
+	ErrIdentityNotFound = errors.New("登录方式不存在")
+	ErrLastIdentity     = errors.New("不能解绑最后一种登录方式")
+	ErrBindStateInvalid = errors.New("绑定请求无效或已过期")
+)
+
+// 发起绑定微信之后多久之内要扫码，和state cookie的有效期一致
+const weChatBindTTL = time.Minute * 10
+
+// 账号绑定的登录方式：邮箱、手机号、微信
+type IdentityService interface {
+	List(ctx context.Context, userId int64) ([]domain.Identity, error)
+	// 调用方负责验证手机号，比如短信验证码
+	BindPhone(ctx context.Context, userId int64, phone string) error
+	// 已登录的用户发起绑定微信，记下state对应的用户
+	PrepareWeChatBind(ctx context.Context, state string, userId int64) error
+	// 扫码回调时取出发起绑定的用户，state只能用一次
+	TakeWeChatBind(ctx context.Context, state string) (int64, error)
+	BindWeChat(ctx context.Context, userId int64, info domain.WeChatResult) error
+	Unbind(ctx context.Context, userId int64, id int64) error
+	// 登录方式属于哪个用户
+	Owner(ctx context.Context, provider string, subject string) (int64, error)
+}
+
+type identityService struct {
+	repo     repository.IdentityRepository
+	userRepo repository.UserRepository
+}
+
+func NewIdentityService(repo repository.IdentityRepository, userRepo repository.UserRepository) IdentityService {
+	return &identityService{repo: repo, userRepo: userRepo}
+}
+
+func (s *identityService) List(ctx context.Context, userId int64) ([]domain.Identity, error) {
+	return s.repo.FindByUser(ctx, userId)
+}
+
+func (s *identityService) Owner(ctx context.Context, provider string, subject string) (int64, error) {
+	i, err := s.repo.FindBySubject(ctx, provider, subject)
+	if err == repository.ErrIdentityNotFound {
+		return 0, ErrIdentityNotFound
+	}
+	return i.UserId, err
+}
+
+func (s *identityService) BindPhone(ctx context.Context, userId int64, phone string) error {
+	return s.bind(ctx, userId, domain.IdentityPhone, phone)
+}
+
+func (s *identityService) PrepareWeChatBind(ctx context.Context, state string, userId int64) error {
+	return s.repo.SaveWeChatBind(ctx, state, userId, weChatBindTTL)
+}
+
+func (s *identityService) TakeWeChatBind(ctx context.Context, state string) (int64, error) {
+	userId, err := s.repo.TakeWeChatBind(ctx, state)
+	if err == repository.ErrBindStateNotFound {
+		return 0, ErrBindStateInvalid
+	}
+	return userId, err
+}
+
+func (s *identityService) BindWeChat(ctx context.Context, userId int64, info domain.WeChatResult) error {
+	return s.bind(ctx, userId, domain.IdentityWeChat, info.OpenId)
+}
+
+func (s *identityService) bind(ctx context.Context, userId int64, provider string, subject string) error {
+	i, err := s.repo.FindBySubject(ctx, provider, subject)
+	if err == nil {
+		// 重复绑定到自己的账号，直接成功
+		if i.UserId == userId {
+			return nil
+		}
+		return ErrIdentityTaken
+	}
+	if err != repository.ErrIdentityNotFound {
+		return err
+	}
+	// users表上每种登录方式只有一列，换绑要先解绑旧的
+	identities, err := s.repo.FindByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, i := range identities {
+		if i.Provider == provider {
+			return ErrIdentityExists
+		}
+	}
+	err = s.repo.Bind(ctx, domain.Identity{
+		UserId:     userId,
+		Provider:   provider,
+		Subject:    subject,
+		VerifiedAt: time.Now().UnixMilli(),
+	})
+	// 并发绑定，被别人抢先了
+	if err == repository.ErrIdentityDuplicate {
+		return ErrIdentityTaken
+	}
+	return err
+}
+
+func (s *identityService) Unbind(ctx context.Context, userId int64, id int64) error {
+	identities, err := s.repo.FindByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	found := false
+	var rest []domain.Identity
+	for _, i := range identities {
+		if i.Id == id {
+			found = true
+			continue
+		}
+		rest = append(rest, i)
+	}
+	if !found {
+		return ErrIdentityNotFound
+	}
+	canLogin, err := s.canLogin(ctx, userId, rest)
+	if err != nil {
+		return err
+	}
+	if !canLogin {
+		return ErrLastIdentity
+	}
+	err = s.repo.Unbind(ctx, userId, id)
+	if err == repository.ErrIdentityNotFound {
+		return ErrIdentityNotFound
+	}
+	return err
+}
+
+// 剩下的登录方式里，至少有一种还能用来登录
+func (s *identityService) canLogin(ctx context.Context, userId int64, identities []domain.Identity) (bool, error) {
+	for _, i := range identities {
+		switch i.Provider {
+		case domain.IdentityPhone, domain.IdentityWeChat:
+			return true, nil
+		case domain.IdentityEmail:
+			// 邮箱要配合密码才能登录
+			hash, err := s.userRepo.FindPasswordHash(ctx, userId)
+			if err != nil {
+				return false, err
+			}
+			if hash != "" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdentityService_Unbind(t *testing.T) {
+	email := domain.Identity{Id: 1, UserId: 9, Provider: domain.IdentityEmail, Subject: "a@qq.com"}
+	phone := domain.Identity{Id: 2, UserId: 9, Provider: domain.IdentityPhone, Subject: "13800000000"}
+	wechat := domain.Identity{Id: 3, UserId: 9, Provider: domain.IdentityWeChat, Subject: "openid"}
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository)
+		id   int64
+
+		wantError error
+	}{
+		{
+			name: "还有微信可以登录",
+			mock: func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository) {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{phone, wechat}, nil)
+				repo.EXPECT().Unbind(gomock.Any(), int64(9), int64(2)).Return(nil)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			id: 2,
+		},
+		{
+			name: "还有邮箱和密码可以登录",
+			mock: func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository) {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{email, phone}, nil)
+				repo.EXPECT().Unbind(gomock.Any(), int64(9), int64(2)).Return(nil)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindPasswordHash(gomock.Any(), int64(9)).Return("hash", nil)
+				return repo, userRepo
+			},
+			id: 2,
+		},
+		{
+			name: "邮箱没有设置密码，不能解绑手机号",
+			mock: func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository) {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{email, phone}, nil)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindPasswordHash(gomock.Any(), int64(9)).Return("", nil)
+				return repo, userRepo
+			},
+			id:        2,
+			wantError: ErrLastIdentity,
+		},
+		{
+			name: "最后一种登录方式",
+			mock: func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository) {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{wechat}, nil)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			id:        3,
+			wantError: ErrLastIdentity,
+		},
+		{
+			name: "不是自己的登录方式",
+			mock: func(ctrl *gomock.Controller) (repository.IdentityRepository, repository.UserRepository) {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{phone, wechat}, nil)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			id:        100,
+			wantError: ErrIdentityNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewIdentityService(tc.mock(ctrl))
+			err := svc.Unbind(context.Background(), 9, tc.id)
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
+
+func TestIdentityService_BindPhone(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.IdentityRepository
+
+		wantError error
+	}{
+		{
+			name: "绑定成功",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindBySubject(gomock.Any(), domain.IdentityPhone, "13800000000").
+					Return(domain.Identity{}, repository.ErrIdentityNotFound)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(nil, nil)
+				repo.EXPECT().Bind(gomock.Any(), gomock.Any()).Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "已经是自己的手机号",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindBySubject(gomock.Any(), domain.IdentityPhone, "13800000000").
+					Return(domain.Identity{UserId: 9}, nil)
+				return repo
+			},
+		},
+		{
+			name: "手机号属于别的账号",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindBySubject(gomock.Any(), domain.IdentityPhone, "13800000000").
+					Return(domain.Identity{UserId: 10}, nil)
+				return repo
+			},
+			wantError: ErrIdentityTaken,
+		},
+		{
+			name: "已经绑定了别的手机号",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindBySubject(gomock.Any(), domain.IdentityPhone, "13800000000").
+					Return(domain.Identity{}, repository.ErrIdentityNotFound)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).
+					Return([]domain.Identity{{Provider: domain.IdentityPhone, Subject: "13900000000"}}, nil)
+				return repo
+			},
+			wantError: ErrIdentityExists,
+		},
+		{
+			name: "并发绑定被抢先",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().FindBySubject(gomock.Any(), domain.IdentityPhone, "13800000000").
+					Return(domain.Identity{}, repository.ErrIdentityNotFound)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(nil, nil)
+				repo.EXPECT().Bind(gomock.Any(), gomock.Any()).Return(repository.ErrIdentityDuplicate)
+				return repo
+			},
+			wantError: ErrIdentityTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewIdentityService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl))
+			err := svc.BindPhone(context.Background(), 9, "13800000000")
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
+
+func TestIdentityService_TakeWeChatBind(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.IdentityRepository
+
+		wantUserId int64
+		wantError  error
+	}{
+		{
+			name: "取出发起绑定的用户",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().TakeWeChatBind(gomock.Any(), "state").Return(int64(9), nil)
+				return repo
+			},
+			wantUserId: 9,
+		},
+		{
+			name: "没有发起过绑定或者已经用过了",
+			mock: func(ctrl *gomock.Controller) repository.IdentityRepository {
+				repo := repomocks.NewMockIdentityRepository(ctrl)
+				repo.EXPECT().TakeWeChatBind(gomock.Any(), "state").Return(int64(0), repository.ErrBindStateNotFound)
+				return repo
+			},
+			wantError: ErrBindStateInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewIdentityService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl))
+			userId, err := svc.TakeWeChatBind(context.Background(), "state")
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantUserId, userId)
+		})
+	}
+}
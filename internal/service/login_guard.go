@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/captcha"
+)
+
+var (
+	ErrLoginLocked     = errors.New("登录失败次数过多，账号暂时锁定")
+	ErrIpBlocked       = errors.New("这个ip登录失败次数过多")
+	ErrCaptchaRequired = errors.New("需要人机验证")
+)
+
+// 防暴力破解的策略，次数都是统计窗口内的失败次数
+type LoginGuardPolicy struct {
+	// 失败这么多次之后，每次失败都要等一会才能再试，每多失败一次等待时间翻倍
+	DelayAfter int64
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// 失败这么多次之后锁定账号
+	LockAfter    int64
+	LockDuration time.Duration
+	// 账号或者ip失败这么多次之后需要人机验证
+	CaptchaAfter   int64
+	IpCaptchaAfter int64
+	// ip失败这么多次之后直接拒绝，防止用一个ip猜很多账号
+	IpBlockAfter int64
+}
+
+// 密码登录的防暴力破解
+type LoginGuardService interface {
+	// 登录前检查并占用一次尝试，占用的尝试先算作失败，并发的请求看得到
+	// 被锁定时返回的LockedUntil是锁定到什么时候
+	Check(ctx context.Context, account string, ip string, captchaTicket string) (domain.LoginAttempt, error)
+	// 密码正确或者没验证密码，把Check占用的尝试还回去
+	Release(ctx context.Context, attempt domain.LoginAttempt) error
+	// 记录一次没经过Check的失败，比如两步验证的验证码错误
+	Failed(ctx context.Context, account string, ip string) error
+	// 登录成功，清掉账号的失败次数，ip的不清
+	Succeeded(ctx context.Context, account string) error
+	// 管理员查看、解除锁定
+	State(ctx context.Context, account string) (domain.LoginLockState, error)
+	Unlock(ctx context.Context, account string) error
+}
+
+type loginGuardService struct {
+	repo    repository.LoginAttemptRepository
+	captcha captcha.Service
+	policy  LoginGuardPolicy
+	// 第i+1次失败的锁定时间，占用尝试时按这个表加锁
+	lockSchedule []time.Duration
+}
+
+func NewLoginGuardService(repo repository.LoginAttemptRepository, captcha captcha.Service, policy LoginGuardPolicy) LoginGuardService {
+	s := &loginGuardService{repo: repo, captcha: captcha, policy: policy}
+	n := max(policy.LockAfter, 1)
+	s.lockSchedule = make([]time.Duration, n)
+	for i := int64(1); i <= n; i++ {
+		s.lockSchedule[i-1] = s.lockDuration(i)
+	}
+	return s
+}
+
+func (s *loginGuardService) Check(ctx context.Context, account string, ip string, captchaTicket string) (domain.LoginAttempt, error) {
+	account = normalizeAccount(account)
+	attempt, err := s.repo.Reserve(ctx, account, ip, s.policy.IpBlockAfter, s.lockSchedule)
+	switch {
+	case errors.Is(err, repository.ErrAttemptLocked):
+		return attempt, ErrLoginLocked
+	case errors.Is(err, repository.ErrAttemptIpBlocked):
+		return attempt, ErrIpBlocked
+	case err != nil:
+		return domain.LoginAttempt{}, err
+	}
+	if attempt.AccountFailures < s.policy.CaptchaAfter && attempt.IpFailures < s.policy.IpCaptchaAfter {
+		return attempt, nil
+	}
+	// 没过人机验证不算一次尝试
+	ok := false
+	if captchaTicket != "" {
+		ok, err = s.captcha.Verify(ctx, captchaTicket, ip)
+	}
+	if err == nil && !ok {
+		err = ErrCaptchaRequired
+	}
+	if err != nil {
+		if er := s.Release(ctx, attempt); er != nil {
+			return domain.LoginAttempt{}, er
+		}
+		return domain.LoginAttempt{}, err
+	}
+	return attempt, nil
+}
+
+func (s *loginGuardService) Release(ctx context.Context, attempt domain.LoginAttempt) error {
+	return s.repo.Release(ctx, attempt)
+}
+
+func (s *loginGuardService) Failed(ctx context.Context, account string, ip string) error {
+	account = normalizeAccount(account)
+	failures, _, err := s.repo.IncrFailure(ctx, account, ip)
+	if err != nil {
+		return err
+	}
+	if d := s.lockDuration(failures); d > 0 {
+		return s.repo.Lock(ctx, account, d)
+	}
+	return nil
+}
+
+// 失败次数对应的锁定时间，前几次不锁
+func (s *loginGuardService) lockDuration(failures int64) time.Duration {
+	if failures >= s.policy.LockAfter {
+		return s.policy.LockDuration
+	}
+	if failures < s.policy.DelayAfter {
+		return 0
+	}
+	d := s.policy.BaseDelay
+	for i := s.policy.DelayAfter; i < failures && d < s.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > s.policy.MaxDelay {
+		d = s.policy.MaxDelay
+	}
+	return d
+}
+
+func (s *loginGuardService) Succeeded(ctx context.Context, account string) error {
+	return s.repo.Reset(ctx, normalizeAccount(account))
+}
+
+func (s *loginGuardService) State(ctx context.Context, account string) (domain.LoginLockState, error) {
+	account = normalizeAccount(account)
+	failures, _, err := s.repo.Failures(ctx, account, "")
+	if err != nil {
+		return domain.LoginLockState{}, err
+	}
+	until, err := s.repo.LockedUntil(ctx, account)
+	if err != nil {
+		return domain.LoginLockState{}, err
+	}
+	if until <= time.Now().UnixMilli() {
+		until = 0
+	}
+	return domain.LoginLockState{Account: account, Failures: failures, LockedUntil: until}, nil
+}
+
+// 只清账号的，ip的计数不动
+func (s *loginGuardService) Unlock(ctx context.Context, account string) error {
+	return s.repo.Reset(ctx, normalizeAccount(account))
+}
+
+// 邮箱大小写不同也算同一个账号，不能靠换大小写绕过
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+	"webook/internal/service/captcha"
+	"webook/internal/service/captcha/local"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var testLoginGuardPolicy = LoginGuardPolicy{
+	DelayAfter:     3,
+	BaseDelay:      time.Second,
+	MaxDelay:       time.Second * 10,
+	LockAfter:      10,
+	LockDuration:   time.Minute * 15,
+	CaptchaAfter:   3,
+	IpCaptchaAfter: 10,
+	IpBlockAfter:   100,
+}
+
+func TestLoginGuardService_Failed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		failures int64
+		// 0表示不锁
+		wantLock time.Duration
+	}{
+		{name: "前几次不锁", failures: 2},
+		{name: "开始延迟", failures: 3, wantLock: time.Second},
+		{name: "延迟翻倍", failures: 5, wantLock: time.Second * 4},
+		{name: "延迟有上限", failures: 9, wantLock: time.Second * 10},
+		{name: "锁定账号", failures: 10, wantLock: time.Minute * 15},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+			// 邮箱统一成小写
+			repo.EXPECT().IncrFailure(gomock.Any(), "a@qq.com", "127.0.0.1").Return(tc.failures, int64(1), nil)
+			if tc.wantLock > 0 {
+				repo.EXPECT().Lock(gomock.Any(), "a@qq.com", tc.wantLock).Return(nil)
+			}
+			svc := NewLoginGuardService(repo, local.NewService(), testLoginGuardPolicy)
+			err := svc.Failed(context.Background(), " A@qq.com", "127.0.0.1")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoginGuardService_Check(t *testing.T) {
+	// 按testLoginGuardPolicy算出来的第1..10次失败的锁定时间
+	schedule := []time.Duration{0, 0, time.Second, time.Second * 2, time.Second * 4,
+		time.Second * 8, time.Second * 10, time.Second * 10, time.Second * 10, time.Minute * 15}
+	lockedUntil := time.Now().Add(time.Minute).UnixMilli()
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.LoginAttemptRepository
+		captcha string
+
+		wantAttempt domain.LoginAttempt
+		wantError   error
+	}{
+		{
+			name: "正常登录",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Reserve(gomock.Any(), "a@qq.com", "127.0.0.1", int64(100), schedule).
+					Return(domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", AccountFailures: 1, IpFailures: 1}, nil)
+				return repo
+			},
+			wantAttempt: domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", AccountFailures: 1, IpFailures: 1},
+		},
+		{
+			name: "账号被锁定",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Reserve(gomock.Any(), "a@qq.com", "127.0.0.1", int64(100), schedule).
+					Return(domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", LockedUntil: lockedUntil}, repository.ErrAttemptLocked)
+				return repo
+			},
+			wantAttempt: domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", LockedUntil: lockedUntil},
+			wantError:   ErrLoginLocked,
+		},
+		{
+			name: "账号失败多次需要人机验证，归还占用的尝试",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				attempt := domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", AccountFailures: 3, IpFailures: 3, LockedUntil: lockedUntil}
+				repo.EXPECT().Reserve(gomock.Any(), "a@qq.com", "127.0.0.1", int64(100), schedule).Return(attempt, nil)
+				repo.EXPECT().Release(gomock.Any(), attempt).Return(nil)
+				return repo
+			},
+			wantError: ErrCaptchaRequired,
+		},
+		{
+			name: "ip失败多次需要人机验证，带了票据",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Reserve(gomock.Any(), "a@qq.com", "127.0.0.1", int64(100), schedule).
+					Return(domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", IpFailures: 10}, nil)
+				return repo
+			},
+			captcha:     "ticket",
+			wantAttempt: domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", IpFailures: 10},
+		},
+		{
+			name: "ip失败太多次",
+			mock: func(ctrl *gomock.Controller) repository.LoginAttemptRepository {
+				repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+				repo.EXPECT().Reserve(gomock.Any(), "a@qq.com", "127.0.0.1", int64(100), schedule).
+					Return(domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", IpFailures: 100}, repository.ErrAttemptIpBlocked)
+				return repo
+			},
+			captcha:     "ticket",
+			wantAttempt: domain.LoginAttempt{Account: "a@qq.com", Ip: "127.0.0.1", IpFailures: 100},
+			wantError:   ErrIpBlocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			var captchaSvc captcha.Service = local.NewService()
+			svc := NewLoginGuardService(tc.mock(ctrl), captchaSvc, testLoginGuardPolicy)
+			attempt, err := svc.Check(context.Background(), " A@qq.com", "127.0.0.1", tc.captcha)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantAttempt, attempt)
+		})
+	}
+}
+
+// 登录成功只清账号的计数，ip的计数留着，防止用自己的账号给ip洗白
+func TestLoginGuardService_Succeeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockLoginAttemptRepository(ctrl)
+	repo.EXPECT().Reset(gomock.Any(), "a@qq.com").Return(nil)
+	svc := NewLoginGuardService(repo, local.NewService(), testLoginGuardPolicy)
+	assert.NoError(t, svc.Succeeded(context.Background(), "A@qq.com"))
+}
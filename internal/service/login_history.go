@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+const (
+	loginHistoryPageSize    = 20
+	loginHistoryMaxPageSize = 100
+	// 每批清理的记录数，避免一次删太多锁表
+	loginHistoryPruneBatch = 1000
+)
+
+// 登录历史，记录每一次登录尝试
+type LoginHistoryService interface {
+	// 调用方不应该因为记录失败而让登录失败
+	Record(ctx context.Context, r domain.LoginRecord) error
+	// 用户自己最近的登录活动，cursor为0表示第一页，返回下一页的cursor，0表示没有下一页
+	Recent(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.LoginRecord, int64, error)
+	// 管理后台查询，返回这一页和总数
+	Search(ctx context.Context, filter domain.LoginRecordFilter, page int, pageSize int) ([]domain.LoginRecord, int64, error)
+	// 删除超过保留期的记录，返回删除的条数
+	PruneExpired(ctx context.Context) (int64, error)
+	// 定时清理，阻塞直到ctx结束
+	RunPrune(ctx context.Context, interval time.Duration)
+	// 注销清理时删掉登录记录
+	UserPurgedListener
+	// 合并账号时把登录记录挪过去
+	UserMergedListener
+}
+
+type loginHistoryService struct {
+	repo  repository.LoginRecordRepository
+	users repository.UserRepository
+	// 记录保留多久，0表示一直保留
+	retention time.Duration
+}
+
+func NewLoginHistoryService(repo repository.LoginRecordRepository, users repository.UserRepository, retention time.Duration) LoginHistoryService {
+	return &loginHistoryService{repo: repo, users: users, retention: retention}
+}
+
+func (s *loginHistoryService) Record(ctx context.Context, r domain.LoginRecord) error {
+	if r.UserId == 0 {
+		r.UserId = s.resolveUserId(ctx, r)
+	}
+	return s.repo.Create(ctx, r)
+}
+
+// 密码错误、验证码错误这些失败还没拿到用户，按账号查一下
+// 这样用户在自己的登录活动里才能看到别人试他的密码
+// 账号不存在或者查询失败就记0，不影响记录本身
+func (s *loginHistoryService) resolveUserId(ctx context.Context, r domain.LoginRecord) int64 {
+	if r.Account == "" {
+		return 0
+	}
+	var (
+		user domain.User
+		err  error
+	)
+	switch r.Method {
+	case domain.LoginMethodPassword:
+		user, err = s.users.FindByEmail(ctx, r.Account)
+	case domain.LoginMethodSms:
+		user, err = s.users.FindByPhone(ctx, r.Account)
+	default:
+		return 0
+	}
+	if err != nil {
+		if err != repository.ErrUserNotFound {
+			log.Println("按账号查询登录用户失败", err)
+		}
+		return 0
+	}
+	return user.Id
+}
+
+func (s *loginHistoryService) Recent(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.LoginRecord, int64, error) {
+	if limit <= 0 {
+		limit = loginHistoryPageSize
+	}
+	if limit > loginHistoryMaxPageSize {
+		limit = loginHistoryMaxPageSize
+	}
+	res, err := s.repo.FindByUser(ctx, userId, cursor, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(res) < limit {
+		return res, 0, nil
+	}
+	return res, res[len(res)-1].Id, nil
+}
+
+func (s *loginHistoryService) Search(ctx context.Context, filter domain.LoginRecordFilter, page int, pageSize int) ([]domain.LoginRecord, int64, error) {
+	offset, limit := adminPage(page, pageSize)
+	return s.repo.Search(ctx, filter, offset, limit)
+}
+
+func (s *loginHistoryService) PruneExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	before := time.Now().Add(-s.retention).UnixMilli()
+	var count int64
+	for {
+		n, err := s.repo.DeleteBefore(ctx, before, loginHistoryPruneBatch)
+		count += n
+		if err != nil || n < loginHistoryPruneBatch {
+			return count, err
+		}
+	}
+}
+
+// 多个实例同时跑也没关系，清理是幂等的
+func (s *loginHistoryService) RunPrune(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PruneExpired(ctx)
+			if err != nil {
+				log.Println("清理登录记录失败", err)
+			}
+			if n > 0 {
+				log.Printf("清理了%d条过期的登录记录", n)
+			}
+		}
+	}
+}
+
+func (s *loginHistoryService) OnUserPurged(ctx context.Context, userId int64) error {
+	return s.repo.DeleteByUser(ctx, userId)
+}
+
+func (s *loginHistoryService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	return s.repo.MoveUser(ctx, evt.SourceId, evt.TargetId)
+}
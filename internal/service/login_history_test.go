@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoginHistoryService_Record(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository)
+		record domain.LoginRecord
+
+		wantError error
+	}{
+		{
+			name: "密码错误，按邮箱找到用户",
+			mock: func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				users.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{Id: 1}, nil)
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.LoginRecord{UserId: 1, Method: domain.LoginMethodPassword,
+					Result: domain.LoginResultWrongCredentials, Account: "123@qq.com"}).Return(nil)
+				return repo, users
+			},
+			record: domain.LoginRecord{Method: domain.LoginMethodPassword,
+				Result: domain.LoginResultWrongCredentials, Account: "123@qq.com"},
+		},
+		{
+			name: "验证码错误，按手机号找到用户",
+			mock: func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				users.EXPECT().FindByPhone(gomock.Any(), "13800000000").Return(domain.User{Id: 2}, nil)
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.LoginRecord{UserId: 2, Method: domain.LoginMethodSms,
+					Result: domain.LoginResultWrongCredentials, Account: "13800000000"}).Return(nil)
+				return repo, users
+			},
+			record: domain.LoginRecord{Method: domain.LoginMethodSms,
+				Result: domain.LoginResultWrongCredentials, Account: "13800000000"},
+		},
+		{
+			name: "账号不存在，记0",
+			mock: func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				users.EXPECT().FindByEmail(gomock.Any(), "404@qq.com").Return(domain.User{}, repository.ErrUserNotFound)
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.LoginRecord{Method: domain.LoginMethodPassword,
+					Result: domain.LoginResultWrongCredentials, Account: "404@qq.com"}).Return(nil)
+				return repo, users
+			},
+			record: domain.LoginRecord{Method: domain.LoginMethodPassword,
+				Result: domain.LoginResultWrongCredentials, Account: "404@qq.com"},
+		},
+		{
+			name: "已经知道用户，不用查",
+			mock: func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository) {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.LoginRecord{UserId: 3, Method: domain.LoginMethodPassword,
+					Result: domain.LoginResultSuccess, Account: "123@qq.com"}).Return(nil)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			record: domain.LoginRecord{UserId: 3, Method: domain.LoginMethodPassword,
+				Result: domain.LoginResultSuccess, Account: "123@qq.com"},
+		},
+		{
+			name: "写入出错",
+			mock: func(ctrl *gomock.Controller) (repository.LoginRecordRepository, repository.UserRepository) {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db错误"))
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			record:    domain.LoginRecord{UserId: 3, Method: domain.LoginMethodRefresh},
+			wantError: errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, users := tc.mock(ctrl)
+			svc := NewLoginHistoryService(repo, users, time.Hour)
+			err := svc.Record(context.Background(), tc.record)
+			assert.Equal(t, tc.wantError, err)
+		})
+	}
+}
+
+func TestLoginHistoryService_Recent(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.LoginRecordRepository
+		cursor int64
+		limit  int
+
+		wantCount  int
+		wantCursor int64
+		wantError  error
+	}{
+		{
+			name: "满一页，返回下一页的cursor",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(1), int64(0), 2).
+					Return([]domain.LoginRecord{{Id: 9}, {Id: 7}}, nil)
+				return repo
+			},
+			limit:      2,
+			wantCount:  2,
+			wantCursor: 7,
+		},
+		{
+			name: "最后一页",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(1), int64(7), 2).
+					Return([]domain.LoginRecord{{Id: 3}}, nil)
+				return repo
+			},
+			cursor:    7,
+			limit:     2,
+			wantCount: 1,
+		},
+		{
+			name: "页大小超过上限",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(1), int64(0), loginHistoryMaxPageSize).Return(nil, nil)
+				return repo
+			},
+			limit: 1000,
+		},
+		{
+			name: "查询出错",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(1), int64(0), loginHistoryPageSize).
+					Return(nil, errors.New("db错误"))
+				return repo
+			},
+			wantError: errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewLoginHistoryService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl), time.Hour)
+			res, cursor, err := svc.Recent(context.Background(), 1, tc.cursor, tc.limit)
+			assert.Equal(t, tc.wantError, err)
+			assert.Len(t, res, tc.wantCount)
+			assert.Equal(t, tc.wantCursor, cursor)
+		})
+	}
+}
+
+func TestLoginHistoryService_PruneExpired(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mock      func(ctrl *gomock.Controller) repository.LoginRecordRepository
+		retention time.Duration
+
+		wantCount int64
+		wantError error
+	}{
+		{
+			name: "分批删到不满一批为止",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				gomock.InOrder(
+					repo.EXPECT().DeleteBefore(gomock.Any(), gomock.Any(), loginHistoryPruneBatch).
+						Return(int64(loginHistoryPruneBatch), nil),
+					repo.EXPECT().DeleteBefore(gomock.Any(), gomock.Any(), loginHistoryPruneBatch).
+						Return(int64(3), nil),
+				)
+				return repo
+			},
+			retention: time.Hour,
+			wantCount: loginHistoryPruneBatch + 3,
+		},
+		{
+			name: "一直保留",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				return repomocks.NewMockLoginRecordRepository(ctrl)
+			},
+		},
+		{
+			name: "删除出错",
+			mock: func(ctrl *gomock.Controller) repository.LoginRecordRepository {
+				repo := repomocks.NewMockLoginRecordRepository(ctrl)
+				repo.EXPECT().DeleteBefore(gomock.Any(), gomock.Any(), loginHistoryPruneBatch).
+					Return(int64(0), errors.New("db错误"))
+				return repo
+			},
+			retention: time.Hour,
+			wantError: errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewLoginHistoryService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl), tc.retention)
+			n, err := svc.PruneExpired(context.Background())
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantCount, n)
+		})
+	}
+}
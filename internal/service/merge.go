@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrMergeSameUser = errors.New("不能合并同一个账号")
+	ErrMergeConflict = errors.New("两个账号绑定了同一类登录方式")
+	ErrUserMerged    = errors.New("账号已经被合并")
+)
+
+// 其他模块实现这个接口，账号合并后把自己的外键改到保留的账号上
+// 要求幂等，失败之后可以重放
+type UserMergedListener interface {
+	OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error
+}
+
+// 合并同一个人重复注册的账号
+type AccountMergeService interface {
+	// 调用方负责证明两个账号都属于当前用户，sourceId的账号合并到targetId上
+	Merge(ctx context.Context, targetId int64, sourceId int64) (domain.UserMergedEvent, error)
+}
+
+type accountMergeService struct {
+	repo        repository.UserRepository
+	securitySvc SecurityEventService
+	listeners   []UserMergedListener
+}
+
+func NewAccountMergeService(repo repository.UserRepository, securitySvc SecurityEventService,
+	listeners []UserMergedListener) AccountMergeService {
+	return &accountMergeService{repo: repo, securitySvc: securitySvc, listeners: listeners}
+}
+
+func (s *accountMergeService) Merge(ctx context.Context, targetId int64, sourceId int64) (domain.UserMergedEvent, error) {
+	if targetId == sourceId {
+		return domain.UserMergedEvent{}, ErrMergeSameUser
+	}
+	// 不管调用方用哪种方式证明，封禁中、注销中的账号都不能合并
+	source, err := s.repo.FindById(ctx, sourceId)
+	if err == repository.ErrUserNotFound {
+		return domain.UserMergedEvent{}, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.UserMergedEvent{}, err
+	}
+	if source.Deleted() {
+		return domain.UserMergedEvent{}, ErrUserDeleted
+	}
+	if source.Banned(time.Now().UnixMilli()) {
+		return domain.UserMergedEvent{}, ErrUserBanned
+	}
+	// 并发封禁由repository在锁里再检查一次
+	_, err = s.repo.Merge(ctx, targetId, sourceId)
+	switch err {
+	case nil:
+	case repository.ErrUserNotFound:
+		return domain.UserMergedEvent{}, ErrUserNotFound
+	case repository.ErrUserBanned:
+		return domain.UserMergedEvent{}, ErrUserBanned
+	case repository.ErrUserMerged:
+		return domain.UserMergedEvent{}, ErrUserMerged
+	case repository.ErrMergeConflict:
+		return domain.UserMergedEvent{}, ErrMergeConflict
+	default:
+		return domain.UserMergedEvent{}, err
+	}
+	evt := domain.UserMergedEvent{SourceId: sourceId, TargetId: targetId, MergedAt: time.Now().UnixMilli()}
+	er := s.securitySvc.Record(ctx, domain.SecurityEvent{
+		UserId: targetId,
+		Type:   domain.SecurityEventAccountMerge,
+		Detail: fmt.Sprintf("合并了账号%d", sourceId),
+	})
+	if er != nil {
+		log.Println("记录安全事件失败", er)
+	}
+	// 用户已经合并完了，监听方失败只记日志，靠日志里的事件重放
+	for _, l := range s.listeners {
+		if er = l.OnUserMerged(ctx, evt); er != nil {
+			log.Printf("处理账号合并事件失败 %+v %v", evt, er)
+		}
+	}
+	return evt, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+	svcmocks "webook/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountMergeService_Merge(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener)
+		sourceId int64
+
+		wantError error
+	}{
+		{
+			name: "合并成功，通知所有监听方",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).Return(domain.User{Id: 1}, nil)
+				security := svcmocks.NewMockSecurityEventService(ctrl)
+				security.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				l1 := svcmocks.NewMockUserMergedListener(ctrl)
+				l1.EXPECT().OnUserMerged(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, evt domain.UserMergedEvent) error {
+						assert.Equal(t, int64(2), evt.SourceId)
+						assert.Equal(t, int64(1), evt.TargetId)
+						return nil
+					})
+				// 一个监听方失败不影响其他的
+				l2 := svcmocks.NewMockUserMergedListener(ctrl)
+				l2.EXPECT().OnUserMerged(gomock.Any(), gomock.Any()).Return(errors.New("mock db error"))
+				l3 := svcmocks.NewMockUserMergedListener(ctrl)
+				l3.EXPECT().OnUserMerged(gomock.Any(), gomock.Any()).Return(nil)
+				return repo, security, []UserMergedListener{l1, l2, l3}
+			},
+			sourceId: 2,
+		},
+		{
+			name: "同一个账号",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				return repomocks.NewMockUserRepository(ctrl), svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  1,
+			wantError: ErrMergeSameUser,
+		},
+		{
+			name: "登录方式冲突",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).Return(domain.User{}, repository.ErrMergeConflict)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), []UserMergedListener{svcmocks.NewMockUserMergedListener(ctrl)}
+			},
+			sourceId:  2,
+			wantError: ErrMergeConflict,
+		},
+		{
+			name: "已经被合并过",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).Return(domain.User{}, repository.ErrUserMerged)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  2,
+			wantError: ErrUserMerged,
+		},
+
+		{
+			name: "被合并的账号封禁中",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2,
+					UserStatus: domain.UserStatus{Status: domain.UserStatusBanned}}, nil)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  2,
+			wantError: ErrUserBanned,
+		},
+		{
+			name: "被合并的账号暂停使用中",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2,
+					UserStatus: domain.UserStatus{Status: domain.UserStatusSuspended,
+						SuspendedUntil: time.Now().Add(time.Hour).UnixMilli()}}, nil)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  2,
+			wantError: ErrUserBanned,
+		},
+		{
+			name: "检查之后被封禁，锁里再拦一次",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).Return(domain.User{}, repository.ErrUserBanned)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  2,
+			wantError: ErrUserBanned,
+		},
+		{
+			name: "被合并的账号注销中",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, SecurityEventService, []UserMergedListener) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2, DeletedAt: 1}, nil)
+				return repo, svcmocks.NewMockSecurityEventService(ctrl), nil
+			},
+			sourceId:  2,
+			wantError: ErrUserDeleted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccountMergeService(tc.mock(ctrl))
+			evt, err := svc.Merge(context.Background(), 1, tc.sourceId)
+			assert.Equal(t, tc.wantError, err)
+			if err == nil {
+				assert.Equal(t, tc.sourceId, evt.SourceId)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/account_status.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/account_status.go -package=svcmocks -destination=./internal/service/mocks/account_status.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountStatusService is a mock of AccountStatusService interface.
+type MockAccountStatusService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountStatusServiceMockRecorder
+}
+
+// MockAccountStatusServiceMockRecorder is the mock recorder for MockAccountStatusService.
+type MockAccountStatusServiceMockRecorder struct {
+	mock *MockAccountStatusService
+}
+
+// NewMockAccountStatusService creates a new mock instance.
+func NewMockAccountStatusService(ctrl *gomock.Controller) *MockAccountStatusService {
+	mock := &MockAccountStatusService{ctrl: ctrl}
+	mock.recorder = &MockAccountStatusServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountStatusService) EXPECT() *MockAccountStatusServiceMockRecorder {
+	return m.recorder
+}
+
+// Unusable mocks base method.
+func (m *MockAccountStatusService) Unusable(ctx context.Context, userId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unusable", ctx, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unusable indicates an expected call of Unusable.
+func (mr *MockAccountStatusServiceMockRecorder) Unusable(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unusable", reflect.TypeOf((*MockAccountStatusService)(nil).Unusable), ctx, userId)
+}
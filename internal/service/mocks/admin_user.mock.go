@@ -0,0 +1,194 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/admin_user.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/admin_user.go -package=svcmocks -destination=./internal/service/mocks/admin_user.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSessionRevoker is a mock of SessionRevoker interface.
+type MockSessionRevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRevokerMockRecorder
+}
+
+// MockSessionRevokerMockRecorder is the mock recorder for MockSessionRevoker.
+type MockSessionRevokerMockRecorder struct {
+	mock *MockSessionRevoker
+}
+
+// NewMockSessionRevoker creates a new mock instance.
+func NewMockSessionRevoker(ctrl *gomock.Controller) *MockSessionRevoker {
+	mock := &MockSessionRevoker{ctrl: ctrl}
+	mock.recorder = &MockSessionRevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRevoker) EXPECT() *MockSessionRevokerMockRecorder {
+	return m.recorder
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockSessionRevoker) RevokeAllSessions(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockSessionRevokerMockRecorder) RevokeAllSessions(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockSessionRevoker)(nil).RevokeAllSessions), ctx, userId)
+}
+
+// MockAdminUserService is a mock of AdminUserService interface.
+type MockAdminUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminUserServiceMockRecorder
+}
+
+// MockAdminUserServiceMockRecorder is the mock recorder for MockAdminUserService.
+type MockAdminUserServiceMockRecorder struct {
+	mock *MockAdminUserService
+}
+
+// NewMockAdminUserService creates a new mock instance.
+func NewMockAdminUserService(ctrl *gomock.Controller) *MockAdminUserService {
+	mock := &MockAdminUserService{ctrl: ctrl}
+	mock.recorder = &MockAdminUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminUserService) EXPECT() *MockAdminUserServiceMockRecorder {
+	return m.recorder
+}
+
+// AuditLogs mocks base method.
+func (m *MockAdminUserService) AuditLogs(ctx context.Context, targetId int64, page, pageSize int) ([]domain.AuditLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLogs", ctx, targetId, page, pageSize)
+	ret0, _ := ret[0].([]domain.AuditLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AuditLogs indicates an expected call of AuditLogs.
+func (mr *MockAdminUserServiceMockRecorder) AuditLogs(ctx, targetId, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLogs", reflect.TypeOf((*MockAdminUserService)(nil).AuditLogs), ctx, targetId, page, pageSize)
+}
+
+// Ban mocks base method.
+func (m *MockAdminUserService) Ban(ctx context.Context, op domain.AuditOperator, userId, until int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ban", ctx, op, userId, until, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ban indicates an expected call of Ban.
+func (mr *MockAdminUserServiceMockRecorder) Ban(ctx, op, userId, until, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ban", reflect.TypeOf((*MockAdminUserService)(nil).Ban), ctx, op, userId, until, reason)
+}
+
+// FindUser mocks base method.
+func (m *MockAdminUserService) FindUser(ctx context.Context, op domain.AuditOperator, userId int64) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUser", ctx, op, userId)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUser indicates an expected call of FindUser.
+func (mr *MockAdminUserServiceMockRecorder) FindUser(ctx, op, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUser", reflect.TypeOf((*MockAdminUserService)(nil).FindUser), ctx, op, userId)
+}
+
+// ForceLogout mocks base method.
+func (m *MockAdminUserService) ForceLogout(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceLogout", ctx, op, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceLogout indicates an expected call of ForceLogout.
+func (mr *MockAdminUserServiceMockRecorder) ForceLogout(ctx, op, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceLogout", reflect.TypeOf((*MockAdminUserService)(nil).ForceLogout), ctx, op, userId)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAdminUserService) ResetPassword(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, op, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAdminUserServiceMockRecorder) ResetPassword(ctx, op, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAdminUserService)(nil).ResetPassword), ctx, op, userId)
+}
+
+// ResetTwoFactor mocks base method.
+func (m *MockAdminUserService) ResetTwoFactor(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetTwoFactor", ctx, op, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetTwoFactor indicates an expected call of ResetTwoFactor.
+func (mr *MockAdminUserServiceMockRecorder) ResetTwoFactor(ctx, op, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetTwoFactor", reflect.TypeOf((*MockAdminUserService)(nil).ResetTwoFactor), ctx, op, userId)
+}
+
+// Search mocks base method.
+func (m *MockAdminUserService) Search(ctx context.Context, op domain.AuditOperator, filter domain.UserFilter, page, pageSize int) ([]domain.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, op, filter, page, pageSize)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockAdminUserServiceMockRecorder) Search(ctx, op, filter, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAdminUserService)(nil).Search), ctx, op, filter, page, pageSize)
+}
+
+// Unban mocks base method.
+func (m *MockAdminUserService) Unban(ctx context.Context, op domain.AuditOperator, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unban", ctx, op, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unban indicates an expected call of Unban.
+func (mr *MockAdminUserServiceMockRecorder) Unban(ctx, op, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unban", reflect.TypeOf((*MockAdminUserService)(nil).Unban), ctx, op, userId)
+}
@@ -1,3 +1,11 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/code.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/code.go -package=svcmocks -destination=./internal/service/mocks/code.mock.go
+//
+// Package svcmocks is a generated GoMock package.
 package svcmocks
 
 import (
@@ -9,52 +17,104 @@ import (
 
 // MockCodeService is a mock of CodeService interface.
 type MockCodeService struct {
-        ctrl     *gomock.Controller
-        recorder *MockCodeServiceMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockCodeServiceMockRecorder
 }
 
 // MockCodeServiceMockRecorder is the mock recorder for MockCodeService.
 type MockCodeServiceMockRecorder struct {
-        mock *MockCodeService
+	mock *MockCodeService
 }
 
 // NewMockCodeService creates a new mock instance.
 func NewMockCodeService(ctrl *gomock.Controller) *MockCodeService {
-        mock := &MockCodeService{ctrl: ctrl}
-        mock.recorder = &MockCodeServiceMockRecorder{mock}
-        return mock
+	mock := &MockCodeService{ctrl: ctrl}
+	mock.recorder = &MockCodeServiceMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockCodeService) EXPECT() *MockCodeServiceMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // Send mocks base method.
 func (m *MockCodeService) Send(ctx context.Context, biz, phone string) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Send", ctx, biz, phone)
-        ret0, _ := ret[0].(error)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, biz, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Send indicates an expected call of Send.
 func (mr *MockCodeServiceMockRecorder) Send(ctx, biz, phone any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCodeService)(nil).Send), ctx, biz, phone)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCodeService)(nil).Send), ctx, biz, phone)
 }
 
 // Verify mocks base method.
 func (m *MockCodeService) Verify(ctx context.Context, biz, phone, code string) (bool, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Verify", ctx, biz, phone, code)
-        ret0, _ := ret[0].(bool)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, biz, phone, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Verify indicates an expected call of Verify.
 func (mr *MockCodeServiceMockRecorder) Verify(ctx, biz, phone, code any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockCodeService)(nil).Verify), ctx, biz, phone, code)
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockCodeService)(nil).Verify), ctx, biz, phone, code)
+}
+
+// MockEmailCodeService is a mock of EmailCodeService interface.
+type MockEmailCodeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailCodeServiceMockRecorder
+}
+
+// MockEmailCodeServiceMockRecorder is the mock recorder for MockEmailCodeService.
+type MockEmailCodeServiceMockRecorder struct {
+	mock *MockEmailCodeService
+}
+
+// NewMockEmailCodeService creates a new mock instance.
+func NewMockEmailCodeService(ctrl *gomock.Controller) *MockEmailCodeService {
+	mock := &MockEmailCodeService{ctrl: ctrl}
+	mock.recorder = &MockEmailCodeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailCodeService) EXPECT() *MockEmailCodeServiceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockEmailCodeService) Send(ctx context.Context, biz, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, biz, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockEmailCodeServiceMockRecorder) Send(ctx, biz, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockEmailCodeService)(nil).Send), ctx, biz, email)
+}
+
+// Verify mocks base method.
+func (m *MockEmailCodeService) Verify(ctx context.Context, biz, email, code string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, biz, email, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockEmailCodeServiceMockRecorder) Verify(ctx, biz, email, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockEmailCodeService)(nil).Verify), ctx, biz, email, code)
+}
@@ -0,0 +1,119 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/deletion.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/deletion.go -package=svcmocks -destination=./internal/service/mocks/deletion.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserPurgedListener is a mock of UserPurgedListener interface.
+type MockUserPurgedListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPurgedListenerMockRecorder
+}
+
+// MockUserPurgedListenerMockRecorder is the mock recorder for MockUserPurgedListener.
+type MockUserPurgedListenerMockRecorder struct {
+	mock *MockUserPurgedListener
+}
+
+// NewMockUserPurgedListener creates a new mock instance.
+func NewMockUserPurgedListener(ctrl *gomock.Controller) *MockUserPurgedListener {
+	mock := &MockUserPurgedListener{ctrl: ctrl}
+	mock.recorder = &MockUserPurgedListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPurgedListener) EXPECT() *MockUserPurgedListenerMockRecorder {
+	return m.recorder
+}
+
+// OnUserPurged mocks base method.
+func (m *MockUserPurgedListener) OnUserPurged(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserPurged", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserPurged indicates an expected call of OnUserPurged.
+func (mr *MockUserPurgedListenerMockRecorder) OnUserPurged(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserPurged", reflect.TypeOf((*MockUserPurgedListener)(nil).OnUserPurged), ctx, userId)
+}
+
+// MockAccountDeletionService is a mock of AccountDeletionService interface.
+type MockAccountDeletionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionServiceMockRecorder
+}
+
+// MockAccountDeletionServiceMockRecorder is the mock recorder for MockAccountDeletionService.
+type MockAccountDeletionServiceMockRecorder struct {
+	mock *MockAccountDeletionService
+}
+
+// NewMockAccountDeletionService creates a new mock instance.
+func NewMockAccountDeletionService(ctrl *gomock.Controller) *MockAccountDeletionService {
+	mock := &MockAccountDeletionService{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionService) EXPECT() *MockAccountDeletionServiceMockRecorder {
+	return m.recorder
+}
+
+// PurgeExpired mocks base method.
+func (m *MockAccountDeletionService) PurgeExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockAccountDeletionServiceMockRecorder) PurgeExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockAccountDeletionService)(nil).PurgeExpired), ctx)
+}
+
+// RequestDeletion mocks base method.
+func (m *MockAccountDeletionService) RequestDeletion(ctx context.Context, userId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestDeletion", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestDeletion indicates an expected call of RequestDeletion.
+func (mr *MockAccountDeletionServiceMockRecorder) RequestDeletion(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestDeletion", reflect.TypeOf((*MockAccountDeletionService)(nil).RequestDeletion), ctx, userId)
+}
+
+// RunPurge mocks base method.
+func (m *MockAccountDeletionService) RunPurge(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunPurge", ctx, interval)
+}
+
+// RunPurge indicates an expected call of RunPurge.
+func (mr *MockAccountDeletionServiceMockRecorder) RunPurge(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPurge", reflect.TypeOf((*MockAccountDeletionService)(nil).RunPurge), ctx, interval)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/export.go -package=svcmocks -destination=./internal/service/mocks/export.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportService is a mock of DataExportService interface.
+type MockDataExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportServiceMockRecorder
+}
+
+// MockDataExportServiceMockRecorder is the mock recorder for MockDataExportService.
+type MockDataExportServiceMockRecorder struct {
+	mock *MockDataExportService
+}
+
+// NewMockDataExportService creates a new mock instance.
+func NewMockDataExportService(ctrl *gomock.Controller) *MockDataExportService {
+	mock := &MockDataExportService{ctrl: ctrl}
+	mock.recorder = &MockDataExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportService) EXPECT() *MockDataExportServiceMockRecorder {
+	return m.recorder
+}
+
+// DownloadURL mocks base method.
+func (m *MockDataExportService) DownloadURL(export domain.DataExport) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadURL", export)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadURL indicates an expected call of DownloadURL.
+func (mr *MockDataExportServiceMockRecorder) DownloadURL(export any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURL", reflect.TypeOf((*MockDataExportService)(nil).DownloadURL), export)
+}
+
+// Find mocks base method.
+func (m *MockDataExportService) Find(ctx context.Context, userId, id int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, userId, id)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockDataExportServiceMockRecorder) Find(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDataExportService)(nil).Find), ctx, userId, id)
+}
+
+// OnUserPurged mocks base method.
+func (m *MockDataExportService) OnUserPurged(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserPurged", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserPurged indicates an expected call of OnUserPurged.
+func (mr *MockDataExportServiceMockRecorder) OnUserPurged(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserPurged", reflect.TypeOf((*MockDataExportService)(nil).OnUserPurged), ctx, userId)
+}
+
+// Open mocks base method.
+func (m *MockDataExportService) Open(ctx context.Context, id, expires int64, sig string) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, id, expires, sig)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockDataExportServiceMockRecorder) Open(ctx, id, expires, sig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockDataExportService)(nil).Open), ctx, id, expires, sig)
+}
+
+// Request mocks base method.
+func (m *MockDataExportService) Request(ctx context.Context, userId int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, userId)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockDataExportServiceMockRecorder) Request(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockDataExportService)(nil).Request), ctx, userId)
+}
+
+// RunCleanup mocks base method.
+func (m *MockDataExportService) RunCleanup(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunCleanup", ctx, interval)
+}
+
+// RunCleanup indicates an expected call of RunCleanup.
+func (mr *MockDataExportServiceMockRecorder) RunCleanup(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCleanup", reflect.TypeOf((*MockDataExportService)(nil).RunCleanup), ctx, interval)
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/follow.go -package=svcmocks -destination=./internal/service/mocks/follow.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowService is a mock of FollowService interface.
+type MockFollowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowServiceMockRecorder
+}
+
+// MockFollowServiceMockRecorder is the mock recorder for MockFollowService.
+type MockFollowServiceMockRecorder struct {
+	mock *MockFollowService
+}
+
+// NewMockFollowService creates a new mock instance.
+func NewMockFollowService(ctrl *gomock.Controller) *MockFollowService {
+	mock := &MockFollowService{ctrl: ctrl}
+	mock.recorder = &MockFollowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowService) EXPECT() *MockFollowServiceMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockFollowService) Follow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowServiceMockRecorder) Follow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollowService)(nil).Follow), ctx, follower, followee)
+}
+
+// Followees mocks base method.
+func (m *MockFollowService) Followees(ctx context.Context, userId, cursor int64, limit int) ([]domain.FollowRelation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Followees", ctx, userId, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Followees indicates an expected call of Followees.
+func (mr *MockFollowServiceMockRecorder) Followees(ctx, userId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Followees", reflect.TypeOf((*MockFollowService)(nil).Followees), ctx, userId, cursor, limit)
+}
+
+// Followers mocks base method.
+func (m *MockFollowService) Followers(ctx context.Context, userId, cursor int64, limit int) ([]domain.FollowRelation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Followers", ctx, userId, cursor, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Followers indicates an expected call of Followers.
+func (mr *MockFollowServiceMockRecorder) Followers(ctx, userId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Followers", reflect.TypeOf((*MockFollowService)(nil).Followers), ctx, userId, cursor, limit)
+}
+
+// Following mocks base method.
+func (m *MockFollowService) Following(ctx context.Context, follower, followee int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Following", ctx, follower, followee)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Following indicates an expected call of Following.
+func (mr *MockFollowServiceMockRecorder) Following(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Following", reflect.TypeOf((*MockFollowService)(nil).Following), ctx, follower, followee)
+}
+
+// OnUserMerged mocks base method.
+func (m *MockFollowService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserMerged", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserMerged indicates an expected call of OnUserMerged.
+func (mr *MockFollowServiceMockRecorder) OnUserMerged(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserMerged", reflect.TypeOf((*MockFollowService)(nil).OnUserMerged), ctx, evt)
+}
+
+// OnUserPurged mocks base method.
+func (m *MockFollowService) OnUserPurged(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserPurged", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserPurged indicates an expected call of OnUserPurged.
+func (mr *MockFollowServiceMockRecorder) OnUserPurged(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserPurged", reflect.TypeOf((*MockFollowService)(nil).OnUserPurged), ctx, userId)
+}
+
+// Statistic mocks base method.
+func (m *MockFollowService) Statistic(ctx context.Context, userId int64) (domain.FollowStatistic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statistic", ctx, userId)
+	ret0, _ := ret[0].(domain.FollowStatistic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statistic indicates an expected call of Statistic.
+func (mr *MockFollowServiceMockRecorder) Statistic(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statistic", reflect.TypeOf((*MockFollowService)(nil).Statistic), ctx, userId)
+}
+
+// Unfollow mocks base method.
+func (m *MockFollowService) Unfollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockFollowServiceMockRecorder) Unfollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockFollowService)(nil).Unfollow), ctx, follower, followee)
+}
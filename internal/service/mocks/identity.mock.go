@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/identity.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/identity.go -package=svcmocks -destination=./internal/service/mocks/identity.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityService is a mock of IdentityService interface.
+type MockIdentityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityServiceMockRecorder
+}
+
+// MockIdentityServiceMockRecorder is the mock recorder for MockIdentityService.
+type MockIdentityServiceMockRecorder struct {
+	mock *MockIdentityService
+}
+
+// NewMockIdentityService creates a new mock instance.
+func NewMockIdentityService(ctrl *gomock.Controller) *MockIdentityService {
+	mock := &MockIdentityService{ctrl: ctrl}
+	mock.recorder = &MockIdentityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityService) EXPECT() *MockIdentityServiceMockRecorder {
+	return m.recorder
+}
+
+// BindPhone mocks base method.
+func (m *MockIdentityService) BindPhone(ctx context.Context, userId int64, phone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindPhone", ctx, userId, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindPhone indicates an expected call of BindPhone.
+func (mr *MockIdentityServiceMockRecorder) BindPhone(ctx, userId, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindPhone", reflect.TypeOf((*MockIdentityService)(nil).BindPhone), ctx, userId, phone)
+}
+
+// BindWeChat mocks base method.
+func (m *MockIdentityService) BindWeChat(ctx context.Context, userId int64, info domain.WeChatResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindWeChat", ctx, userId, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindWeChat indicates an expected call of BindWeChat.
+func (mr *MockIdentityServiceMockRecorder) BindWeChat(ctx, userId, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindWeChat", reflect.TypeOf((*MockIdentityService)(nil).BindWeChat), ctx, userId, info)
+}
+
+// List mocks base method.
+func (m *MockIdentityService) List(ctx context.Context, userId int64) ([]domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIdentityServiceMockRecorder) List(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIdentityService)(nil).List), ctx, userId)
+}
+
+// Owner mocks base method.
+func (m *MockIdentityService) Owner(ctx context.Context, provider, subject string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Owner", ctx, provider, subject)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Owner indicates an expected call of Owner.
+func (mr *MockIdentityServiceMockRecorder) Owner(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Owner", reflect.TypeOf((*MockIdentityService)(nil).Owner), ctx, provider, subject)
+}
+
+// PrepareWeChatBind mocks base method.
+func (m *MockIdentityService) PrepareWeChatBind(ctx context.Context, state string, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareWeChatBind", ctx, state, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrepareWeChatBind indicates an expected call of PrepareWeChatBind.
+func (mr *MockIdentityServiceMockRecorder) PrepareWeChatBind(ctx, state, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareWeChatBind", reflect.TypeOf((*MockIdentityService)(nil).PrepareWeChatBind), ctx, state, userId)
+}
+
+// TakeWeChatBind mocks base method.
+func (m *MockIdentityService) TakeWeChatBind(ctx context.Context, state string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeWeChatBind", ctx, state)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeWeChatBind indicates an expected call of TakeWeChatBind.
+func (mr *MockIdentityServiceMockRecorder) TakeWeChatBind(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeWeChatBind", reflect.TypeOf((*MockIdentityService)(nil).TakeWeChatBind), ctx, state)
+}
+
+// Unbind mocks base method.
+func (m *MockIdentityService) Unbind(ctx context.Context, userId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unbind", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unbind indicates an expected call of Unbind.
+func (mr *MockIdentityServiceMockRecorder) Unbind(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unbind", reflect.TypeOf((*MockIdentityService)(nil).Unbind), ctx, userId, id)
+}
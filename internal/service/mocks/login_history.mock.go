@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/login_history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/login_history.go -package=svcmocks -destination=./internal/service/mocks/login_history.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginHistoryService is a mock of LoginHistoryService interface.
+type MockLoginHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginHistoryServiceMockRecorder
+}
+
+// MockLoginHistoryServiceMockRecorder is the mock recorder for MockLoginHistoryService.
+type MockLoginHistoryServiceMockRecorder struct {
+	mock *MockLoginHistoryService
+}
+
+// NewMockLoginHistoryService creates a new mock instance.
+func NewMockLoginHistoryService(ctrl *gomock.Controller) *MockLoginHistoryService {
+	mock := &MockLoginHistoryService{ctrl: ctrl}
+	mock.recorder = &MockLoginHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginHistoryService) EXPECT() *MockLoginHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// OnUserMerged mocks base method.
+func (m *MockLoginHistoryService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserMerged", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserMerged indicates an expected call of OnUserMerged.
+func (mr *MockLoginHistoryServiceMockRecorder) OnUserMerged(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserMerged", reflect.TypeOf((*MockLoginHistoryService)(nil).OnUserMerged), ctx, evt)
+}
+
+// OnUserPurged mocks base method.
+func (m *MockLoginHistoryService) OnUserPurged(ctx context.Context, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserPurged", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserPurged indicates an expected call of OnUserPurged.
+func (mr *MockLoginHistoryServiceMockRecorder) OnUserPurged(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserPurged", reflect.TypeOf((*MockLoginHistoryService)(nil).OnUserPurged), ctx, userId)
+}
+
+// PruneExpired mocks base method.
+func (m *MockLoginHistoryService) PruneExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneExpired indicates an expected call of PruneExpired.
+func (mr *MockLoginHistoryServiceMockRecorder) PruneExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneExpired", reflect.TypeOf((*MockLoginHistoryService)(nil).PruneExpired), ctx)
+}
+
+// Recent mocks base method.
+func (m *MockLoginHistoryService) Recent(ctx context.Context, userId, cursor int64, limit int) ([]domain.LoginRecord, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recent", ctx, userId, cursor, limit)
+	ret0, _ := ret[0].([]domain.LoginRecord)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Recent indicates an expected call of Recent.
+func (mr *MockLoginHistoryServiceMockRecorder) Recent(ctx, userId, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recent", reflect.TypeOf((*MockLoginHistoryService)(nil).Recent), ctx, userId, cursor, limit)
+}
+
+// Record mocks base method.
+func (m *MockLoginHistoryService) Record(ctx context.Context, r domain.LoginRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockLoginHistoryServiceMockRecorder) Record(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockLoginHistoryService)(nil).Record), ctx, r)
+}
+
+// RunPrune mocks base method.
+func (m *MockLoginHistoryService) RunPrune(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunPrune", ctx, interval)
+}
+
+// RunPrune indicates an expected call of RunPrune.
+func (mr *MockLoginHistoryServiceMockRecorder) RunPrune(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPrune", reflect.TypeOf((*MockLoginHistoryService)(nil).RunPrune), ctx, interval)
+}
+
+// Search mocks base method.
+func (m *MockLoginHistoryService) Search(ctx context.Context, filter domain.LoginRecordFilter, page, pageSize int) ([]domain.LoginRecord, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter, page, pageSize)
+	ret0, _ := ret[0].([]domain.LoginRecord)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockLoginHistoryServiceMockRecorder) Search(ctx, filter, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockLoginHistoryService)(nil).Search), ctx, filter, page, pageSize)
+}
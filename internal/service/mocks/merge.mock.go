@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/merge.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/merge.go -package=svcmocks -destination=./internal/service/mocks/merge.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserMergedListener is a mock of UserMergedListener interface.
+type MockUserMergedListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMergedListenerMockRecorder
+}
+
+// MockUserMergedListenerMockRecorder is the mock recorder for MockUserMergedListener.
+type MockUserMergedListenerMockRecorder struct {
+	mock *MockUserMergedListener
+}
+
+// NewMockUserMergedListener creates a new mock instance.
+func NewMockUserMergedListener(ctrl *gomock.Controller) *MockUserMergedListener {
+	mock := &MockUserMergedListener{ctrl: ctrl}
+	mock.recorder = &MockUserMergedListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserMergedListener) EXPECT() *MockUserMergedListenerMockRecorder {
+	return m.recorder
+}
+
+// OnUserMerged mocks base method.
+func (m *MockUserMergedListener) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserMerged", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserMerged indicates an expected call of OnUserMerged.
+func (mr *MockUserMergedListenerMockRecorder) OnUserMerged(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserMerged", reflect.TypeOf((*MockUserMergedListener)(nil).OnUserMerged), ctx, evt)
+}
+
+// MockAccountMergeService is a mock of AccountMergeService interface.
+type MockAccountMergeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountMergeServiceMockRecorder
+}
+
+// MockAccountMergeServiceMockRecorder is the mock recorder for MockAccountMergeService.
+type MockAccountMergeServiceMockRecorder struct {
+	mock *MockAccountMergeService
+}
+
+// NewMockAccountMergeService creates a new mock instance.
+func NewMockAccountMergeService(ctrl *gomock.Controller) *MockAccountMergeService {
+	mock := &MockAccountMergeService{ctrl: ctrl}
+	mock.recorder = &MockAccountMergeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountMergeService) EXPECT() *MockAccountMergeServiceMockRecorder {
+	return m.recorder
+}
+
+// Merge mocks base method.
+func (m *MockAccountMergeService) Merge(ctx context.Context, targetId, sourceId int64) (domain.UserMergedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, targetId, sourceId)
+	ret0, _ := ret[0].(domain.UserMergedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockAccountMergeServiceMockRecorder) Merge(ctx, targetId, sourceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockAccountMergeService)(nil).Merge), ctx, targetId, sourceId)
+}
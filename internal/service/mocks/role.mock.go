@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/role.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/role.go -package=svcmocks -destination=./internal/service/mocks/role.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleService is a mock of RoleService interface.
+type MockRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleServiceMockRecorder
+}
+
+// MockRoleServiceMockRecorder is the mock recorder for MockRoleService.
+type MockRoleServiceMockRecorder struct {
+	mock *MockRoleService
+}
+
+// NewMockRoleService creates a new mock instance.
+func NewMockRoleService(ctrl *gomock.Controller) *MockRoleService {
+	mock := &MockRoleService{ctrl: ctrl}
+	mock.recorder = &MockRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleService) EXPECT() *MockRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockRoleService) Assign(ctx context.Context, userId int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, userId, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockRoleServiceMockRecorder) Assign(ctx, userId, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRoleService)(nil).Assign), ctx, userId, roleName)
+}
+
+// Authz mocks base method.
+func (m *MockRoleService) Authz(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authz", ctx, userId)
+	ret0, _ := ret[0].(domain.UserAuthz)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authz indicates an expected call of Authz.
+func (mr *MockRoleServiceMockRecorder) Authz(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authz", reflect.TypeOf((*MockRoleService)(nil).Authz), ctx, userId)
+}
+
+// HasPermission mocks base method.
+func (m *MockRoleService) HasPermission(ctx context.Context, userId int64, permission string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPermission", ctx, userId, permission)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPermission indicates an expected call of HasPermission.
+func (mr *MockRoleServiceMockRecorder) HasPermission(ctx, userId, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermission", reflect.TypeOf((*MockRoleService)(nil).HasPermission), ctx, userId, permission)
+}
+
+// OnUserMerged mocks base method.
+func (m *MockRoleService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserMerged", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserMerged indicates an expected call of OnUserMerged.
+func (mr *MockRoleServiceMockRecorder) OnUserMerged(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserMerged", reflect.TypeOf((*MockRoleService)(nil).OnUserMerged), ctx, evt)
+}
+
+// Revoke mocks base method.
+func (m *MockRoleService) Revoke(ctx context.Context, userId int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userId, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRoleServiceMockRecorder) Revoke(ctx, userId, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoleService)(nil).Revoke), ctx, userId, roleName)
+}
+
+// Roles mocks base method.
+func (m *MockRoleService) Roles(ctx context.Context, userId int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *MockRoleServiceMockRecorder) Roles(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockRoleService)(nil).Roles), ctx, userId)
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/security.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/security.go -package=svcmocks -destination=./internal/service/mocks/security.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSecurityEventService is a mock of SecurityEventService interface.
+type MockSecurityEventService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecurityEventServiceMockRecorder
+}
+
+// MockSecurityEventServiceMockRecorder is the mock recorder for MockSecurityEventService.
+type MockSecurityEventServiceMockRecorder struct {
+	mock *MockSecurityEventService
+}
+
+// NewMockSecurityEventService creates a new mock instance.
+func NewMockSecurityEventService(ctrl *gomock.Controller) *MockSecurityEventService {
+	mock := &MockSecurityEventService{ctrl: ctrl}
+	mock.recorder = &MockSecurityEventServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecurityEventService) EXPECT() *MockSecurityEventServiceMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockSecurityEventService) Record(ctx context.Context, e domain.SecurityEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockSecurityEventServiceMockRecorder) Record(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockSecurityEventService)(nil).Record), ctx, e)
+}
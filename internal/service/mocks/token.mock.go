@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/token.go -package=svcmocks -destination=./internal/service/mocks/token.mock.go
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPersonalTokenService is a mock of PersonalTokenService interface.
+type MockPersonalTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalTokenServiceMockRecorder
+}
+
+// MockPersonalTokenServiceMockRecorder is the mock recorder for MockPersonalTokenService.
+type MockPersonalTokenServiceMockRecorder struct {
+	mock *MockPersonalTokenService
+}
+
+// NewMockPersonalTokenService creates a new mock instance.
+func NewMockPersonalTokenService(ctrl *gomock.Controller) *MockPersonalTokenService {
+	mock := &MockPersonalTokenService{ctrl: ctrl}
+	mock.recorder = &MockPersonalTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalTokenService) EXPECT() *MockPersonalTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonalTokenService) Create(ctx context.Context, userId int64, name string, scopes []string, expireAt int64) (string, domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, name, scopes, expireAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(domain.PersonalToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalTokenServiceMockRecorder) Create(ctx, userId, name, scopes, expireAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalTokenService)(nil).Create), ctx, userId, name, scopes, expireAt)
+}
+
+// List mocks base method.
+func (m *MockPersonalTokenService) List(ctx context.Context, userId int64) ([]domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]domain.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPersonalTokenServiceMockRecorder) List(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPersonalTokenService)(nil).List), ctx, userId)
+}
+
+// OnUserMerged mocks base method.
+func (m *MockPersonalTokenService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnUserMerged", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnUserMerged indicates an expected call of OnUserMerged.
+func (mr *MockPersonalTokenServiceMockRecorder) OnUserMerged(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUserMerged", reflect.TypeOf((*MockPersonalTokenService)(nil).OnUserMerged), ctx, evt)
+}
+
+// Revoke mocks base method.
+func (m *MockPersonalTokenService) Revoke(ctx context.Context, userId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPersonalTokenServiceMockRecorder) Revoke(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPersonalTokenService)(nil).Revoke), ctx, userId, id)
+}
+
+// RevokeAll mocks base method.
+func (m *MockPersonalTokenService) RevokeAll(ctx context.Context, userId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockPersonalTokenServiceMockRecorder) RevokeAll(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockPersonalTokenService)(nil).RevokeAll), ctx, userId)
+}
+
+// Verify mocks base method.
+func (m *MockPersonalTokenService) Verify(ctx context.Context, token string) (domain.PersonalToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(domain.PersonalToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockPersonalTokenServiceMockRecorder) Verify(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockPersonalTokenService)(nil).Verify), ctx, token)
+}
@@ -0,0 +1,89 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idSaltLen = 16
+	argon2idKeyLen  = 32
+)
+
+// argon2id的参数，Memory的单位是KiB
+type Argon2idParams struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+type argon2idAlgorithm struct {
+	params Argon2idParams
+}
+
+func NewArgon2id(params Argon2idParams) Algorithm {
+	return &argon2idAlgorithm{params: params}
+}
+
+// PHC格式：$argon2id$v=19$m=65536,t=3,p=2$salt$key，salt和key是不带padding的base64
+func (a *argon2idAlgorithm) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := a.params
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, argon2idKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (a *argon2idAlgorithm) Supports(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (a *argon2idAlgorithm) Verify(hash string, password string) (bool, error) {
+	p, salt, key, err := a.decode(hash)
+	if err != nil {
+		return false, err
+	}
+	actual := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+func (a *argon2idAlgorithm) Outdated(hash string) bool {
+	p, _, _, err := a.decode(hash)
+	if err != nil {
+		return true
+	}
+	return p.Memory < a.params.Memory || p.Time < a.params.Time || p.Threads < a.params.Threads
+}
+
+func (a *argon2idAlgorithm) decode(hash string) (Argon2idParams, []byte, []byte, error) {
+	// 第一段是空字符串
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+	var p Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+	return p, salt, key, nil
+}
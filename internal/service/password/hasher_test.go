@@ -0,0 +1,76 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试用小参数，跑得快
+var testArgon2idParams = Argon2idParams{Memory: 1024, Time: 1, Threads: 1}
+
+func TestHasher(t *testing.T) {
+	bcryptHash, err := NewBcrypt(4).Hash("hello#world123")
+	require.NoError(t, err)
+	argonHash, err := NewArgon2id(testArgon2idParams).Hash("hello#world123")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		hasher Hasher
+		hash   string
+
+		wantRehash bool
+	}{
+		{
+			name:   "当前就是argon2id",
+			hasher: NewHasher(NewArgon2id(testArgon2idParams), NewBcrypt(4)),
+			hash:   argonHash,
+		},
+		{
+			name:       "bcrypt升级到argon2id",
+			hasher:     NewHasher(NewArgon2id(testArgon2idParams), NewBcrypt(4)),
+			hash:       bcryptHash,
+			wantRehash: true,
+		},
+		{
+			name:       "argon2id的参数调高了",
+			hasher:     NewHasher(NewArgon2id(Argon2idParams{Memory: 2048, Time: 1, Threads: 1}), NewBcrypt(4)),
+			hash:       argonHash,
+			wantRehash: true,
+		},
+		{
+			name:       "bcrypt的cost调高了",
+			hasher:     NewHasher(NewBcrypt(5), NewArgon2id(testArgon2idParams)),
+			hash:       bcryptHash,
+			wantRehash: true,
+		},
+		{
+			name:       "降级回bcrypt",
+			hasher:     NewHasher(NewBcrypt(4), NewArgon2id(testArgon2idParams)),
+			hash:       argonHash,
+			wantRehash: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := tc.hasher.Verify(tc.hash, "hello#world123")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			ok, err = tc.hasher.Verify(tc.hash, "wrong")
+			require.NoError(t, err)
+			assert.False(t, ok)
+			assert.Equal(t, tc.wantRehash, tc.hasher.NeedsRehash(tc.hash))
+		})
+	}
+}
+
+func TestHasher_UnknownHash(t *testing.T) {
+	h := NewHasher(NewArgon2id(testArgon2idParams))
+	_, err := h.Verify("$2a$10$abc", "hello")
+	assert.Equal(t, ErrUnknownHash, err)
+	_, err = h.Verify("$argon2id$v=19$m=x$salt$key", "hello")
+	assert.Equal(t, ErrUnknownHash, err)
+}
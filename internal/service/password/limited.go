@@ -0,0 +1,33 @@
+package password
+
+// 装饰器，限制同时在算的hash个数
+// argon2id每次要占Memory这么多内存，并发不限制的话一波登录请求就能把实例的内存打满
+type limitedHasher struct {
+	hasher Hasher
+	sem    chan struct{}
+}
+
+// maxConcurrency小于等于0表示不限制
+func NewLimitedHasher(hasher Hasher, maxConcurrency int) Hasher {
+	if maxConcurrency <= 0 {
+		return hasher
+	}
+	return &limitedHasher{hasher: hasher, sem: make(chan struct{}, maxConcurrency)}
+}
+
+func (l *limitedHasher) Hash(password string) (string, error) {
+	l.sem <- struct{}{}
+	defer func() { <-l.sem }()
+	return l.hasher.Hash(password)
+}
+
+func (l *limitedHasher) Verify(hash string, password string) (bool, error) {
+	l.sem <- struct{}{}
+	defer func() { <-l.sem }()
+	return l.hasher.Verify(hash, password)
+}
+
+// 只解析hash的参数，不用排队
+func (l *limitedHasher) NeedsRehash(hash string) bool {
+	return l.hasher.NeedsRehash(hash)
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 记录同时在算的个数
+type slowHasher struct {
+	running atomic.Int64
+	peak    atomic.Int64
+}
+
+func (s *slowHasher) Hash(password string) (string, error) {
+	n := s.running.Add(1)
+	defer s.running.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond * 10)
+	return password, nil
+}
+
+func (s *slowHasher) Verify(hash string, password string) (bool, error) {
+	_, err := s.Hash(password)
+	return hash == password, err
+}
+
+func (s *slowHasher) NeedsRehash(hash string) bool {
+	return false
+}
+
+func TestLimitedHasher(t *testing.T) {
+	slow := &slowHasher{}
+	h := NewLimitedHasher(slow, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, _ = h.Hash("a")
+			} else {
+				_, _ = h.Verify("a", "a")
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int64(2), slow.peak.Load())
+
+	// 不限制时原样返回
+	assert.Equal(t, Hasher(slow), NewLimitedHasher(slow, 0))
+}
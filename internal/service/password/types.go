@@ -0,0 +1,51 @@
+package password
+
+import "errors"
+
+var ErrUnknownHash = errors.New("无法识别的密码hash格式")
+
+// 一种哈希算法，生成的hash自带算法和参数
+type Algorithm interface {
+	Hash(password string) (string, error)
+	// hash是不是这个算法生成的
+	Supports(hash string) bool
+	Verify(hash string, password string) (bool, error)
+	// 同一个算法，但是参数比当前配置的弱
+	Outdated(hash string) bool
+}
+
+// service用的密码哈希，新密码用当前配置的算法，老的hash也能校验
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(hash string, password string) (bool, error)
+	// 不是当前算法和参数生成的，登录成功后应该重新哈希
+	NeedsRehash(hash string) bool
+}
+
+type hasher struct {
+	current Algorithm
+	// 包括current，校验老的hash用
+	all []Algorithm
+}
+
+// legacy是还需要能校验、但不再用来生成hash的算法
+func NewHasher(current Algorithm, legacy ...Algorithm) Hasher {
+	return &hasher{current: current, all: append([]Algorithm{current}, legacy...)}
+}
+
+func (h *hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *hasher) Verify(hash string, password string) (bool, error) {
+	for _, algo := range h.all {
+		if algo.Supports(hash) {
+			return algo.Verify(hash, password)
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+func (h *hasher) NeedsRehash(hash string) bool {
+	return !h.current.Supports(hash) || h.current.Outdated(hash)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrHandleInvalid  = errors.New("用户名格式不正确")
+	ErrHandleReserved = errors.New("用户名是保留字")
+	ErrHandleTaken    = repository.ErrHandleTaken
+	ErrHandleTooSoon  = repository.ErrHandleTooSoon
+)
+
+// 字母开头，只能有小写字母、数字和下划线，3到20位
+var handleReg = regexp.MustCompile(`^[a-z][a-z0-9_]{2,19}$`)
+
+// 和路由、系统角色容易混淆的用户名不让用
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true,
+	"api": true, "user": true, "users": true, "u": true, "me": true,
+	"login": true, "logout": true, "signup": true, "oauth2": true,
+	"settings": true, "static": true, "support": true, "help": true,
+	"about": true, "webook": true, "null": true, "undefined": true,
+}
+
+// 用户名和公开主页
+type ProfileService interface {
+	// 设置或者修改用户名，不区分大小写
+	ClaimHandle(ctx context.Context, userId int64, handle string) (string, error)
+	// 公开主页，正在注销的用户查不到
+	FindByHandle(ctx context.Context, handle string) (domain.PublicProfile, error)
+}
+
+type profileService struct {
+	repo repository.UserRepository
+	// 两次修改用户名之间至少隔多久
+	cooldown time.Duration
+}
+
+func NewProfileService(repo repository.UserRepository, cooldown time.Duration) ProfileService {
+	return &profileService{repo: repo, cooldown: cooldown}
+}
+
+func (s *profileService) ClaimHandle(ctx context.Context, userId int64, handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimSpace(handle))
+	if !handleReg.MatchString(handle) {
+		return "", ErrHandleInvalid
+	}
+	if reservedHandles[handle] {
+		return "", ErrHandleReserved
+	}
+	changedBefore := time.Now().Add(-s.cooldown).UnixMilli()
+	err := s.repo.UpdateHandle(ctx, userId, handle, changedBefore)
+	switch err {
+	case nil:
+		return handle, nil
+	case repository.ErrUserNotFound:
+		return "", ErrUserNotFound
+	default:
+		return "", err
+	}
+}
+
+func (s *profileService) FindByHandle(ctx context.Context, handle string) (domain.PublicProfile, error) {
+	handle = strings.ToLower(handle)
+	if !handleReg.MatchString(handle) {
+		return domain.PublicProfile{}, ErrUserNotFound
+	}
+	u, err := s.repo.FindByHandle(ctx, handle)
+	if err == repository.ErrUserNotFound {
+		return domain.PublicProfile{}, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	if u.Deleted() {
+		return domain.PublicProfile{}, ErrUserNotFound
+	}
+	return u.Public(), nil
+}
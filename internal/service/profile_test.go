@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProfileService_ClaimHandle(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.UserRepository
+		handle string
+
+		wantHandle string
+		wantError  error
+	}{
+		{
+			name: "设置成功，统一转小写",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(1), "tom_01", gomock.Any()).Return(nil)
+				return repo
+			},
+			handle:     " Tom_01 ",
+			wantHandle: "tom_01",
+		},
+		{
+			name: "格式不对",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:    "1tom",
+			wantError: ErrHandleInvalid,
+		},
+		{
+			name: "太短",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:    "ab",
+			wantError: ErrHandleInvalid,
+		},
+		{
+			name: "保留字",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:    "Admin",
+			wantError: ErrHandleReserved,
+		},
+		{
+			name: "被占用",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(1), "tom", gomock.Any()).Return(repository.ErrHandleTaken)
+				return repo
+			},
+			handle:    "tom",
+			wantError: ErrHandleTaken,
+		},
+		{
+			name: "冷却期内",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().UpdateHandle(gomock.Any(), int64(1), "tom", gomock.Any()).Return(repository.ErrHandleTooSoon)
+				return repo
+			},
+			handle:    "tom",
+			wantError: ErrHandleTooSoon,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewProfileService(tc.mock(ctrl), time.Hour)
+			handle, err := svc.ClaimHandle(context.Background(), 1, tc.handle)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantHandle, handle)
+		})
+	}
+}
+
+func TestProfileService_ClaimHandleCooldown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockUserRepository(ctrl)
+	var changedBefore int64
+	repo.EXPECT().UpdateHandle(gomock.Any(), int64(1), "tom", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userId int64, handle string, before int64) error {
+			changedBefore = before
+			return nil
+		})
+	svc := NewProfileService(repo, time.Hour)
+	_, err := svc.ClaimHandle(context.Background(), 1, "tom")
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(-time.Hour).UnixMilli(), changedBefore, float64(time.Second.Milliseconds()))
+}
+
+func TestProfileService_FindByHandle(t *testing.T) {
+	user := domain.User{
+		Id:            1,
+		Handle:        "tom",
+		NickName:      "Tom",
+		Email:         "tom@example.com",
+		Phone:         "13800000000",
+		WeChatOpenId:  "open",
+		WeChatUnionId: "union",
+		CreatetAt:     100,
+	}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.UserRepository
+		handle string
+
+		wantProfile domain.PublicProfile
+		wantError   error
+	}{
+		{
+			name: "查到",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByHandle(gomock.Any(), "tom").Return(user, nil)
+				return repo
+			},
+			handle:      "Tom",
+			wantProfile: domain.PublicProfile{Id: 1, Handle: "tom", NickName: "Tom", CreatedAt: 100},
+		},
+		{
+			name: "格式不对的直接当作不存在",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			handle:    "t",
+			wantError: ErrUserNotFound,
+		},
+		{
+			name: "不存在",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByHandle(gomock.Any(), "tom").Return(domain.User{}, repository.ErrUserNotFound)
+				return repo
+			},
+			handle:    "tom",
+			wantError: ErrUserNotFound,
+		},
+		{
+			name: "正在注销",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				deleted := user
+				deleted.DeletedAt = 1
+				repo.EXPECT().FindByHandle(gomock.Any(), "tom").Return(deleted, nil)
+				return repo
+			},
+			handle:    "tom",
+			wantError: ErrUserNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewProfileService(tc.mock(ctrl), time.Hour)
+			profile, err := svc.FindByHandle(context.Background(), tc.handle)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantProfile, profile)
+		})
+	}
+}
+
+// 公开主页序列化出来不能带联系方式
+func TestPublicProfileJSON(t *testing.T) {
+	val, err := json.Marshal(domain.User{
+		Id: 1, Handle: "tom", Email: "tom@example.com", Phone: "13800000000",
+		WeChatOpenId: "open", WeChatUnionId: "union",
+	}.Public())
+	require.NoError(t, err)
+	for _, field := range []string{"email", "phone", "weChatOpenId", "weChatUnionId", "tom@example.com", "13800000000"} {
+		assert.NotContains(t, string(val), field)
+	}
+}
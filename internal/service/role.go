@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+type RoleService interface {
+	// 用户的角色名，签发token时放到claims里
+	Roles(ctx context.Context, userId int64) ([]string, error)
+	Authz(ctx context.Context, userId int64) (domain.UserAuthz, error)
+	HasPermission(ctx context.Context, userId int64, permission string) (bool, error)
+	Assign(ctx context.Context, userId int64, roleName string) error
+	Revoke(ctx context.Context, userId int64, roleName string) error
+	// 账号合并后，被合并账号的角色归到保留的账号上
+	UserMergedListener
+}
+
+var ErrRoleNotFound = errors.New("角色不存在")
+
+type roleService struct {
+	repo repository.RoleRepository
+}
+
+func NewRoleService(repo repository.RoleRepository) RoleService {
+	return &roleService{repo: repo}
+}
+
+func (s *roleService) Roles(ctx context.Context, userId int64) ([]string, error) {
+	authz, err := s.repo.FindAuthz(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return authz.Roles, nil
+}
+
+func (s *roleService) Authz(ctx context.Context, userId int64) (domain.UserAuthz, error) {
+	return s.repo.FindAuthz(ctx, userId)
+}
+
+func (s *roleService) HasPermission(ctx context.Context, userId int64, permission string) (bool, error) {
+	authz, err := s.repo.FindAuthz(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return authz.HasPermission(permission), nil
+}
+
+func (s *roleService) Assign(ctx context.Context, userId int64, roleName string) error {
+	err := s.repo.Assign(ctx, userId, roleName)
+	if err == repository.ErrRoleNotFound {
+		return ErrRoleNotFound
+	}
+	return err
+}
+
+func (s *roleService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	return s.repo.MoveUser(ctx, evt.SourceId, evt.TargetId)
+}
+
+func (s *roleService) Revoke(ctx context.Context, userId int64, roleName string) error {
+	err := s.repo.Revoke(ctx, userId, roleName)
+	if err == repository.ErrRoleNotFound {
+		return ErrRoleNotFound
+	}
+	return err
+}
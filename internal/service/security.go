@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// 记录安全相关的事件，方便事后排查
+type SecurityEventService interface {
+	Record(ctx context.Context, e domain.SecurityEvent) error
+}
+
+type securityEventService struct {
+	repo repository.SecurityEventRepository
+}
+
+func NewSecurityEventService(repo repository.SecurityEventRepository) SecurityEventService {
+	return &securityEventService{repo: repo}
+}
+
+func (s *securityEventService) Record(ctx context.Context, e domain.SecurityEvent) error {
+	return s.repo.Create(ctx, e)
+}
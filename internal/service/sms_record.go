@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// 短信发送记录，目前只有注销清理时用到
+type SmsRecordService interface {
+	// 去掉发给用户手机号的记录，记录里有手机号，还可能有验证码
+	UserPurgedListener
+}
+
+type smsRecordService struct {
+	users      repository.UserRepository
+	identities repository.IdentityRepository
+	sms        repository.SmsRepository
+}
+
+func NewSmsRecordService(users repository.UserRepository, identities repository.IdentityRepository,
+	sms repository.SmsRepository) SmsRecordService {
+	return &smsRecordService{users: users, identities: identities, sms: sms}
+}
+
+// 在抹掉users表之前调用，这时手机号还查得到；重试时手机号还在，可以再来一遍
+func (s *smsRecordService) OnUserPurged(ctx context.Context, userId int64) error {
+	u, err := s.users.FindById(ctx, userId)
+	if err == repository.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	identities, err := s.identities.FindByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	// 和导出一样按手机号身份找，再加上资料里的手机号
+	numbers := map[string]struct{}{}
+	if u.Phone != "" {
+		numbers[u.Phone] = struct{}{}
+	}
+	for _, i := range identities {
+		if i.Provider == domain.IdentityPhone {
+			numbers[i.Subject] = struct{}{}
+		}
+	}
+	for number := range numbers {
+		if err = s.sms.RemoveNumber(ctx, number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
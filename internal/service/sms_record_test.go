@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSmsRecordService_OnUserPurged(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository,
+			repository.SmsRepository)
+		wantErr error
+	}{
+		{
+			name: "资料和身份里的手机号都去掉，重复的只处理一次",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository,
+				repository.SmsRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				identities := repomocks.NewMockIdentityRepository(ctrl)
+				sms := repomocks.NewMockSmsRepository(ctrl)
+				users.EXPECT().FindById(gomock.Any(), int64(9)).Return(domain.User{Id: 9, Phone: "13800000000"}, nil)
+				identities.EXPECT().FindByUser(gomock.Any(), int64(9)).Return([]domain.Identity{
+					{Provider: domain.IdentityPhone, Subject: "13800000000"},
+					{Provider: domain.IdentityPhone, Subject: "13900000000"},
+					{Provider: domain.IdentityEmail, Subject: "a@qq.com"},
+				}, nil)
+				sms.EXPECT().RemoveNumber(gomock.Any(), "13800000000").Return(nil)
+				sms.EXPECT().RemoveNumber(gomock.Any(), "13900000000").Return(nil)
+				return users, identities, sms
+			},
+		},
+		{
+			name: "用户已经抹掉了",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository,
+				repository.SmsRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				users.EXPECT().FindById(gomock.Any(), int64(9)).Return(domain.User{}, repository.ErrUserNotFound)
+				return users, repomocks.NewMockIdentityRepository(ctrl), repomocks.NewMockSmsRepository(ctrl)
+			},
+		},
+		{
+			name: "删除失败",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.IdentityRepository,
+				repository.SmsRepository) {
+				users := repomocks.NewMockUserRepository(ctrl)
+				identities := repomocks.NewMockIdentityRepository(ctrl)
+				sms := repomocks.NewMockSmsRepository(ctrl)
+				users.EXPECT().FindById(gomock.Any(), int64(9)).Return(domain.User{Id: 9, Phone: "13800000000"}, nil)
+				identities.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(nil, nil)
+				sms.EXPECT().RemoveNumber(gomock.Any(), "13800000000").Return(errors.New("db错误"))
+				return users, identities, sms
+			},
+			wantErr: errors.New("db错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewSmsRecordService(tc.mock(ctrl))
+			err := svc.OnUserPurged(context.Background(), 9)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errInvalidKey = errors.New("非法的key")
+
+// 存在本地目录，由web服务器以静态文件的方式对外提供
+// 多实例部署时目录必须是共享存储
+type Service struct {
+	dir string
+	// 静态文件的访问前缀，比如 http://localhost:8080/static
+	baseURL string
+}
+
+func NewService(dir string, baseURL string) *Service {
+	return &Service{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *Service) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// 先写临时文件再改名，读的人不会看到写了一半的文件
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *Service) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// key不能跳出存储目录
+func (s *Service) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errInvalidKey
+	}
+	return filepath.Join(s.dir, clean), nil
+}
+
+// 本地存储的文件由web服务器提供，请求的路径就是key，不列目录
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := s.path(r.URL.Path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
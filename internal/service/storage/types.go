@@ -0,0 +1,12 @@
+package storage
+
+import "context"
+
+// 对象存储，头像之类的文件通过它保存
+type Service interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// key不存在也算成功
+	Delete(ctx context.Context, key string) error
+	// 对外的访问地址
+	URL(key string) string
+}
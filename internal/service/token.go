@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// 最后使用时间的更新间隔，避免每个请求都写库
+const personalTokenTouchInterval = time.Minute
+
+var (
+	ErrPersonalTokenInvalid  = errors.New("令牌不存在或已过期")
+	ErrPersonalTokenNotFound = errors.New("令牌不存在")
+	ErrPersonalTokenScope    = errors.New("不支持的授权范围")
+)
+
+type PersonalTokenService interface {
+	// 返回令牌明文，只有这一次机会拿到
+	Create(ctx context.Context, userId int64, name string, scopes []string, expireAt int64) (string, domain.PersonalToken, error)
+	// 校验令牌明文，顺便记录最后使用时间
+	Verify(ctx context.Context, token string) (domain.PersonalToken, error)
+	List(ctx context.Context, userId int64) ([]domain.PersonalToken, error)
+	Revoke(ctx context.Context, userId int64, id int64) error
+	// 作废用户所有的令牌，比如重置密码的时候，返回作废的个数
+	RevokeAll(ctx context.Context, userId int64) (int64, error)
+	// 账号合并后，被合并账号的令牌归到保留的账号上
+	UserMergedListener
+}
+
+type personalTokenService struct {
+	repo repository.PersonalTokenRepository
+}
+
+func NewPersonalTokenService(repo repository.PersonalTokenRepository) PersonalTokenService {
+	return &personalTokenService{repo: repo}
+}
+
+func (s *personalTokenService) OnUserMerged(ctx context.Context, evt domain.UserMergedEvent) error {
+	return s.repo.MoveUser(ctx, evt.SourceId, evt.TargetId)
+}
+
+func (s *personalTokenService) Create(ctx context.Context, userId int64, name string, scopes []string, expireAt int64) (string, domain.PersonalToken, error) {
+	for _, scope := range scopes {
+		if !isPersonalTokenScope(scope) {
+			return "", domain.PersonalToken{}, ErrPersonalTokenScope
+		}
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", domain.PersonalToken{}, err
+	}
+	token := domain.PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	t, err := s.repo.Create(ctx, domain.PersonalToken{
+		UserId:   userId,
+		Name:     name,
+		Scopes:   scopes,
+		Prefix:   token[:len(domain.PersonalTokenPrefix)+6],
+		ExpireAt: expireAt,
+	}, hashPersonalToken(token))
+	if err != nil {
+		return "", domain.PersonalToken{}, err
+	}
+	return token, t, nil
+}
+
+func (s *personalTokenService) Verify(ctx context.Context, token string) (domain.PersonalToken, error) {
+	if !strings.HasPrefix(token, domain.PersonalTokenPrefix) {
+		return domain.PersonalToken{}, ErrPersonalTokenInvalid
+	}
+	t, err := s.repo.FindByHash(ctx, hashPersonalToken(token))
+	if err == repository.ErrPersonalTokenNotFound {
+		return domain.PersonalToken{}, ErrPersonalTokenInvalid
+	}
+	if err != nil {
+		return domain.PersonalToken{}, err
+	}
+	now := time.Now()
+	if t.ExpireAt > 0 && now.UnixMilli() > t.ExpireAt {
+		return domain.PersonalToken{}, ErrPersonalTokenInvalid
+	}
+	if now.Sub(time.UnixMilli(t.LastUsedAt)) > personalTokenTouchInterval {
+		// 记录失败不影响请求
+		if err = s.repo.UpdateLastUsed(ctx, t.Id, now.UnixMilli()); err != nil {
+			log.Println("更新令牌最后使用时间失败", err)
+		}
+		t.LastUsedAt = now.UnixMilli()
+	}
+	return t, nil
+}
+
+func (s *personalTokenService) List(ctx context.Context, userId int64) ([]domain.PersonalToken, error) {
+	return s.repo.FindByUser(ctx, userId)
+}
+
+func (s *personalTokenService) Revoke(ctx context.Context, userId int64, id int64) error {
+	err := s.repo.Delete(ctx, userId, id)
+	if err == repository.ErrPersonalTokenNotFound {
+		return ErrPersonalTokenNotFound
+	}
+	return err
+}
+
+func (s *personalTokenService) RevokeAll(ctx context.Context, userId int64) (int64, error) {
+	return s.repo.DeleteByUser(ctx, userId)
+}
+
+// 令牌本身是高熵随机串，不需要加盐慢哈希，sha256就能防止拖库后直接使用
+func hashPersonalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func isPersonalTokenScope(scope string) bool {
+	for _, s := range domain.PersonalTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPersonalTokenService_Verify(t *testing.T) {
+	const token = "wpat_abcdefghijklmnopqrstuvwxyz"
+	now := time.Now().UnixMilli()
+
+	testCases := []struct {
+		name  string
+		mock  func(ctrl *gomock.Controller) repository.PersonalTokenRepository
+		token string
+
+		wantId    int64
+		wantError error
+	}{
+		{
+			name: "校验通过并更新最后使用时间",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), hashPersonalToken(token)).
+					Return(domain.PersonalToken{Id: 1, UserId: 2}, nil)
+				repo.EXPECT().UpdateLastUsed(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				return repo
+			},
+			token:  token,
+			wantId: 1,
+		},
+		{
+			name: "刚用过不重复写库",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), hashPersonalToken(token)).
+					Return(domain.PersonalToken{Id: 1, UserId: 2, LastUsedAt: now}, nil)
+				return repo
+			},
+			token:  token,
+			wantId: 1,
+		},
+		{
+			name: "前缀不对",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				return repomocks.NewMockPersonalTokenRepository(ctrl)
+			},
+			token:     "eyJhbGciOiJFZERTQSJ9",
+			wantError: ErrPersonalTokenInvalid,
+		},
+		{
+			name: "令牌不存在或已吊销",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalToken{}, repository.ErrPersonalTokenNotFound)
+				return repo
+			},
+			token:     token,
+			wantError: ErrPersonalTokenInvalid,
+		},
+		{
+			name: "令牌已过期",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalToken{Id: 1, UserId: 2, ExpireAt: now - 1000}, nil)
+				return repo
+			},
+			token:     token,
+			wantError: ErrPersonalTokenInvalid,
+		},
+		{
+			name: "数据库出错",
+			mock: func(ctrl *gomock.Controller) repository.PersonalTokenRepository {
+				repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalToken{}, errors.New("db error"))
+				return repo
+			},
+			token:     token,
+			wantError: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewPersonalTokenService(tc.mock(ctrl))
+			got, err := svc.Verify(context.Background(), tc.token)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantId, got.Id)
+		})
+	}
+}
+
+func TestPersonalTokenService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockPersonalTokenRepository(ctrl)
+	var storedHash string
+	repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, t domain.PersonalToken, hash string) (domain.PersonalToken, error) {
+			storedHash = hash
+			t.Id = 1
+			return t, nil
+		})
+	svc := NewPersonalTokenService(repo)
+
+	token, info, err := svc.Create(context.Background(), 2, "ci", []string{domain.ScopeProfileRead}, 0)
+	assert.NoError(t, err)
+	// 只落库摘要，前缀用来展示
+	assert.Equal(t, hashPersonalToken(token), storedHash)
+	assert.NotEqual(t, token, storedHash)
+	assert.Equal(t, token[:len(info.Prefix)], info.Prefix)
+
+	_, _, err = svc.Create(context.Background(), 2, "ci", []string{"role:manage"}, 0)
+	assert.Equal(t, ErrPersonalTokenScope, err)
+}
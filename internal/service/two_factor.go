@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/password"
+)
+
+const (
+	// 认证器里显示的服务名
+	totpIssuer = "webook"
+	// 密码校验通过后，多久之内要输入两步验证码
+	twoFactorChallengeTTL = time.Minute * 5
+	// 一个登录凭证最多试几次验证码
+	twoFactorChallengeMaxAttempts = 5
+	// 每次生成的恢复码个数
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorEnabled       = errors.New("两步验证已经启用")
+	ErrTwoFactorNotEnabled    = errors.New("没有启用两步验证")
+	ErrTwoFactorCodeWrong     = errors.New("两步验证码有误")
+	ErrChallengeInvalid       = errors.New("登录凭证无效或已过期")
+	ErrChallengeVerifyTooMany = repository.ErrChallengeVerifyTooMany
+)
+
+type TwoFactorService interface {
+	// 生成新的密钥，确认之前不生效
+	Enroll(ctx context.Context, userId int64) (domain.TwoFactorEnrollment, error)
+	// 用认证器上的验证码确认绑定，返回恢复码明文，只有这一次机会拿到
+	Confirm(ctx context.Context, userId int64, code string) ([]string, error)
+	// 关闭需要当前密码（有密码的账号）和一个有效的验证码或恢复码
+	Disable(ctx context.Context, userId int64, password string, code string) error
+	Enabled(ctx context.Context, userId int64) (bool, error)
+	// 旧的恢复码全部作废
+	RegenerateRecoveryCodes(ctx context.Context, userId int64, code string) ([]string, error)
+
+	// 第一步登录成功后签发登录凭证
+	Challenge(ctx context.Context, userId int64) (string, error)
+	// 校验凭证和验证码，返回登录的用户，凭证只能用一次
+	// 失败时只要凭证对应得上用户也返回，用于记录登录历史和失败次数，不代表登录成功
+	VerifyChallenge(ctx context.Context, challenge string, code string) (int64, error)
+}
+
+type twoFactorService struct {
+	repo     repository.TwoFactorRepository
+	userRepo repository.UserRepository
+	hasher   password.Hasher
+	now      func() time.Time
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, userRepo repository.UserRepository,
+	hasher password.Hasher) TwoFactorService {
+	return &twoFactorService{repo: repo, userRepo: userRepo, hasher: hasher, now: time.Now}
+}
+
+func (s *twoFactorService) Enroll(ctx context.Context, userId int64) (domain.TwoFactorEnrollment, error) {
+	u, err := s.userRepo.FindById(ctx, userId)
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	secret, err := newTotpSecret()
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	err = s.repo.SavePending(ctx, userId, secret)
+	if err == repository.ErrTwoFactorEnabled {
+		return domain.TwoFactorEnrollment{}, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	return domain.TwoFactorEnrollment{Secret: secret, URI: totpURI(secret, accountLabel(u))}, nil
+}
+
+func (s *twoFactorService) Confirm(ctx context.Context, userId int64, code string) ([]string, error) {
+	tf, err := s.repo.FindByUser(ctx, userId)
+	if err == repository.ErrTwoFactorNotFound {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+	step, ok := matchTotp(tf.Secret, normalizeTwoFactorCode(code), s.now())
+	if !ok {
+		return nil, ErrTwoFactorCodeWrong
+	}
+	codes, hashes, err := newRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Enable(ctx, userId, step, hashes)
+	if err == repository.ErrTwoFactorEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userId int64, plain string, code string) error {
+	hash, err := s.userRepo.FindPasswordHash(ctx, userId)
+	if err != nil {
+		return err
+	}
+	// 手机号、微信注册的账号没有密码，只校验验证码
+	if hash != "" {
+		ok, err := s.hasher.Verify(hash, plain)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrPasswordWrong
+		}
+	}
+	if err = s.verify(ctx, userId, code); err != nil {
+		return err
+	}
+	return s.repo.Disable(ctx, userId)
+}
+
+func (s *twoFactorService) Enabled(ctx context.Context, userId int64) (bool, error) {
+	tf, err := s.repo.FindByUser(ctx, userId)
+	if err == repository.ErrTwoFactorNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.Enabled(), nil
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId int64, code string) ([]string, error) {
+	if err := s.verify(ctx, userId, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *twoFactorService) Challenge(ctx context.Context, userId int64) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(buf)
+	if err := s.repo.SetChallenge(ctx, challenge, userId, twoFactorChallengeTTL); err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+func (s *twoFactorService) VerifyChallenge(ctx context.Context, challenge string, code string) (int64, error) {
+	userId, err := s.repo.GetChallenge(ctx, challenge, twoFactorChallengeMaxAttempts)
+	if err == repository.ErrChallengeNotFound {
+		return 0, ErrChallengeInvalid
+	}
+	if err == repository.ErrChallengeVerifyTooMany {
+		return userId, ErrChallengeVerifyTooMany
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err = s.verify(ctx, userId, code); err != nil {
+		return userId, err
+	}
+	// 删除成功的那个请求才算登录成功，并发提交同一个凭证只有一个能过
+	ok, err := s.repo.DeleteChallenge(ctx, challenge)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrChallengeInvalid
+	}
+	return userId, nil
+}
+
+// 6位数字按认证器的验证码校验，其他的按恢复码校验
+func (s *twoFactorService) verify(ctx context.Context, userId int64, code string) error {
+	tf, err := s.repo.FindByUser(ctx, userId)
+	if err == repository.ErrTwoFactorNotFound {
+		return ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+	code = normalizeTwoFactorCode(code)
+	var ok bool
+	if len(code) == totpDigits {
+		step, matched := matchTotp(tf.Secret, code, s.now())
+		if !matched {
+			return ErrTwoFactorCodeWrong
+		}
+		ok, err = s.repo.UseStep(ctx, userId, step)
+	} else {
+		ok, err = s.repo.UseRecoveryCode(ctx, userId, hashRecoveryCode(userId, code))
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTwoFactorCodeWrong
+	}
+	return nil
+}
+
+// 恢复码展示成xxxxx-xxxxx，用户输入时可能带空格、大写
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// 恢复码是随机生成的，不需要慢哈希，带上用户id防止不同用户的相同恢复码撞在一起
+func hashRecoveryCode(userId int64, code string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userId, code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRecoveryCodes(userId int64) ([]string, []string, error) {
+	codes, hashes := make([]string, 0, recoveryCodeCount), make([]string, 0, recoveryCodeCount)
+	buf := make([]byte, 10)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		// 10个字节编码出16个字符，取前10个
+		code := strings.ToLower(totpEncoding.EncodeToString(buf))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(userId, code))
+	}
+	return codes, hashes, nil
+}
+
+// 认证器扫码用的otpauth链接
+func totpURI(secret string, account string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+	label := url.PathEscape(totpIssuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// 认证器里区分账号用，优先用邮箱
+func accountLabel(u domain.User) string {
+	switch {
+	case u.Email != "":
+		return u.Email
+	case u.Phone != "":
+		return u.Phone
+	default:
+		return strconv.FormatInt(u.Id, 10)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// RFC 6238附录B的测试密钥"12345678901234567890"
+const rfcTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCode(t *testing.T) {
+	// RFC里是8位，取后6位
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tc := range testCases {
+		code, err := totpCode(rfcTotpSecret, totpStep(time.Unix(tc.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, code)
+	}
+}
+
+func TestTwoFactorService_VerifyChallenge(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := totpStep(now)
+	enabled := domain.TwoFactor{UserId: 9, Secret: rfcTotpSecret, EnabledAt: 1, LastUsedStep: step - 10}
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.TwoFactorRepository
+		code string
+
+		wantUserId int64
+		wantError  error
+	}{
+		{
+			name: "认证器验证码正确",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(enabled, nil)
+				repo.EXPECT().UseStep(gomock.Any(), int64(9), step).Return(true, nil)
+				repo.EXPECT().DeleteChallenge(gomock.Any(), "c").Return(true, nil)
+				return repo
+			},
+			code:       "005 924",
+			wantUserId: 9,
+		},
+		{
+			name: "验证码已经用过了",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(enabled, nil)
+				repo.EXPECT().UseStep(gomock.Any(), int64(9), step).Return(false, nil)
+				return repo
+			},
+			code:       "005924",
+			wantUserId: 9,
+			wantError:  ErrTwoFactorCodeWrong,
+		},
+		{
+			name: "验证码错误",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(enabled, nil)
+				return repo
+			},
+			code:       "123456",
+			wantUserId: 9,
+			wantError:  ErrTwoFactorCodeWrong,
+		},
+		{
+			name: "试太多次，凭证作废",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).
+					Return(int64(9), repository.ErrChallengeVerifyTooMany)
+				return repo
+			},
+			code:       "123456",
+			wantUserId: 9,
+			wantError:  ErrChallengeVerifyTooMany,
+		},
+		{
+			name: "中途关闭了两步验证",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(domain.TwoFactor{}, repository.ErrTwoFactorNotFound)
+				return repo
+			},
+			code:       "123456",
+			wantUserId: 9,
+			wantError:  ErrTwoFactorNotEnabled,
+		},
+		{
+			name: "使用恢复码",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(enabled, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(9), hashRecoveryCode(9, "abcdefghij")).Return(true, nil)
+				repo.EXPECT().DeleteChallenge(gomock.Any(), "c").Return(true, nil)
+				return repo
+			},
+			code:       "ABCDE-FGHIJ",
+			wantUserId: 9,
+		},
+		{
+			name: "凭证已过期",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(0), repository.ErrChallengeNotFound)
+				return repo
+			},
+			code:      "005924",
+			wantError: ErrChallengeInvalid,
+		},
+		{
+			name: "并发提交，凭证被别的请求用掉了",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().GetChallenge(gomock.Any(), "c", twoFactorChallengeMaxAttempts).Return(int64(9), nil)
+				repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(enabled, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(9), gomock.Any()).Return(true, nil)
+				repo.EXPECT().DeleteChallenge(gomock.Any(), "c").Return(false, nil)
+				return repo
+			},
+			code:      "abcde-fghij",
+			wantError: ErrChallengeInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := &twoFactorService{repo: tc.mock(ctrl), now: func() time.Time { return now }}
+			userId, err := svc.VerifyChallenge(context.Background(), "c", tc.code)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.wantUserId, userId)
+		})
+	}
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockTwoFactorRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(9)).Return(domain.TwoFactor{UserId: 9, Secret: rfcTotpSecret}, nil)
+	var hashes []string
+	repo.EXPECT().Enable(gomock.Any(), int64(9), totpStep(now), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userId int64, step int64, codeHashes []string) error {
+			hashes = codeHashes
+			return nil
+		})
+	svc := &twoFactorService{repo: repo, now: func() time.Time { return now }}
+
+	codes, err := svc.Confirm(context.Background(), 9, "005924")
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	// 落库的只有摘要
+	for i, code := range codes {
+		assert.Equal(t, hashRecoveryCode(9, normalizeTwoFactorCode(code)), hashes[i])
+	}
+}
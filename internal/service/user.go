@@ -3,32 +3,57 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 	"webook/internal/domain"
 	"webook/internal/repository"
-
-	"golang.org/x/crypto/bcrypt"
+	"webook/internal/service/password"
 )
 
 type UserService interface {
 	SignUp(ctx context.Context, user domain.User) error
 	Login(ctx context.Context, user domain.User) (domain.User, error)
+	// 只校验邮箱和密码，不恢复注销中的账号、不升级hash，用来证明账号归属
+	// 注销中的账号返回ErrUserDeleted，被封禁的返回ErrUserBanned
+	VerifyPassword(ctx context.Context, email string, password string) (domain.User, error)
 	Edit(ctx context.Context, userId int64, nickname string, description string, birthday int64) (domain.User, error)
 	FindOne(ctx context.Context, userId int64) (domain.User, error)
+	FindByEmail(ctx context.Context, email string) (domain.User, error)
+	// 忘记密码，验证过邮箱之后直接设置新密码
+	ResetPassword(ctx context.Context, email string, password string) (domain.User, error)
+	// 校验当前密码后修改密码
+	ChangePassword(ctx context.Context, userId int64, oldPassword string, newPassword string) error
+	// 调用方已经用别的方式验证过身份，比如短信验证码
+	SetPassword(ctx context.Context, userId int64, password string) error
+	// 邮箱验证码校验通过后调用
+	VerifyEmail(ctx context.Context, email string) error
+	// 给中间件用，没有邮箱的账号也算通过
+	EmailVerified(ctx context.Context, userId int64) (bool, error)
 	FindOrCreate(ctx context.Context, phone string) (domain.User, error)
 	FindOrCreateByWeChat(ctx context.Context, userInfo domain.WeChatResult) (domain.User, error)
+	// 完整登录（包括两步验证）之后、颁发token之前调用，恢复正在注销的账号
+	// 第一步登录只检查，单凭密码或者验证码不能取消注销
+	Restore(ctx context.Context, userId int64) error
 }
 
 var ErrUserDuplicate = repository.ErrUserDuplicate
 var ErrEmailOrPassWrong = errors.New("邮箱或密码错误")
 var ErrUserNotFound = errors.New("用户不存在")
+var ErrPasswordWrong = errors.New("密码错误")
+var ErrUserDeleted = errors.New("账号已注销")
+
+// 返回这个错误时同时返回用户，调用方可以展示封禁的原因和截止时间
+var ErrUserBanned = errors.New("账号已被封禁")
 
 type userService struct {
 	repo repository.UserRepository
+	// 密码哈希，算法和参数由配置决定
+	hasher password.Hasher
 }
 
 // UserService工厂函数
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, hasher password.Hasher) UserService {
+	return &userService{repo: repo, hasher: hasher}
 }
 
 // handler的ctx先一路带下来
@@ -36,19 +61,43 @@ func (us *userService) SignUp(ctx context.Context, user domain.User) error {
 	// 对密码加密，然后调用repo的insert方法
 
 	// 加密后的密码
-	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
+	hash, err := us.hasher.Hash(user.Password)
 	if err != nil {
 		return err
 	}
 
-	user.Password = string(hash)
+	user.Password = hash
 
 	return us.repo.Create(ctx, user)
 }
 
 func (us *userService) Login(ctx context.Context, user domain.User) (domain.User, error) {
+	u, err := us.checkPassword(ctx, user.Email, user.Password)
+	if err != nil {
+		return domain.User{}, err
+	}
+	us.rehash(ctx, u.Id, u.Password, user.Password)
+	return us.checkLogin(u)
+}
+
+func (us *userService) VerifyPassword(ctx context.Context, email string, password string) (domain.User, error) {
+	u, err := us.checkPassword(ctx, email, password)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if u.Banned(time.Now().UnixMilli()) {
+		return u, ErrUserBanned
+	}
+	if u.Deleted() {
+		return domain.User{}, ErrUserDeleted
+	}
+	return u, nil
+}
+
+// 校验邮箱和密码，没有副作用
+func (us *userService) checkPassword(ctx context.Context, email string, password string) (domain.User, error) {
 	// 先根据Email查找用户
-	u, err := us.repo.FindByEmail(ctx, user.Email)
+	u, err := us.repo.FindByEmail(ctx, email)
 
 	// 用户没找到
 	if err == repository.ErrUserNotFound {
@@ -59,15 +108,68 @@ func (us *userService) Login(ctx context.Context, user domain.User) (domain.User
 		return domain.User{}, err
 	}
 
-	//	比较密码，不太好测
-	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(user.Password))
+	// 手机号、微信注册的账号没有密码
+	if u.Password == "" {
+		return domain.User{}, ErrEmailOrPassWrong
+	}
+	ok, err := us.hasher.Verify(u.Password, password)
 	if err != nil {
+		return domain.User{}, err
+	}
+	if !ok {
 		return domain.User{}, ErrEmailOrPassWrong
 	}
-	// 返回结果
 	return u, nil
 }
 
+// 只有登录成功的时候能拿到明文，趁机把老算法、老参数的hash升级掉，失败了下次登录再试
+func (us *userService) rehash(ctx context.Context, userId int64, hash string, plain string) {
+	if !us.hasher.NeedsRehash(hash) {
+		return
+	}
+	newHash, err := us.hasher.Hash(plain)
+	if err != nil {
+		log.Println("重新哈希密码失败", err)
+		return
+	}
+	if err = us.repo.UpdatePassword(ctx, userId, newHash); err != nil {
+		log.Println("保存升级后的密码hash失败", err)
+	}
+}
+
+// 所有登录方式找到用户之后都走这里，拦住被封禁的账号，过了恢复期、还没来得及清理的当作已经注销
+// 恢复期内的账号先放过去，完整登录之后再调用Restore恢复，封禁的账号走不到那一步
+func (us *userService) checkLogin(u domain.User) (domain.User, error) {
+	now := time.Now().UnixMilli()
+	if u.Banned(now) {
+		return u, ErrUserBanned
+	}
+	if u.Deleted() && now >= u.PurgeAt {
+		return domain.User{}, ErrUserDeleted
+	}
+	return u, nil
+}
+
+// 注销恢复期内登录就恢复账号
+func (us *userService) Restore(ctx context.Context, userId int64) error {
+	u, err := us.repo.FindById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !u.Deleted() {
+		return nil
+	}
+	if time.Now().UnixMilli() >= u.PurgeAt {
+		return ErrUserDeleted
+	}
+	err = us.repo.Restore(ctx, u.Id)
+	// 刚好被清理了
+	if err == repository.ErrUserNotFound {
+		return ErrUserDeleted
+	}
+	return err
+}
+
 // 编辑用户
 func (uc *userService) Edit(ctx context.Context, userId int64, nickname string, description string, birthday int64) (domain.User, error) {
 	//	先查找用户是否存在
@@ -91,9 +193,77 @@ func (uc *userService) FindOne(ctx context.Context, userId int64) (domain.User,
 	return user, nil
 }
 
+func (uc *userService) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	u, err := uc.repo.FindByEmail(ctx, email)
+	if err == repository.ErrUserNotFound {
+		return domain.User{}, ErrUserNotFound
+	}
+	return u, err
+}
+
+func (uc *userService) ResetPassword(ctx context.Context, email string, password string) (domain.User, error) {
+	u, err := uc.FindByEmail(ctx, email)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err = uc.SetPassword(ctx, u.Id, password); err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (uc *userService) ChangePassword(ctx context.Context, userId int64, oldPassword string, newPassword string) error {
+	hash, err := uc.repo.FindPasswordHash(ctx, userId)
+	if err != nil {
+		return err
+	}
+	// 手机号、微信注册的账号没有密码，只能用短信验证码设置
+	if hash == "" {
+		return ErrPasswordWrong
+	}
+	ok, err := uc.hasher.Verify(hash, oldPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPasswordWrong
+	}
+	return uc.SetPassword(ctx, userId, newPassword)
+}
+
+func (uc *userService) SetPassword(ctx context.Context, userId int64, password string) error {
+	hash, err := uc.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	return uc.repo.UpdatePassword(ctx, userId, hash)
+}
+
+func (uc *userService) VerifyEmail(ctx context.Context, email string) error {
+	u, err := uc.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified {
+		return nil
+	}
+	return uc.repo.MarkEmailVerified(ctx, u.Id)
+}
+
+func (uc *userService) EmailVerified(ctx context.Context, userId int64) (bool, error) {
+	u, err := uc.repo.FindById(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return !u.NeedsEmailVerification(), nil
+}
+
 func (uc *userService) FindOrCreate(ctx context.Context, phone string) (domain.User, error) {
 	u, err := uc.repo.FindByPhone(ctx, phone)
-	// 下面确保至少不是用户没找到的error，已经注册过了、或者别的原因
+	if err == nil {
+		return uc.checkLogin(u)
+	}
+	// 下面确保至少不是用户没找到的error
 	if err != repository.ErrUserNotFound {
 		return u, err
 	}
@@ -109,8 +279,11 @@ func (uc *userService) FindOrCreate(ctx context.Context, phone string) (domain.U
 func (uc *userService) FindOrCreateByWeChat(ctx context.Context, userInfo domain.WeChatResult) (domain.User, error) {
 	// 先查用户是否存在，也就是已经注册
 	u, err := uc.repo.FindByWeChat(ctx, userInfo.OpenId)
-	// openId唯一索引、可能已经注册过了、或者别的问题
-	if err != repository.ErrUserDuplicate {
+	if err == nil {
+		return uc.checkLogin(u)
+	}
+	// 没注册以外的问题
+	if err != repository.ErrUserNotFound {
 		return u, err
 	}
 	// 到这里没注册
@@ -6,6 +6,7 @@ import (
 	"webook/internal/domain"
 	"webook/internal/repository"
 	repomocks "webook/internal/repository/mock"
+	"webook/internal/service/password"
 
 	"github.com/go-playground/assert/v2"
 	"go.uber.org/mock/gomock"
@@ -128,7 +129,7 @@ func TestLogin(t *testing.T) {
 			// 关闭
 			defer ctrl.Finish()
 			userRepo := tc.mock(ctrl);
-			userService := NewUserService(userRepo)
+			userService := NewUserService(userRepo, password.NewHasher(password.NewBcrypt(10)))
 			
 			_, err := userService.Login(tc.ctx, tc.user)
 
@@ -137,4 +138,88 @@ func TestLogin(t *testing.T) {
 			// 	assert.Equal(t, user, tc.wantUser) // 检查返回的用户是否与预期相符
 			// }
 		}
-}
\ No newline at end of file
+}
+// 老的bcrypt hash登录成功后升级成argon2id
+func TestLoginRehash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	bcryptHash := "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi"
+	hasher := password.NewHasher(password.NewArgon2id(password.Argon2idParams{Memory: 1024, Time: 1, Threads: 1}),
+		password.NewBcrypt(10))
+
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+		Return(domain.User{Id: 123, Email: "123@qq.com", Password: bcryptHash}, nil)
+	var newHash string
+	userRepo.EXPECT().UpdatePassword(gomock.Any(), int64(123), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userId int64, hash string) error {
+			newHash = hash
+			return nil
+		})
+
+	_, err := NewUserService(userRepo, hasher).Login(context.Background(), domain.User{Email: "123@qq.com", Password: "hello#world123"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, hasher.NeedsRehash(newHash), false)
+	ok, err := hasher.Verify(newHash, "hello#world123")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, ok, true)
+}
+
+// 证明账号归属时不能有副作用：不升级hash，不恢复注销中的账号
+func TestVerifyPassword(t *testing.T) {
+	bcryptHash := "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi"
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) repository.UserRepository
+		password string
+
+		wantId    int64
+		wantError error
+	}{
+		{
+			name: "密码正确",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 123, Password: bcryptHash}, nil)
+				return userRepo
+			},
+			password: "hello#world123",
+			wantId:   123,
+		},
+		{
+			name: "密码错误",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 123, Password: bcryptHash}, nil)
+				return userRepo
+			},
+			password:  "wrong",
+			wantError: ErrEmailOrPassWrong,
+		},
+		{
+			name: "注销中的账号",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 123, Password: bcryptHash, DeletedAt: 1, PurgeAt: 1 << 62}, nil)
+				return userRepo
+			},
+			password:  "hello#world123",
+			wantError: ErrUserDeleted,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			// 当前算法是argon2id，bcrypt的hash需要升级，但这里不应该升级
+			hasher := password.NewHasher(password.NewArgon2id(password.Argon2idParams{Memory: 1024, Time: 1, Threads: 1}),
+				password.NewBcrypt(10))
+			u, err := NewUserService(tc.mock(ctrl), hasher).VerifyPassword(context.Background(), "123@qq.com", tc.password)
+			assert.Equal(t, err, tc.wantError)
+			assert.Equal(t, u.Id, tc.wantId)
+		})
+	}
+}
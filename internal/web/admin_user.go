@@ -0,0 +1,195 @@
+package web
+
+import (
+	"net/http"
+	"unicode/utf8"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理后台的用户管理，每个操作都会记审计日志
+type AdminUserHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Search(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Ban(ctx *gin.Context)
+	Unban(ctx *gin.Context)
+	Logout(ctx *gin.Context)
+	ResetTwoFactor(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	AuditLogs(ctx *gin.Context)
+}
+
+type adminUserHandler struct {
+	svc  service.AdminUserService
+	rbac *middleware.RBACMiddlewareBuilder
+}
+
+func NewAdminUserHandler(svc service.AdminUserService, rbac *middleware.RBACMiddlewareBuilder) AdminUserHandler {
+	return &adminUserHandler{svc: svc, rbac: rbac}
+}
+
+func (h *adminUserHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	ug := rules.Routes(server.Group("/admin/users"), middleware.AuthRequired)
+	ug.POST("/search", h.rbac.RequirePermission(domain.PermissionUserView), h.Search).
+		POST("/detail", h.rbac.RequirePermission(domain.PermissionUserView), h.Detail).
+		POST("/ban", h.rbac.RequirePermission(domain.PermissionUserBan), h.Ban).
+		POST("/unban", h.rbac.RequirePermission(domain.PermissionUserBan), h.Unban).
+		POST("/logout", h.rbac.RequirePermission(domain.PermissionUserManage), h.Logout).
+		POST("/reset_2fa", h.rbac.RequirePermission(domain.PermissionUserManage), h.ResetTwoFactor).
+		POST("/reset_password", h.rbac.RequirePermission(domain.PermissionUserManage), h.ResetPassword)
+	rules.Routes(server.Group("/admin/audit_logs"), middleware.AuthRequired).
+		POST("", h.rbac.RequirePermission(domain.PermissionAuditView), h.AuditLogs)
+}
+
+// 从登录态里取出当前管理员
+func auditOperator(ctx *gin.Context) (domain.AuditOperator, bool) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		return domain.AuditOperator{}, false
+	}
+	return domain.AuditOperator{
+		UserId:    claims.UserId,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}, true
+}
+
+// 封禁原因的最大长度，和users表的status_reason字段一致
+const adminReasonMaxLen = 256
+
+type adminUserReq struct {
+	UserId int64 `json:"userId"`
+	// 封禁原因，会展示给用户
+	Reason string `json:"reason"`
+	// 暂停使用的截止时间，毫秒，不传表示永久封禁
+	Until int64 `json:"until"`
+}
+
+func (h *adminUserHandler) Search(ctx *gin.Context) {
+	type Req struct {
+		domain.UserFilter
+		Page     int `json:"page"`
+		PageSize int `json:"pageSize"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	op, ok := auditOperator(ctx)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	users, total, err := h.svc.Search(ctx, op, req.UserFilter, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: gin.H{"items": users, "total": total}})
+}
+
+func (h *adminUserHandler) Detail(ctx *gin.Context) {
+	var req adminUserReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	op, ok := auditOperator(ctx)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	u, err := h.svc.FindUser(ctx, op, req.UserId)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: u})
+	case service.ErrUserNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户不存在"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+func (h *adminUserHandler) Ban(ctx *gin.Context) {
+	h.act(ctx, func(op domain.AuditOperator, req adminUserReq) error {
+		return h.svc.Ban(ctx, op, req.UserId, req.Until, req.Reason)
+	})
+}
+
+func (h *adminUserHandler) Unban(ctx *gin.Context) {
+	h.act(ctx, func(op domain.AuditOperator, req adminUserReq) error {
+		return h.svc.Unban(ctx, op, req.UserId)
+	})
+}
+
+func (h *adminUserHandler) Logout(ctx *gin.Context) {
+	h.act(ctx, func(op domain.AuditOperator, req adminUserReq) error {
+		return h.svc.ForceLogout(ctx, op, req.UserId)
+	})
+}
+
+func (h *adminUserHandler) ResetTwoFactor(ctx *gin.Context) {
+	h.act(ctx, func(op domain.AuditOperator, req adminUserReq) error {
+		return h.svc.ResetTwoFactor(ctx, op, req.UserId)
+	})
+}
+
+func (h *adminUserHandler) ResetPassword(ctx *gin.Context) {
+	h.act(ctx, func(op domain.AuditOperator, req adminUserReq) error {
+		return h.svc.ResetPassword(ctx, op, req.UserId)
+	})
+}
+
+// 针对单个用户的操作
+func (h *adminUserHandler) act(ctx *gin.Context, fn func(op domain.AuditOperator, req adminUserReq) error) {
+	var req adminUserReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if utf8.RuneCountInString(req.Reason) > adminReasonMaxLen {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "原因不能超过256个字"})
+		return
+	}
+	op, ok := auditOperator(ctx)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := fn(op, req)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户不存在"})
+		return
+	case service.ErrSuspendUntilPassed:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "截止时间必须晚于现在"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
+
+func (h *adminUserHandler) AuditLogs(ctx *gin.Context) {
+	type Req struct {
+		// 为0表示所有用户
+		UserId   int64 `json:"userId"`
+		Page     int   `json:"page"`
+		PageSize int   `json:"pageSize"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	logs, total, err := h.svc.AuditLogs(ctx, req.UserId, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: gin.H{"items": logs, "total": total}})
+}
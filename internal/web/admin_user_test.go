@@ -0,0 +1,86 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/web/ijwt"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdminUserHandler_Ban(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) service.AdminUserService
+		reason string
+
+		wantResponse Result
+	}{
+		{
+			name: "封禁成功",
+			mock: func(ctrl *gomock.Controller) service.AdminUserService {
+				svc := svcmocks.NewMockAdminUserService(ctrl)
+				svc.EXPECT().Ban(gomock.Any(), gomock.Any(), int64(1), int64(0), "spam").Return(nil)
+				return svc
+			},
+			reason:       "spam",
+			wantResponse: Result{Code: 0, Msg: "ok"},
+		},
+		{
+			name: "原因刚好256个字",
+			mock: func(ctrl *gomock.Controller) service.AdminUserService {
+				svc := svcmocks.NewMockAdminUserService(ctrl)
+				svc.EXPECT().Ban(gomock.Any(), gomock.Any(), int64(1), int64(0), strings.Repeat("广", 256)).Return(nil)
+				return svc
+			},
+			reason:       strings.Repeat("广", 256),
+			wantResponse: Result{Code: 0, Msg: "ok"},
+		},
+		{
+			name: "原因太长",
+			mock: func(ctrl *gomock.Controller) service.AdminUserService {
+				return svcmocks.NewMockAdminUserService(ctrl)
+			},
+			reason:       strings.Repeat("广", 257),
+			wantResponse: Result{Code: 4, Msg: "原因不能超过256个字"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			h := &adminUserHandler{svc: tc.mock(ctrl)}
+
+			server := gin.Default()
+			// 模拟登录中间件，权限校验在路由上，这里直接测handler
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("Claims", &ijwt.UserJwtClaims{UserId: 100})
+			})
+			server.POST("/admin/users/ban", h.Ban)
+			body := bytes.NewBufferString(fmt.Sprintf(`{"userId":1,"reason":%q}`, tc.reason))
+			req, err := http.NewRequest(http.MethodPost, "/admin/users/ban", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, req)
+
+			var respBody Result
+			if err = json.NewDecoder(response.Body).Decode(&respBody); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			assert.Equal(t, respBody, tc.wantResponse)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/service/storage"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体的硬上限，具体的文件大小限制在service里校验
+const avatarMaxBody = 10 << 20
+
+// 上传头像
+type AvatarHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Upload(ctx *gin.Context)
+}
+
+type avatarHandler struct {
+	svc     service.AvatarService
+	storage storage.Service
+	// 没验证邮箱的账号不能改资料
+	verified *middleware.EmailVerifiedMiddlewareBuilder
+}
+
+func NewAvatarHandler(svc service.AvatarService, storage storage.Service,
+	verified *middleware.EmailVerifiedMiddlewareBuilder) AvatarHandler {
+	return &avatarHandler{svc: svc, storage: storage, verified: verified}
+}
+
+func (a *avatarHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	// 和修改资料一样，个人访问令牌有profile:write就能传
+	rules.Routes(server.Group("/user"), middleware.AuthRequired).Scope(domain.ScopeProfileWrite).
+		POST("/avatar", a.verified.Build(), a.Upload)
+	// 本地存储的文件由这里提供，对象存储的走自己的域名
+	if h, ok := a.storage.(http.Handler); ok {
+		rules.Routes(server.Group("/static"), middleware.AuthPublic).
+			GET("/*key", gin.WrapH(http.StripPrefix("/static", h)))
+	}
+}
+
+// multipart上传，字段名file，返回各个尺寸的地址
+func (a *avatarHandler) Upload(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, avatarMaxBody)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请选择图片，大小不能超过10MB"})
+		return
+	}
+	file, err := fh.Open()
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	urls, err := a.svc.Upload(ctx, claims.UserId, data)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: urls})
+	case service.ErrAvatarTooLarge:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "图片太大"})
+	case service.ErrAvatarType:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "只支持jpg、png、gif格式的图片"})
+	case service.ErrAvatarInvalid:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "图片已损坏"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
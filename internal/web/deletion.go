@@ -0,0 +1,72 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 用户自助注销
+type AccountDeletionHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Delete(ctx *gin.Context)
+}
+
+type accountDeletionHandler struct {
+	svc         service.AccountDeletionService
+	handler     ijwt.Handler
+	securitySvc service.SecurityEventService
+}
+
+func NewAccountDeletionHandler(svc service.AccountDeletionService, handler ijwt.Handler,
+	securitySvc service.SecurityEventService) AccountDeletionHandler {
+	return &accountDeletionHandler{svc: svc, handler: handler, securitySvc: securitySvc}
+}
+
+func (d *accountDeletionHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	// 个人访问令牌不能注销账号
+	rules.Routes(server.Group("/user"), middleware.AuthRequired).
+		POST("/delete", d.Delete)
+}
+
+// 申请注销，所有设备立刻退出，恢复期内重新登录可以恢复账号
+func (d *accountDeletionHandler) Delete(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	purgeAt, err := d.svc.RequestDeletion(ctx, claims.UserId)
+	if err == service.ErrUserNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已经在注销中"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	er := d.securitySvc.Record(ctx, domain.SecurityEvent{
+		UserId:    claims.UserId,
+		Type:      domain.SecurityEventAccountDelete,
+		Ssid:      claims.Ssid,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if er != nil {
+		log.Println("记录安全事件失败", er)
+	}
+	if err = d.handler.RevokeAllSessions(ctx, claims.UserId); err != nil {
+		log.Println("注销账号后退出所有会话失败", err)
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "已申请注销，但退出登录失败，请手动退出"})
+		return
+	}
+	// 当前设备的token也作废了，让前端清掉
+	ctx.Header("x-ijwt-token", "")
+	ctx.Header("x-ijwt-refresh-token", "")
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "已申请注销，恢复期内登录可以恢复账号", Data: purgeAt})
+}
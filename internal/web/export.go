@@ -0,0 +1,119 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 个人数据导出
+type DataExportHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Request(ctx *gin.Context)
+	Status(ctx *gin.Context)
+	Download(ctx *gin.Context)
+}
+
+type dataExportHandler struct {
+	svc service.DataExportService
+}
+
+func NewDataExportHandler(svc service.DataExportService) DataExportHandler {
+	return &dataExportHandler{svc: svc}
+}
+
+func (e *dataExportHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	eg := server.Group("/user/export")
+	rules.Routes(eg, middleware.AuthRequired).
+		POST("", e.Request).
+		POST("/status", e.Status)
+	// 下载地址自带签名，浏览器直接打开，不需要token
+	rules.Routes(eg, middleware.AuthPublic).
+		GET("/download", e.Download)
+}
+
+type dataExportVo struct {
+	domain.DataExport
+	// 生成好之后才有
+	Url string `json:"url,omitempty"`
+}
+
+// 申请导出，归档在后台生成，之后轮询状态
+func (e *dataExportHandler) Request(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	export, err := e.svc.Request(ctx, claims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	e.respond(ctx, export)
+}
+
+func (e *dataExportHandler) Status(ctx *gin.Context) {
+	type statusReq struct {
+		Id int64 `json:"id"`
+	}
+	var req statusReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	export, err := e.svc.Find(ctx, claims.UserId, req.Id)
+	if err == service.ErrDataExportNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "导出不存在"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	e.respond(ctx, export)
+}
+
+func (e *dataExportHandler) respond(ctx *gin.Context, export domain.DataExport) {
+	vo := dataExportVo{DataExport: export}
+	if export.Status == domain.DataExportReady {
+		url, err := e.svc.DownloadURL(export)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+			return
+		}
+		vo.Url = url
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: vo})
+}
+
+// 校验签名后返回zip文件
+func (e *dataExportHandler) Download(ctx *gin.Context) {
+	id, err1 := strconv.ParseInt(ctx.Query("id"), 10, 64)
+	expires, err2 := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err1 != nil || err2 != nil {
+		ctx.String(http.StatusNotFound, "下载链接无效或已过期")
+		return
+	}
+	export, err := e.svc.Open(ctx, id, expires, ctx.Query("sig"))
+	if err == service.ErrDownloadLinkWrong {
+		ctx.String(http.StatusNotFound, "下载链接无效或已过期")
+		return
+	}
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "系统错误")
+		return
+	}
+	ctx.FileAttachment(export.File, fmt.Sprintf("webook-export-%d.zip", export.Id))
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 关注、取消关注、粉丝和关注列表
+type FollowHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+	Followers(ctx *gin.Context)
+	Followees(ctx *gin.Context)
+	Statistic(ctx *gin.Context)
+}
+
+type followHandler struct {
+	svc service.FollowService
+}
+
+func NewFollowHandler(svc service.FollowService) FollowHandler {
+	return &followHandler{svc: svc}
+}
+
+func (f *followHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	group := server.Group("/user")
+	// 个人访问令牌不能关注别人
+	required := rules.Routes(group, middleware.AuthRequired)
+	required.POST("/follow", f.Follow)
+	required.POST("/unfollow", f.Unfollow)
+	read := required.Scope(domain.ScopeProfileRead)
+	read.POST("/followers", f.Followers)
+	read.POST("/followees", f.Followees)
+	read.POST("/follow/statistic", f.Statistic)
+}
+
+type followReq struct {
+	Followee int64 `json:"followee"`
+}
+
+func (f *followHandler) Follow(ctx *gin.Context) {
+	var req followReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := f.svc.Follow(ctx, claims.UserId, req.Followee)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+	case service.ErrFollowSelf:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "不能关注自己"})
+	case service.ErrUserNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户不存在"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+func (f *followHandler) Unfollow(ctx *gin.Context) {
+	var req followReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err := f.svc.Unfollow(ctx, claims.UserId, req.Followee); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
+
+// 列表请求，UserId为0表示当前用户
+type followListReq struct {
+	UserId int64 `json:"userId"`
+	Cursor int64 `json:"cursor"`
+	Limit  int   `json:"limit"`
+}
+
+type followListResp struct {
+	Relations []domain.FollowRelation `json:"relations"`
+	// 为0表示没有下一页
+	Cursor int64 `json:"cursor"`
+}
+
+func (f *followHandler) Followers(ctx *gin.Context) {
+	f.list(ctx, f.svc.Followers)
+}
+
+func (f *followHandler) Followees(ctx *gin.Context) {
+	f.list(ctx, f.svc.Followees)
+}
+
+func (f *followHandler) list(ctx *gin.Context,
+	find func(ctx context.Context, userId int64, cursor int64, limit int) ([]domain.FollowRelation, int64, error)) {
+	var req followListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if req.UserId == 0 {
+		req.UserId = claims.UserId
+	}
+	res, cursor, err := find(ctx, req.UserId, req.Cursor, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: followListResp{Relations: res, Cursor: cursor}})
+}
+
+// 粉丝数、关注数，看别人的时候顺便返回是否已经关注
+func (f *followHandler) Statistic(ctx *gin.Context) {
+	type Resp struct {
+		domain.FollowStatistic
+		Following bool `json:"following"`
+	}
+	var req followListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if req.UserId == 0 {
+		req.UserId = claims.UserId
+	}
+	s, err := f.svc.Statistic(ctx, req.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	resp := Resp{FollowStatistic: s}
+	if req.UserId != claims.UserId {
+		resp.Following, err = f.svc.Following(ctx, claims.UserId, req.UserId)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: resp})
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"net/http"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 短信验证码绑定手机号
+const bindPhoneBiz = "bind_phone"
+
+// 账号绑定的登录方式，微信的绑定走OAuth2WeChatHandler
+type IdentityHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	List(ctx *gin.Context)
+	SendBindPhoneCode(ctx *gin.Context)
+	BindPhone(ctx *gin.Context)
+	Unbind(ctx *gin.Context)
+}
+
+type identityHandler struct {
+	svc     service.IdentityService
+	codeSvc service.CodeService
+}
+
+func NewIdentityHandler(svc service.IdentityService, codeSvc service.CodeService) IdentityHandler {
+	return &identityHandler{svc: svc, codeSvc: codeSvc}
+}
+
+func (i *identityHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	ig := server.Group("/user/identities")
+	rules.Routes(ig, middleware.AuthRequired).
+		POST("", i.List).
+		POST("/phone/code/send", i.SendBindPhoneCode).
+		POST("/phone/bind", i.BindPhone).
+		POST("/unbind", i.Unbind)
+}
+
+// 我绑定的所有登录方式
+func (i *identityHandler) List(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	identities, err := i.svc.List(ctx, claims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: identities})
+}
+
+// 给要绑定的手机号发验证码
+func (i *identityHandler) SendBindPhoneCode(ctx *gin.Context) {
+	type sendReq struct {
+		Phone string `json:"phone"`
+	}
+	var req sendReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if req.Phone == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请输入手机号"})
+		return
+	}
+	err := i.codeSvc.Send(ctx, bindPhoneBiz, req.Phone)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+	case service.ErrCodeSendTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送过于频繁"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 校验验证码，证明手机号是自己的，再绑定
+func (i *identityHandler) BindPhone(ctx *gin.Context) {
+	type bindReq struct {
+		Phone string `json:"phone"`
+		Code  string `json:"code"`
+	}
+	var req bindReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ok, err := i.codeSvc.Verify(ctx, bindPhoneBiz, req.Phone, req.Code)
+	if err == service.ErrCodeVerifyTooMany {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	}
+	err = i.svc.BindPhone(ctx, claims.UserId, req.Phone)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "绑定成功"})
+	case service.ErrIdentityTaken:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "手机号已经绑定了其他账号"})
+	case service.ErrIdentityExists:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "已经绑定了手机号，请先解绑"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 解绑一种登录方式，不能解绑最后一种能登录的方式
+func (i *identityHandler) Unbind(ctx *gin.Context) {
+	type unbindReq struct {
+		Id int64 `json:"id"`
+	}
+	var req unbindReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := i.svc.Unbind(ctx, claims.UserId, req.Id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "解绑成功"})
+	case service.ErrIdentityNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录方式不存在"})
+	case service.ErrLastIdentity:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这是最后一种登录方式，不能解绑"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
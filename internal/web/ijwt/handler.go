@@ -1,131 +1,237 @@
 package ijwt
 
 import (
+	"context"
+	_ "embed"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"strings"
 	"time"
 )
 
-var AccessTokenKey = []byte("95osj3fUD7fo0mlYdDbncXz4VD2igvf0")
-var RefreshTokenKey = []byte("moyn8y9abnd7q4zkq2m73yw8tu9j5ixA")
-
 var ErrUserLogout = errors.New("用户已经退出登录")
+var ErrInvalidToken = errors.New("token不合法")
+var ErrRefreshTokenReused = errors.New("refresh token被重复使用")
+var ErrSessionExpired = errors.New("会话超过最长有效期，需要重新登录")
+var ErrSessionCheckUnavailable = errors.New("redis不可用，且本地退出登录数据已过期")
+
+//go:embed lua/rotate_refresh.lua
+var rotateRefreshScript string
+
+// 长短token用同一套密钥签名，用TokenType区分，防止拿短token去刷新
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+	// 个人访问令牌校验通过后，中间件构造的claims用这个类型
+	PersonalTokenType = "personal"
+)
 
 // 内聚所有Jwt token相关的方法
 type Handler interface {
 	ClearToken(ctx *gin.Context) error
 	SetLoginToken(ctx *gin.Context, userId int64) error
-	SetAccessToken(ctx *gin.Context, userId int64, ssid string) error
+	SetAccessToken(ctx *gin.Context, claims UserJwtClaims) error
 	SetRefreshToken(ctx *gin.Context, userId int64, ssid string) error
+	// 用refresh token换一对新的长短token，旧的refresh token作废
+	RotateRefreshToken(ctx *gin.Context, claims *UserRefreshJwtClaims) error
 	CheckSession(ctx *gin.Context, ssid string) error
 	ExtractTokenString(ctx *gin.Context) string
+	ParseAccessToken(tokenStr string) (*UserJwtClaims, error)
+	ParseRefreshToken(tokenStr string) (*UserRefreshJwtClaims, error)
+
+	// 会话管理，一个会话对应一次登录（一个token family）
+	TouchSession(ctx context.Context, claims *UserJwtClaims) error
+	ListSessions(ctx context.Context, userId int64) ([]Session, error)
+	RevokeSession(ctx context.Context, userId int64, sessionId string) error
+	// 退出除了当前会话以外的所有会话
+	RevokeOtherSessions(ctx context.Context, userId int64, currentSessionId string) error
+	// 退出所有会话，比如重置密码之后
+	RevokeAllSessions(ctx context.Context, userId int64) error
+}
+
+// 签发token时查询用户的角色，放到claims里给其他服务用
+// 鉴权以实时查询的权限为准，claims里的角色只是快照
+type RoleProvider interface {
+	Roles(ctx context.Context, userId int64) ([]string, error)
 }
 
 type RedisHandler struct {
+	// 签发、解析token的部分和存储无关
+	*tokenIssuer
 	client redis.Cmdable
-	// 长token过期时间
-	rtExpiration time.Duration
+	// redis挂了之后的降级校验
+	revoked RevocationCache
 }
 
 func (r *RedisHandler) ClearToken(ctx *gin.Context) error {
 	//	所有的请求都必须有这讲个token
-	ctx.Header("x-jwt-token", "")
-	ctx.Header("x-jwt-refresh-token", "")
+	ctx.Header("x-ijwt-token", "")
+	ctx.Header("x-ijwt-refresh-token", "")
 	// 这里不可能拿不到
 	claims := ctx.MustGet("Claims").(*UserJwtClaims)
-	return r.client.Set(ctx, r.key(claims.Ssid), claims.Ssid, r.rtExpiration).Err()
+	if err := r.logout(ctx, claims.Ssid); err != nil {
+		return err
+	}
+	// 老版本签发的token没有family
+	if claims.Family == "" {
+		return nil
+	}
+	return r.RevokeSession(ctx, claims.UserId, claims.Family)
 }
 
 // 设置长短token
 func (r *RedisHandler) SetLoginToken(ctx *gin.Context, userId int64) error {
+	// 登录时的ssid同时作为family和会话的id
 	ssid := uuid.New().String()
 	// 先设置短token
-	if err := r.SetAccessToken(ctx, userId, ssid); err != nil {
+	err := r.SetAccessToken(ctx, UserJwtClaims{
+		UserId:  userId,
+		Ssid:    ssid,
+		Family:  ssid,
+		LoginAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
 		return err
 	}
 	// 然后设置长token
-	return r.SetRefreshToken(ctx, userId, ssid)
+	if err = r.SetRefreshToken(ctx, userId, ssid); err != nil {
+		return err
+	}
+	return r.addSession(ctx, userId, ssid)
 }
 
-func (r *RedisHandler) SetAccessToken(ctx *gin.Context, userId int64, ssid string) error {
-	claims := UserJwtClaims{
-		UserId:    userId,
-		Ssid:      ssid,
-		UserAgent: ctx.Request.UserAgent(),
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 7)),
-		},
+// 颁发一个新的refresh token，同时开启一个token family，family的id就是登录时的ssid
+func (r *RedisHandler) SetRefreshToken(ctx *gin.Context, userId int64, ssid string) error {
+	family, jti := ssid, uuid.New().String()
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, r.familyKey(family), "current", jti, "uid", userId)
+	pipe.SAdd(ctx, r.familySsidsKey(family), ssid)
+	pipe.Expire(ctx, r.familyKey(family), r.rtExpiration)
+	pipe.Expire(ctx, r.familySsidsKey(family), r.rtExpiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	return r.setRefreshToken(ctx, userId, ssid, family, jti, time.Now().UnixMilli())
+}
 
-	// 签发 xxx.xx.xx的字符串
-	tokenStr, err := token.SignedString(AccessTokenKey)
+// 每次刷新都换一个新的ssid和jti，family不变
+// 如果提交的是已经被换掉的refresh token，说明token泄漏了，吊销family下的所有ssid
+func (r *RedisHandler) RotateRefreshToken(ctx *gin.Context, claims *UserRefreshJwtClaims) error {
+	family := claims.Family
+	if family == "" {
+		return ErrInvalidToken
+	}
+	// 超过会话最长寿命，必须重新登录
+	if _, err := r.expiresAt(claims.LoginAt, r.rtExpiration); err != nil {
+		return err
+	}
+	ssid, jti := uuid.New().String(), uuid.New().String()
+	result, err := r.client.Eval(ctx, rotateRefreshScript,
+		[]string{r.familyKey(family), r.familySsidsKey(family)},
+		claims.ID, jti, ssid, int64(r.rtExpiration.Seconds())).Int()
 	if err != nil {
 		return err
 	}
-	ctx.Header("x-ijwt-token", tokenStr)
-	return nil
+	switch result {
+	case 0:
+	case -1:
+		if err = r.revokeFamily(ctx, family); err != nil {
+			return err
+		}
+		if err = r.removeSessions(ctx, claims.UserId, family); err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	case -2, -3:
+		return ErrUserLogout
+	default:
+		return fmt.Errorf("未知的刷新结果 %d", result)
+	}
+
+	err = r.SetAccessToken(ctx, UserJwtClaims{
+		UserId:  claims.UserId,
+		Ssid:    ssid,
+		Family:  family,
+		LoginAt: claims.LoginAt,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.setRefreshToken(ctx, claims.UserId, ssid, family, jti, claims.LoginAt); err != nil {
+		return err
+	}
+	return r.touchSession(ctx, claims.UserId, family)
 }
 
-func (r *RedisHandler) SetRefreshToken(ctx *gin.Context, userId int64, ssid string) error {
-	// 再颁发一个refresh-token
-	refreshClaims := UserRefreshJwtClaims{
-		UserId: userId,
-		Ssid:   ssid,
-		RegisteredClaims: jwt.RegisteredClaims{
-			// 7天有效期
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7)),
-		},
-	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS512, refreshClaims)
-
-	// 签发 xxx.xx.xx的字符串
-	refreshTokenStr, err := refreshToken.SignedString(RefreshTokenKey)
+// 把family下所有的ssid都标记成退出登录，family也不能再刷新
+func (r *RedisHandler) revokeFamily(ctx context.Context, family string) error {
+	ssids, err := r.client.SMembers(ctx, r.familySsidsKey(family)).Result()
 	if err != nil {
 		return err
 	}
-	ctx.Header("x-ijwt-refresh-token", refreshTokenStr)
-	return nil
+	pipe := r.client.Pipeline()
+	pipe.HSet(ctx, r.familyKey(family), "revoked", "1")
+	for _, ssid := range ssids {
+		r.revoked.Add(ssid)
+		pipe.Set(ctx, r.key(ssid), ssid, r.rtExpiration)
+		pipe.Publish(ctx, revokedChannel, ssid)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
-// redis挂了校验？
+// 标记ssid退出登录，并通知其他实例
+func (r *RedisHandler) logout(ctx context.Context, ssid string) error {
+	// 先记到本地，redis挂了至少当前实例能拦住
+	r.revoked.Add(ssid)
+	pipe := r.client.Pipeline()
+	pipe.Set(ctx, r.key(ssid), ssid, r.rtExpiration)
+	pipe.Publish(ctx, revokedChannel, ssid)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 优先看本地缓存，再查redis
+// redis挂了就降级到本地缓存，本地数据超过允许的延迟则拒绝
 func (r *RedisHandler) CheckSession(ctx *gin.Context, ssid string) error {
+	if r.revoked.Contains(ssid) {
+		return ErrUserLogout
+	}
 	logout, err := r.client.Exists(ctx, r.key(ssid)).Result()
 	if err != nil {
-		return err
+		if r.revoked.AllowDegraded() {
+			return nil
+		}
+		return fmt.Errorf("%w: %w", ErrSessionCheckUnavailable, err)
 	}
 	if logout > 0 {
+		r.revoked.Add(ssid)
 		return ErrUserLogout
 	}
 	return nil
 }
 
-func (r *RedisHandler) ExtractTokenString(ctx *gin.Context) string {
-	// 判断header
-	tokenHeader := ctx.Request.Header.Get("Authorization")
-	if tokenHeader == "" {
-		//	没带token
-		return ""
-	}
-	// 通常是 Bearer xxx
-	segs := strings.Split(tokenHeader, " ")
-	if len(segs) != 2 {
-		return ""
-	}
-	return segs[1]
-}
-
 func (r *RedisHandler) key(ssid string) string {
 	return fmt.Sprintf("users:ssid:%s", ssid)
 }
 
-func NewRedisHandler(client redis.Cmdable) Handler {
-	return &RedisHandler{client: client, rtExpiration: time.Hour * 24 * 7}
+func (r *RedisHandler) familyKey(family string) string {
+	return fmt.Sprintf("users:rt_family:%s", family)
+}
+
+func (r *RedisHandler) familySsidsKey(family string) string {
+	return fmt.Sprintf("users:rt_family:%s:ssids", family)
+}
+
+func NewRedisHandler(client redis.Cmdable, keys KeyManager, revoked RevocationCache, roles RoleProvider, opts Options) Handler {
+	return &RedisHandler{
+		tokenIssuer: newTokenIssuer(keys, roles, opts),
+		client:      client,
+		revoked:     revoked,
+	}
 }
 
 // 自定义jwt-claims
@@ -134,10 +240,20 @@ type UserJwtClaims struct {
 	jwt.RegisteredClaims
 	// 用于标识是否过期
 	Ssid string
+	// 所属的token family，也是会话的id
+	Family string
+	// 登录时间，毫秒时间戳，续期不能超过会话最长寿命
+	LoginAt int64
 	// 自己定义的数据
 	UserId int64
 	// 浏览器信息
 	UserAgent string
+	// 签发时用户拥有的角色
+	Roles []string
+	// 长短token的标识
+	TokenType string
+	// 个人访问令牌的授权范围，jwt不设置
+	Scopes []string `json:",omitempty"`
 }
 
 // refresh-token
@@ -148,4 +264,10 @@ type UserRefreshJwtClaims struct {
 	Ssid string
 	// 自己定义的数据
 	UserId int64
+	// 所属的token family，登录时的ssid
+	Family string
+	// 登录时间，毫秒时间戳
+	LoginAt int64
+	// 长短token的标识
+	TokenType string
 }
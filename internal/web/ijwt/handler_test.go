@@ -0,0 +1,42 @@
+package ijwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisHandler_expiresAt(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name    string
+		loginAt time.Time
+		ttl     time.Duration
+
+		// 期望的过期时间距离现在多久，允许一秒误差
+		wantIn  time.Duration
+		wantErr error
+	}{
+		{name: "没有登录时间不做限制", ttl: time.Hour, wantIn: time.Hour},
+		{name: "未触及最长寿命", loginAt: now.Add(-time.Hour), ttl: time.Hour, wantIn: time.Hour},
+		{name: "过期时间被截断到最长寿命", loginAt: now.Add(-time.Hour*24 + time.Minute*10), ttl: time.Hour, wantIn: time.Minute * 10},
+		{name: "超过最长寿命", loginAt: now.Add(-time.Hour * 25), ttl: time.Hour, wantErr: ErrSessionExpired},
+	}
+
+	r := NewRedisHandler(nil, nil, nil, nil, Options{MaxSessionLifetime: time.Hour * 24}).(*RedisHandler)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var loginAt int64
+			if !tc.loginAt.IsZero() {
+				loginAt = tc.loginAt.UnixMilli()
+			}
+			expiresAt, err := r.expiresAt(loginAt, tc.ttl)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.WithinDuration(t, now.Add(tc.wantIn), expiresAt, time.Second)
+		})
+	}
+}
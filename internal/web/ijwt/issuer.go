@@ -0,0 +1,167 @@
+package ijwt
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"strings"
+	"time"
+)
+
+// 签发短token时把ssid放进gin.Context，登录记录要用
+const issuedSsidKey = "IssuedSsid"
+
+// token有效期相关的配置，不填使用默认值
+type Options struct {
+	// 短token有效期，默认7小时
+	AccessExpiration time.Duration
+	// 长token有效期，默认7天
+	RefreshExpiration time.Duration
+	// 从登录开始算，会话最长能续多久，0表示不限制
+	MaxSessionLifetime time.Duration
+}
+
+// 签发和解析token，RedisHandler和MemoryHandler共用，会话状态由各自的存储负责
+type tokenIssuer struct {
+	// 签名和校验token的密钥
+	keys KeyManager
+	// 短token过期时间
+	atExpiration time.Duration
+	// 长token过期时间
+	rtExpiration time.Duration
+	// 从登录开始算，会话最长能续多久
+	maxLifetime time.Duration
+	roles       RoleProvider
+}
+
+func newTokenIssuer(keys KeyManager, roles RoleProvider, opts Options) *tokenIssuer {
+	if opts.AccessExpiration <= 0 {
+		opts.AccessExpiration = time.Hour * 7
+	}
+	if opts.RefreshExpiration <= 0 {
+		opts.RefreshExpiration = time.Hour * 24 * 7
+	}
+	return &tokenIssuer{
+		keys:         keys,
+		atExpiration: opts.AccessExpiration,
+		rtExpiration: opts.RefreshExpiration,
+		maxLifetime:  opts.MaxSessionLifetime,
+		roles:        roles,
+	}
+}
+
+// 签发短token，UserId、Ssid、Family、LoginAt沿用传入的claims，过期时间重新计算
+// 续期的时候传入当前的claims即可，不会超过会话的最长寿命
+func (i *tokenIssuer) SetAccessToken(ctx *gin.Context, claims UserJwtClaims) error {
+	expiresAt, err := i.expiresAt(claims.LoginAt, i.atExpiration)
+	if err != nil {
+		return err
+	}
+	// 每次签发都重新查询角色，续期后claims里的角色也是新的
+	claims.Roles, err = i.roles.Roles(ctx, claims.UserId)
+	if err != nil {
+		return err
+	}
+	claims.UserAgent = ctx.Request.UserAgent()
+	claims.TokenType = AccessTokenType
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	// 签发 xxx.xx.xx的字符串
+	tokenStr, err := i.keys.Sign(claims)
+	if err != nil {
+		return err
+	}
+	ctx.Header("x-ijwt-token", tokenStr)
+	ctx.Set(issuedSsidKey, claims.Ssid)
+	return nil
+}
+
+// 这次请求签发的token属于哪个会话，没有签发时为空
+func IssuedSsid(ctx *gin.Context) string {
+	return ctx.GetString(issuedSsidKey)
+}
+
+func (i *tokenIssuer) setRefreshToken(ctx *gin.Context, userId int64, ssid string, family string, jti string, loginAt int64) error {
+	expiresAt, err := i.expiresAt(loginAt, i.rtExpiration)
+	if err != nil {
+		return err
+	}
+	refreshClaims := UserRefreshJwtClaims{
+		UserId:    userId,
+		Ssid:      ssid,
+		Family:    family,
+		LoginAt:   loginAt,
+		TokenType: RefreshTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	// 签发 xxx.xx.xx的字符串
+	refreshTokenStr, err := i.keys.Sign(refreshClaims)
+	if err != nil {
+		return err
+	}
+	ctx.Header("x-ijwt-refresh-token", refreshTokenStr)
+	return nil
+}
+
+// token的过期时间不能超过登录时间+会话最长寿命
+func (i *tokenIssuer) expiresAt(loginAt int64, ttl time.Duration) (time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	// 老版本的token没有登录时间，不做限制
+	if loginAt == 0 || i.maxLifetime <= 0 {
+		return expiresAt, nil
+	}
+	deadline := time.UnixMilli(loginAt).Add(i.maxLifetime)
+	if !time.Now().Before(deadline) {
+		return time.Time{}, ErrSessionExpired
+	}
+	if expiresAt.After(deadline) {
+		expiresAt = deadline
+	}
+	return expiresAt, nil
+}
+
+func (i *tokenIssuer) ExtractTokenString(ctx *gin.Context) string {
+	// 判断header
+	tokenHeader := ctx.Request.Header.Get("Authorization")
+	if tokenHeader == "" {
+		//	没带token
+		return ""
+	}
+	// 通常是 Bearer xxx
+	segs := strings.Split(tokenHeader, " ")
+	if len(segs) != 2 {
+		return ""
+	}
+	return segs[1]
+}
+
+func (i *tokenIssuer) ParseAccessToken(tokenStr string) (*UserJwtClaims, error) {
+	claims := &UserJwtClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, i.keys.Keyfunc, jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}))
+	if err != nil {
+		return nil, err
+	}
+	// 判断jwt payload，此处认为用户的主键不会为0
+	if token == nil || !token.Valid || claims.TokenType != AccessTokenType || claims.UserId == 0 {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (i *tokenIssuer) ParseRefreshToken(tokenStr string) (*UserRefreshJwtClaims, error) {
+	claims := &UserRefreshJwtClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, i.keys.Keyfunc, jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || !token.Valid || claims.TokenType != RefreshTokenType || claims.UserId == 0 {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
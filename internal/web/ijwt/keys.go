@@ -0,0 +1,236 @@
+package ijwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// 支持的非对称签名算法
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("不支持的jwt签名算法")
+	ErrKeyNotFound    = errors.New("找不到token对应的校验密钥")
+	ErrNoSigningKey   = errors.New("没有可用的签名密钥")
+)
+
+// 管理签名密钥：当前签名用的主密钥 + 若干个仍然用于校验的旧密钥
+// 轮换时旧密钥不会立即删除，所以已经签发的token还能继续用，不会把所有人踢下线
+type KeyManager interface {
+	// 用主密钥签名，header里带上kid
+	Sign(claims jwt.Claims) (string, error)
+	// 根据header里的kid找到公钥，可以直接作为jwt.Keyfunc使用
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	// 加入一个新的主密钥，超过上限的旧密钥被淘汰
+	AddKey(kid string, key crypto.Signer) error
+	// 生成一个新的主密钥
+	Rotate() error
+	// 对外公开的公钥集合，给其他服务校验webook的token
+	JWKS() JWKS
+}
+
+// 一把签名密钥
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	createdAt time.Time
+}
+
+type keyManager struct {
+	lock sync.RWMutex
+	alg  string
+	// 第一个是主密钥，后面是用于校验的旧密钥
+	keys []signingKey
+	// 最多保留多少把校验密钥
+	maxKeys int
+}
+
+// alg为RS256或者EdDSA，maxKeys为保留的校验密钥数量（包含主密钥）
+func NewKeyManager(alg string, maxKeys int) (KeyManager, error) {
+	if _, err := methodOf(alg); err != nil {
+		return nil, err
+	}
+	if maxKeys < 1 {
+		maxKeys = 1
+	}
+	return &keyManager{alg: alg, maxKeys: maxKeys}, nil
+}
+
+func (k *keyManager) Sign(claims jwt.Claims) (string, error) {
+	k.lock.RLock()
+	if len(k.keys) == 0 {
+		k.lock.RUnlock()
+		return "", ErrNoSigningKey
+	}
+	current := k.keys[0]
+	k.lock.RUnlock()
+
+	token := jwt.NewWithClaims(current.method, claims)
+	token.Header["kid"] = current.kid
+	return token.SignedString(current.private)
+}
+
+func (k *keyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrKeyNotFound
+	}
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	for _, key := range k.keys {
+		if key.kid != kid {
+			continue
+		}
+		// 防止alg被篡改，比如改成none或者HS256
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("%w, alg不匹配: %s", ErrKeyNotFound, token.Method.Alg())
+		}
+		return key.private.Public(), nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (k *keyManager) AddKey(kid string, key crypto.Signer) error {
+	method, err := methodOf(k.alg)
+	if err != nil {
+		return err
+	}
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		if k.alg != AlgRS256 {
+			return fmt.Errorf("%w, 密钥类型与%s不匹配", ErrUnsupportedAlg, k.alg)
+		}
+	case ed25519.PrivateKey:
+		if k.alg != AlgEdDSA {
+			return fmt.Errorf("%w, 密钥类型与%s不匹配", ErrUnsupportedAlg, k.alg)
+		}
+	default:
+		return ErrUnsupportedAlg
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	keys := make([]signingKey, 0, len(k.keys)+1)
+	keys = append(keys, signingKey{kid: kid, method: method, private: key, createdAt: time.Now()})
+	for _, old := range k.keys {
+		if old.kid != kid {
+			keys = append(keys, old)
+		}
+	}
+	if len(keys) > k.maxKeys {
+		keys = keys[:k.maxKeys]
+	}
+	k.keys = keys
+	return nil
+}
+
+func (k *keyManager) Rotate() error {
+	key, err := GenerateKey(k.alg)
+	if err != nil {
+		return err
+	}
+	return k.AddKey(uuid.New().String(), key)
+}
+
+func (k *keyManager) JWKS() JWKS {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	set := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		set.Keys = append(set.Keys, toJWK(key))
+	}
+	return set
+}
+
+// 生成一把对应算法的私钥
+func GenerateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	}
+	return nil, ErrUnsupportedAlg
+}
+
+// 解析PKCS8格式的PEM私钥，RSA也兼容PKCS1
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("私钥不是PEM格式")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedAlg
+	}
+	return signer, nil
+}
+
+func methodOf(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, ErrUnsupportedAlg
+}
+
+// RFC 7517 中的JWK Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// 只放公钥相关的字段
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA公钥
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519公钥
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func toJWK(key signingKey) JWK {
+	jwk := JWK{Kid: key.kid, Alg: key.method.Alg(), Use: "sig"}
+	switch pub := key.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
@@ -0,0 +1,61 @@
+package ijwt
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyManager_Rotate(t *testing.T) {
+	testCases := []struct {
+		name string
+		alg  string
+		// 签发后再轮换几次
+		rotations int
+		maxKeys   int
+
+		wantValid bool
+	}{
+		{name: "EdDSA未轮换", alg: AlgEdDSA, rotations: 0, maxKeys: 2, wantValid: true},
+		{name: "EdDSA轮换后旧token仍然有效", alg: AlgEdDSA, rotations: 1, maxKeys: 2, wantValid: true},
+		{name: "EdDSA旧密钥被淘汰", alg: AlgEdDSA, rotations: 2, maxKeys: 2, wantValid: false},
+		{name: "RS256轮换后旧token仍然有效", alg: AlgRS256, rotations: 1, maxKeys: 2, wantValid: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := NewKeyManager(tc.alg, tc.maxKeys)
+			require.NoError(t, err)
+			require.NoError(t, keys.Rotate())
+
+			tokenStr, err := keys.Sign(UserJwtClaims{UserId: 1, TokenType: AccessTokenType})
+			require.NoError(t, err)
+			for i := 0; i < tc.rotations; i++ {
+				require.NoError(t, keys.Rotate())
+			}
+
+			claims := &UserJwtClaims{}
+			_, err = jwt.ParseWithClaims(tokenStr, claims, keys.Keyfunc)
+			assert.Equal(t, tc.wantValid, err == nil)
+			assert.Len(t, keys.JWKS().Keys, min(tc.rotations+1, tc.maxKeys))
+		})
+	}
+}
+
+func TestKeyManager_RejectAlgConfusion(t *testing.T) {
+	keys, err := NewKeyManager(AlgEdDSA, 1)
+	require.NoError(t, err)
+	require.NoError(t, keys.Rotate())
+	kid := keys.JWKS().Keys[0].Kid
+
+	// 用HS256伪造一个带合法kid的token
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, UserJwtClaims{UserId: 1, TokenType: AccessTokenType})
+	token.Header["kid"] = kid
+	tokenStr, err := token.SignedString([]byte("guess"))
+	require.NoError(t, err)
+
+	_, err = jwt.ParseWithClaims(tokenStr, &UserJwtClaims{}, keys.Keyfunc)
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+-- token family的key：记录当前有效的refresh token的jti
+-- users:rt_family:$family
+local familyKey = KEYS[1]
+-- family下派生出的所有ssid
+local ssidsKey = KEYS[2]
+-- 本次提交的refresh token的jti
+local presented = ARGV[1]
+-- 新签发的refresh token的jti
+local nextJti = ARGV[2]
+-- 新签发的ssid
+local nextSsid = ARGV[3]
+-- family的过期时间，单位秒
+local expiration = tonumber(ARGV[4])
+
+if redis.call("hget", familyKey, "revoked") == "1" then
+    -- family已经被吊销
+    return -2
+end
+local current = redis.call("hget", familyKey, "current")
+if current == false then
+    -- family不存在或者已经过期
+    return -3
+end
+if current ~= presented then
+    -- 用了已经被轮换掉的refresh token，认为被盗用了，吊销整个family
+    redis.call("hset", familyKey, "revoked", "1")
+    return -1
+end
+redis.call("hset", familyKey, "current", nextJti)
+redis.call("sadd", ssidsKey, nextSsid)
+redis.call("expire", familyKey, expiration)
+redis.call("expire", ssidsKey, expiration)
+return 0
@@ -0,0 +1,262 @@
+package ijwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 过期数据的清理间隔，写操作时顺手清理，不额外起goroutine
+const memorySweepInterval = time.Minute
+
+// 会话状态存在进程内存里，适合单机部署和本地开发，不依赖redis
+// 多实例部署时各实例的数据不共享，退出登录只对当前实例生效，不要这么用
+type MemoryHandler struct {
+	*tokenIssuer
+	lock sync.Mutex
+	// 退出登录的ssid -> 过期时间
+	loggedOut map[string]time.Time
+	// token family -> refresh token的状态
+	families map[string]*memoryFamily
+	// userId -> family -> 会话
+	sessions  map[int64]map[string]*Session
+	lastSweep time.Time
+}
+
+// 和redis里users:rt_family:%s的hash对应
+type memoryFamily struct {
+	// 当前有效的refresh token的jti
+	current  string
+	userId   int64
+	revoked  bool
+	ssids    []string
+	expireAt time.Time
+}
+
+func NewMemoryHandler(keys KeyManager, roles RoleProvider, opts Options) Handler {
+	return &MemoryHandler{
+		tokenIssuer: newTokenIssuer(keys, roles, opts),
+		loggedOut:   make(map[string]time.Time),
+		families:    make(map[string]*memoryFamily),
+		sessions:    make(map[int64]map[string]*Session),
+		lastSweep:   time.Now(),
+	}
+}
+
+func (m *MemoryHandler) ClearToken(ctx *gin.Context) error {
+	ctx.Header("x-ijwt-token", "")
+	ctx.Header("x-ijwt-refresh-token", "")
+	claims := ctx.MustGet("Claims").(*UserJwtClaims)
+	m.lock.Lock()
+	m.logoutLocked(claims.Ssid, time.Now())
+	m.lock.Unlock()
+	if claims.Family == "" {
+		return nil
+	}
+	return m.RevokeSession(ctx, claims.UserId, claims.Family)
+}
+
+func (m *MemoryHandler) SetLoginToken(ctx *gin.Context, userId int64) error {
+	ssid := uuid.New().String()
+	now := time.Now()
+	err := m.SetAccessToken(ctx, UserJwtClaims{
+		UserId:  userId,
+		Ssid:    ssid,
+		Family:  ssid,
+		LoginAt: now.UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	if err = m.SetRefreshToken(ctx, userId, ssid); err != nil {
+		return err
+	}
+	s := newSession(ctx, ssid, now.UnixMilli())
+	s.LastSeenAt = s.CreateAt
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.sessions[userId] == nil {
+		m.sessions[userId] = make(map[string]*Session)
+	}
+	m.sessions[userId][ssid] = &s
+	return nil
+}
+
+func (m *MemoryHandler) SetRefreshToken(ctx *gin.Context, userId int64, ssid string) error {
+	family, jti := ssid, uuid.New().String()
+	now := time.Now()
+	m.lock.Lock()
+	m.sweepLocked(now)
+	m.families[family] = &memoryFamily{
+		current:  jti,
+		userId:   userId,
+		ssids:    []string{ssid},
+		expireAt: now.Add(m.rtExpiration),
+	}
+	m.lock.Unlock()
+	return m.setRefreshToken(ctx, userId, ssid, family, jti, now.UnixMilli())
+}
+
+// 和lua/rotate_refresh.lua的逻辑保持一致
+func (m *MemoryHandler) RotateRefreshToken(ctx *gin.Context, claims *UserRefreshJwtClaims) error {
+	family := claims.Family
+	if family == "" {
+		return ErrInvalidToken
+	}
+	if _, err := m.expiresAt(claims.LoginAt, m.rtExpiration); err != nil {
+		return err
+	}
+	ssid, jti := uuid.New().String(), uuid.New().String()
+	now := time.Now()
+
+	m.lock.Lock()
+	f, ok := m.families[family]
+	if !ok || now.After(f.expireAt) || f.revoked {
+		m.lock.Unlock()
+		return ErrUserLogout
+	}
+	if f.current != claims.ID {
+		// 旧的refresh token被重复使用
+		m.revokeFamilyLocked(family, now)
+		delete(m.sessions[claims.UserId], family)
+		m.lock.Unlock()
+		return ErrRefreshTokenReused
+	}
+	f.current = jti
+	f.ssids = append(f.ssids, ssid)
+	f.expireAt = now.Add(m.rtExpiration)
+	if s, ok := m.sessions[claims.UserId][family]; ok {
+		s.LastSeenAt = now.UnixMilli()
+	}
+	m.lock.Unlock()
+
+	err := m.SetAccessToken(ctx, UserJwtClaims{
+		UserId:  claims.UserId,
+		Ssid:    ssid,
+		Family:  family,
+		LoginAt: claims.LoginAt,
+	})
+	if err != nil {
+		return err
+	}
+	return m.setRefreshToken(ctx, claims.UserId, ssid, family, jti, claims.LoginAt)
+}
+
+func (m *MemoryHandler) CheckSession(ctx *gin.Context, ssid string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if expireAt, ok := m.loggedOut[ssid]; ok && time.Now().Before(expireAt) {
+		return ErrUserLogout
+	}
+	return nil
+}
+
+func (m *MemoryHandler) TouchSession(ctx context.Context, claims *UserJwtClaims) error {
+	if claims.Family == "" {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if s, ok := m.sessions[claims.UserId][claims.Family]; ok {
+		s.LastSeenAt = time.Now().UnixMilli()
+	}
+	return nil
+}
+
+func (m *MemoryHandler) ListSessions(ctx context.Context, userId int64) ([]Session, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	sessions := make([]Session, 0, len(m.sessions[userId]))
+	for family, s := range m.sessions[userId] {
+		if m.staleLocked(s, time.Now()) {
+			delete(m.sessions[userId], family)
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, nil
+}
+
+func (m *MemoryHandler) RevokeSession(ctx context.Context, userId int64, sessionId string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.sessions[userId][sessionId]; !ok {
+		return ErrSessionNotFound
+	}
+	m.revokeFamilyLocked(sessionId, time.Now())
+	delete(m.sessions[userId], sessionId)
+	return nil
+}
+
+// 会话id不会是空串，所以不保留任何会话
+func (m *MemoryHandler) RevokeAllSessions(ctx context.Context, userId int64) error {
+	return m.RevokeOtherSessions(ctx, userId, "")
+}
+
+func (m *MemoryHandler) RevokeOtherSessions(ctx context.Context, userId int64, currentSessionId string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	now := time.Now()
+	for family := range m.sessions[userId] {
+		if family == currentSessionId {
+			continue
+		}
+		m.revokeFamilyLocked(family, now)
+		delete(m.sessions[userId], family)
+	}
+	return nil
+}
+
+// 下面的方法都要求调用方持有锁
+
+func (m *MemoryHandler) logoutLocked(ssid string, now time.Time) {
+	m.sweepLocked(now)
+	m.loggedOut[ssid] = now.Add(m.rtExpiration)
+}
+
+func (m *MemoryHandler) revokeFamilyLocked(family string, now time.Time) {
+	f, ok := m.families[family]
+	if !ok {
+		return
+	}
+	f.revoked = true
+	for _, ssid := range f.ssids {
+		m.logoutLocked(ssid, now)
+	}
+}
+
+// 长token都过期了的会话
+func (m *MemoryHandler) staleLocked(s *Session, now time.Time) bool {
+	return now.Sub(time.UnixMilli(s.LastSeenAt)) > m.rtExpiration
+}
+
+// 清理过期的数据，相当于redis的key过期
+func (m *MemoryHandler) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for ssid, expireAt := range m.loggedOut {
+		if now.After(expireAt) {
+			delete(m.loggedOut, ssid)
+		}
+	}
+	for family, f := range m.families {
+		if now.After(f.expireAt) {
+			delete(m.families, family)
+		}
+	}
+	for userId, sessions := range m.sessions {
+		for family, s := range sessions {
+			if m.staleLocked(s, now) {
+				delete(sessions, family)
+			}
+		}
+		if len(sessions) == 0 {
+			delete(m.sessions, userId)
+		}
+	}
+}
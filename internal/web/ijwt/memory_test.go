@@ -0,0 +1,107 @@
+package ijwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRoles []string
+
+func (s staticRoles) Roles(ctx context.Context, userId int64) ([]string, error) {
+	return s, nil
+}
+
+func newMemoryTestHandler(t *testing.T, opts Options) *MemoryHandler {
+	keys, err := NewKeyManager(AlgEdDSA, 1)
+	require.NoError(t, err)
+	require.NoError(t, keys.Rotate())
+	return NewMemoryHandler(keys, staticRoles{}, opts).(*MemoryHandler)
+}
+
+// 模拟一次请求，返回响应头里的长短token
+func memoryLogin(t *testing.T, h *MemoryHandler, userId int64) (*UserJwtClaims, *UserRefreshJwtClaims) {
+	ctx, recorder := newTestContext()
+	require.NoError(t, h.SetLoginToken(ctx, userId))
+	return parseIssued(t, h, recorder)
+}
+
+func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	return ctx, recorder
+}
+
+func parseIssued(t *testing.T, h *MemoryHandler, recorder *httptest.ResponseRecorder) (*UserJwtClaims, *UserRefreshJwtClaims) {
+	access, err := h.ParseAccessToken(recorder.Header().Get("x-ijwt-token"))
+	require.NoError(t, err)
+	refresh, err := h.ParseRefreshToken(recorder.Header().Get("x-ijwt-refresh-token"))
+	require.NoError(t, err)
+	return access, refresh
+}
+
+func TestMemoryHandler_Logout(t *testing.T) {
+	h := newMemoryTestHandler(t, Options{})
+	access, _ := memoryLogin(t, h, 1)
+
+	ctx, _ := newTestContext()
+	assert.NoError(t, h.CheckSession(ctx, access.Ssid))
+	sessions, err := h.ListSessions(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	ctx.Set("Claims", access)
+	require.NoError(t, h.ClearToken(ctx))
+	assert.Equal(t, ErrUserLogout, h.CheckSession(ctx, access.Ssid))
+	sessions, err = h.ListSessions(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 0)
+}
+
+func TestMemoryHandler_RotateRefreshToken(t *testing.T) {
+	h := newMemoryTestHandler(t, Options{})
+	access, refresh := memoryLogin(t, h, 1)
+
+	ctx, recorder := newTestContext()
+	require.NoError(t, h.RotateRefreshToken(ctx, refresh))
+	newAccess, _ := parseIssued(t, h, recorder)
+	assert.Equal(t, access.Family, newAccess.Family)
+	assert.NotEqual(t, access.Ssid, newAccess.Ssid)
+
+	// 再用一次旧的refresh token，整个family都被吊销
+	ctx, _ = newTestContext()
+	assert.Equal(t, ErrRefreshTokenReused, h.RotateRefreshToken(ctx, refresh))
+	assert.Equal(t, ErrUserLogout, h.CheckSession(ctx, access.Ssid))
+	assert.Equal(t, ErrUserLogout, h.CheckSession(ctx, newAccess.Ssid))
+}
+
+func TestMemoryHandler_Expire(t *testing.T) {
+	h := newMemoryTestHandler(t, Options{})
+	access, refresh := memoryLogin(t, h, 1)
+	ctx, _ := newTestContext()
+	ctx.Set("Claims", access)
+	require.NoError(t, h.ClearToken(ctx))
+	assert.Equal(t, ErrUserLogout, h.CheckSession(ctx, access.Ssid))
+
+	// 模拟过了长token的有效期
+	past := time.Now().Add(-time.Second)
+	h.loggedOut[access.Ssid] = past
+	h.families[access.Family].expireAt = past
+	assert.NoError(t, h.CheckSession(ctx, access.Ssid))
+	assert.Equal(t, ErrUserLogout, h.RotateRefreshToken(ctx, refresh))
+
+	// 触发清理
+	h.lastSweep = time.Time{}
+	h.lock.Lock()
+	h.sweepLocked(time.Now())
+	h.lock.Unlock()
+	assert.Len(t, h.loggedOut, 0)
+	assert.Len(t, h.families, 0)
+}
@@ -0,0 +1,173 @@
+package ijwt
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+)
+
+// 各个实例通过这个channel广播退出登录的ssid
+const revokedChannel = "users:ssid:revoked"
+
+// 降级相关的监控指标，通过/debug/vars查看
+var (
+	// 走降级路径的校验次数
+	degradedChecks = expvar.NewInt("webook_session_check_degraded_total")
+	// 本地数据太旧，降级也拒绝的次数
+	staleRejects = expvar.NewInt("webook_session_check_stale_rejected_total")
+	// 当前是否处于降级状态
+	degradedGauge = expvar.NewInt("webook_session_check_degraded")
+	// 上一次和redis同步成功的毫秒时间戳
+	lastSyncGauge = expvar.NewInt("webook_session_revocation_synced_at")
+	// 内存满了被挤掉的ssid个数
+	evictedRevocations = expvar.NewInt("webook_session_revocation_evicted_total")
+)
+
+// 本地的退出登录缓存，redis挂了之后靠它继续校验ssid
+type RevocationCache interface {
+	// 记录一个已经退出的ssid
+	Add(ssid string)
+	Contains(ssid string) bool
+	// redis不可用时调用，返回本地数据是否还在可接受的延迟内
+	AllowDegraded() bool
+	// 订阅其他实例的退出事件，断线重连后从redis重建，阻塞直到ctx结束
+	// 只有订阅连接确认可用时才算同步过，没有运行Sync的实例降级时一律拒绝
+	Sync(ctx context.Context, client redis.UniversalClient)
+}
+
+type localRevocationCache struct {
+	cache *bigcache.BigCache
+	// 距离上一次同步超过这个时间，本地数据就不可信了
+	lag time.Duration
+	// 上一次同步成功的毫秒时间戳
+	lastSync   atomic.Int64
+	expiration time.Duration
+	// 内存满了挤掉了还没过期的ssid，这个毫秒时间戳之前本地数据都不完整
+	incompleteUntil atomic.Int64
+}
+
+// expiration和长token的有效期一致，lag是降级时允许的最大延迟，maxMB限制内存占用
+// 刚启动时还没有同步过，要等订阅成功之后才允许降级
+func NewLocalRevocationCache(expiration time.Duration, lag time.Duration, maxMB int) RevocationCache {
+	c := &localRevocationCache{lag: lag, expiration: expiration}
+	cfg := bigcache.DefaultConfig(expiration)
+	cfg.HardMaxCacheSize = maxMB
+	cfg.OnRemoveWithReason = c.onRemove
+	cache, err := bigcache.New(context.Background(), cfg)
+	if err != nil {
+		panic(err)
+	}
+	c.cache = cache
+	return c
+}
+
+// 被挤掉的ssid在过期之前都可能还有人在用，这段时间内降级不可信
+func (c *localRevocationCache) onRemove(key string, entry []byte, reason bigcache.RemoveReason) {
+	if reason != bigcache.NoSpace {
+		return
+	}
+	evictedRevocations.Add(1)
+	c.incompleteUntil.Store(time.Now().Add(c.expiration).UnixMilli())
+}
+
+func (c *localRevocationCache) Add(ssid string) {
+	_ = c.cache.Set(ssid, []byte{1})
+}
+
+func (c *localRevocationCache) Contains(ssid string) bool {
+	_, err := c.cache.Get(ssid)
+	return err == nil
+}
+
+// 只能在确认订阅连接可用（收到消息、pong或者重建完成）时调用
+// 单纯访问redis成功说明不了漏没漏广播
+func (c *localRevocationCache) markSynced() {
+	now := time.Now().UnixMilli()
+	c.lastSync.Store(now)
+	lastSyncGauge.Set(now)
+	degradedGauge.Set(0)
+}
+
+func (c *localRevocationCache) AllowDegraded() bool {
+	degradedGauge.Set(1)
+	degradedChecks.Add(1)
+	if time.Since(time.UnixMilli(c.lastSync.Load())) > c.lag ||
+		time.Now().UnixMilli() < c.incompleteUntil.Load() {
+		staleRejects.Add(1)
+		return false
+	}
+	return true
+}
+
+func (c *localRevocationCache) Sync(ctx context.Context, client redis.UniversalClient) {
+	pubsub := client.Subscribe(ctx, revokedChannel)
+	defer pubsub.Close()
+	// 一直没人退出登录时也要证明订阅还活着，在订阅连接上定时ping，收到pong才算同步过
+	go c.heartbeat(ctx, pubsub)
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// 连不上redis，等一会再重连，Receive会自动重新订阅
+			degradedGauge.Set(1)
+			time.Sleep(time.Second)
+			continue
+		}
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			// 第一次订阅或者断线重连，期间可能漏了消息，从redis重建
+			if m.Kind == "subscribe" {
+				if err = c.rebuild(ctx, client); err != nil {
+					log.Println("重建本地退出登录缓存失败", err)
+					continue
+				}
+				c.markSynced()
+			}
+		case *redis.Message:
+			c.Add(m.Payload)
+			c.markSynced()
+		case *redis.Pong:
+			c.markSynced()
+		}
+	}
+}
+
+func (c *localRevocationCache) heartbeat(ctx context.Context, pubsub *redis.PubSub) {
+	interval := c.lag / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 断线时Receive那边会重连，这里失败了等下一轮
+			_ = pubsub.Ping(ctx)
+		}
+	}
+}
+
+// 扫描所有退出登录的ssid
+func (c *localRevocationCache) rebuild(ctx context.Context, client redis.UniversalClient) error {
+	iter := client.Scan(ctx, 0, "users:ssid:*", 1000).Iterator()
+	for iter.Next(ctx) {
+		ssid := strings.TrimPrefix(iter.Val(), "users:ssid:")
+		// 排除掉其他用同一前缀的key
+		if strings.Contains(ssid, ":") {
+			continue
+		}
+		c.Add(ssid)
+	}
+	return iter.Err()
+}
@@ -0,0 +1,45 @@
+package ijwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRevocationCache(t *testing.T) {
+	c := NewLocalRevocationCache(time.Minute, time.Millisecond*50, 1).(*localRevocationCache)
+
+	c.Add("ssid-1")
+	assert.True(t, c.Contains("ssid-1"))
+	assert.False(t, c.Contains("ssid-2"))
+
+	// 还没订阅成功过，不允许降级
+	assert.False(t, c.AllowDegraded())
+
+	// 刚同步过，允许降级
+	c.markSynced()
+	degraded := degradedChecks.Value()
+	assert.True(t, c.AllowDegraded())
+	assert.Equal(t, degraded+1, degradedChecks.Value())
+
+	// 超过允许的延迟，拒绝
+	time.Sleep(time.Millisecond * 60)
+	stale := staleRejects.Value()
+	assert.False(t, c.AllowDegraded())
+	assert.Equal(t, stale+1, staleRejects.Value())
+
+	// 重新同步后恢复
+	c.markSynced()
+	assert.Equal(t, int64(0), degradedGauge.Value())
+	assert.True(t, c.AllowDegraded())
+
+	// 过期清理不影响
+	c.onRemove("ssid-1", nil, bigcache.Expired)
+	assert.True(t, c.AllowDegraded())
+	// 内存满了挤掉了没过期的ssid，同步了也不能降级
+	c.onRemove("ssid-1", nil, bigcache.NoSpace)
+	c.markSynced()
+	assert.False(t, c.AllowDegraded())
+}
@@ -0,0 +1,180 @@
+package ijwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrSessionNotFound = errors.New("会话不存在")
+
+// 一次登录对应的会话，Id就是token family
+type Session struct {
+	Id         string `json:"id"`
+	Device     string `json:"device"`
+	UserAgent  string `json:"userAgent"`
+	Ip         string `json:"ip"`
+	CreateAt   int64  `json:"createdAt"`
+	LastSeenAt int64  `json:"lastSeenAt"`
+	// 是否是发起请求的会话，由调用方填
+	Current bool `json:"current"`
+}
+
+// 根据登录请求构造会话信息
+func newSession(ctx *gin.Context, family string, now int64) Session {
+	ua := ctx.Request.UserAgent()
+	return Session{
+		Id:        family,
+		Device:    deviceOf(ctx.GetHeader("x-device"), ua),
+		UserAgent: ua,
+		Ip:        ctx.ClientIP(),
+		CreateAt:  now,
+	}
+}
+
+// 登录时登记会话
+func (r *RedisHandler) addSession(ctx *gin.Context, userId int64, family string) error {
+	now := time.Now().UnixMilli()
+	val, err := json.Marshal(newSession(ctx, family, now))
+	if err != nil {
+		return err
+	}
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, r.sessionsKey(userId), family, val)
+	pipe.HSet(ctx, r.sessionsSeenKey(userId), family, now)
+	pipe.Expire(ctx, r.sessionsKey(userId), r.rtExpiration)
+	pipe.Expire(ctx, r.sessionsSeenKey(userId), r.rtExpiration)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisHandler) touchSession(ctx context.Context, userId int64, family string) error {
+	pipe := r.client.Pipeline()
+	pipe.HSet(ctx, r.sessionsSeenKey(userId), family, time.Now().UnixMilli())
+	pipe.Expire(ctx, r.sessionsKey(userId), r.rtExpiration)
+	pipe.Expire(ctx, r.sessionsSeenKey(userId), r.rtExpiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 每个登录态的请求都刷新一下最后活跃时间
+func (r *RedisHandler) TouchSession(ctx context.Context, claims *UserJwtClaims) error {
+	if claims.Family == "" {
+		return nil
+	}
+	return r.client.HSet(ctx, r.sessionsSeenKey(claims.UserId), claims.Family, time.Now().UnixMilli()).Err()
+}
+
+func (r *RedisHandler) ListSessions(ctx context.Context, userId int64) ([]Session, error) {
+	infos, err := r.client.HGetAll(ctx, r.sessionsKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+	seen, err := r.client.HGetAll(ctx, r.sessionsSeenKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(infos))
+	var stale []string
+	for family, info := range infos {
+		var s Session
+		if err = json.Unmarshal([]byte(info), &s); err != nil {
+			return nil, err
+		}
+		s.LastSeenAt, _ = strconv.ParseInt(seen[family], 10, 64)
+		// 长token都已经过期了，顺手清理掉
+		if time.Since(time.UnixMilli(s.LastSeenAt)) > r.rtExpiration {
+			stale = append(stale, family)
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	if len(stale) > 0 {
+		_ = r.removeSessions(ctx, userId, stale...)
+	}
+	return sessions, nil
+}
+
+func (r *RedisHandler) RevokeSession(ctx context.Context, userId int64, sessionId string) error {
+	ok, err := r.client.HExists(ctx, r.sessionsKey(userId), sessionId).Result()
+	if err != nil {
+		return err
+	}
+	// 只能吊销自己的会话
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err = r.revokeFamily(ctx, sessionId); err != nil {
+		return err
+	}
+	return r.removeSessions(ctx, userId, sessionId)
+}
+
+// 会话id不会是空串，所以不保留任何会话
+func (r *RedisHandler) RevokeAllSessions(ctx context.Context, userId int64) error {
+	return r.RevokeOtherSessions(ctx, userId, "")
+}
+
+func (r *RedisHandler) RevokeOtherSessions(ctx context.Context, userId int64, currentSessionId string) error {
+	families, err := r.client.HKeys(ctx, r.sessionsKey(userId)).Result()
+	if err != nil {
+		return err
+	}
+	var revoked []string
+	for _, family := range families {
+		if family == currentSessionId {
+			continue
+		}
+		if err = r.revokeFamily(ctx, family); err != nil {
+			return err
+		}
+		revoked = append(revoked, family)
+	}
+	if len(revoked) == 0 {
+		return nil
+	}
+	return r.removeSessions(ctx, userId, revoked...)
+}
+
+func (r *RedisHandler) removeSessions(ctx context.Context, userId int64, families ...string) error {
+	pipe := r.client.Pipeline()
+	pipe.HDel(ctx, r.sessionsKey(userId), families...)
+	pipe.HDel(ctx, r.sessionsSeenKey(userId), families...)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisHandler) sessionsKey(userId int64) string {
+	return fmt.Sprintf("users:sessions:%d", userId)
+}
+
+func (r *RedisHandler) sessionsSeenKey(userId int64) string {
+	return fmt.Sprintf("users:sessions:%d:seen", userId)
+}
+
+// 客户端可以通过x-device自己上报设备名，否则根据UserAgent粗略判断
+func deviceOf(device string, ua string) string {
+	if device != "" {
+		return device
+	}
+	lower := strings.ToLower(ua)
+	switch {
+	case strings.Contains(lower, "iphone"), strings.Contains(lower, "ipad"):
+		return "iOS"
+	case strings.Contains(lower, "android"):
+		return "Android"
+	case strings.Contains(lower, "windows"):
+		return "Windows"
+	case strings.Contains(lower, "mac os"):
+		return "Mac"
+	case strings.Contains(lower, "linux"):
+		return "Linux"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"net/http"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 公开jwt的校验公钥，其他服务拿来校验webook签发的token
+type JWKSHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	JWKS(ctx *gin.Context)
+}
+
+type jwksHandler struct {
+	keys ijwt.KeyManager
+}
+
+func NewJWKSHandler(keys ijwt.KeyManager) JWKSHandler {
+	return &jwksHandler{keys: keys}
+}
+
+func (h *jwksHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	rules.Routes(&server.RouterGroup, middleware.AuthPublic).
+		GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *jwksHandler) JWKS(ctx *gin.Context) {
+	// 允许其他服务缓存一会，轮换密钥时旧密钥还会保留一段时间
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,105 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 密码登录前检查是否被锁定、是否需要人机验证，不通过时已经写好了响应，返回不通过的原因
+// 通过时占用了一次尝试，密码错误就留着算一次失败，其他结果要调用loginReleased还回去
+func checkLoginGuard(ctx *gin.Context, guard service.LoginGuardService, account string, captcha string) (domain.LoginAttempt, error) {
+	attempt, err := guard.Check(ctx, account, ctx.ClientIP(), captcha)
+	switch err {
+	case nil:
+		return attempt, nil
+	case service.ErrLoginLocked:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录失败次数过多，请稍后再试", Data: gin.H{"lockedUntil": attempt.LockedUntil}})
+	case service.ErrIpBlocked:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录失败次数过多，请稍后再试"})
+	case service.ErrCaptchaRequired:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请完成人机验证", Data: gin.H{"captchaRequired": true}})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+	return attempt, err
+}
+
+func loginReleased(ctx *gin.Context, guard service.LoginGuardService, attempt domain.LoginAttempt) {
+	if err := guard.Release(ctx, attempt); err != nil {
+		log.Println("归还登录尝试出错", err)
+	}
+}
+
+// 记录失败不影响这次的响应
+func loginFailed(ctx *gin.Context, guard service.LoginGuardService, account string) {
+	if err := guard.Failed(ctx, account, ctx.ClientIP()); err != nil {
+		log.Println("记录登录失败次数出错", err)
+	}
+}
+
+func loginSucceeded(ctx *gin.Context, guard service.LoginGuardService, account string) {
+	if err := guard.Succeeded(ctx, account); err != nil {
+		log.Println("清除登录失败次数出错", err)
+	}
+}
+
+// 管理员查看、解除密码登录的锁定
+type LoginLockHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	State(ctx *gin.Context)
+	Unlock(ctx *gin.Context)
+}
+
+type loginLockHandler struct {
+	guard service.LoginGuardService
+	rbac  *middleware.RBACMiddlewareBuilder
+}
+
+func NewLoginLockHandler(guard service.LoginGuardService, rbac *middleware.RBACMiddlewareBuilder) LoginLockHandler {
+	return &loginLockHandler{guard: guard, rbac: rbac}
+}
+
+func (h *loginLockHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	lg := server.Group("/admin/login_locks")
+	lg.Use(h.rbac.RequirePermission(domain.PermissionLoginLockManage))
+	rules.Routes(lg, middleware.AuthRequired).
+		POST("", h.State).
+		POST("/unlock", h.Unlock)
+}
+
+type loginLockReq struct {
+	// 登录用的邮箱
+	Account string `json:"account"`
+}
+
+func (h *loginLockHandler) State(ctx *gin.Context) {
+	var req loginLockReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	state, err := h.guard.State(ctx, req.Account)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: state})
+}
+
+func (h *loginLockHandler) Unlock(ctx *gin.Context) {
+	var req loginLockReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err := h.guard.Unlock(ctx, req.Account); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
@@ -0,0 +1,116 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 用户查看自己最近的登录活动，管理员查询所有登录记录
+type LoginHistoryHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Activity(ctx *gin.Context)
+	Search(ctx *gin.Context)
+}
+
+type loginHistoryHandler struct {
+	svc  service.LoginHistoryService
+	rbac *middleware.RBACMiddlewareBuilder
+}
+
+func NewLoginHistoryHandler(svc service.LoginHistoryService, rbac *middleware.RBACMiddlewareBuilder) LoginHistoryHandler {
+	return &loginHistoryHandler{svc: svc, rbac: rbac}
+}
+
+func (h *loginHistoryHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	// 个人访问令牌不能查看登录活动
+	rules.Routes(server.Group("/user/security"), middleware.AuthRequired).
+		POST("/activity", h.Activity)
+	rules.Routes(server.Group("/admin/login_history"), middleware.AuthRequired).
+		POST("/search", h.rbac.RequirePermission(domain.PermissionLoginHistoryView), h.Search)
+}
+
+type loginActivityResp struct {
+	Records []domain.LoginRecord `json:"records"`
+	// 为0表示没有下一页
+	Cursor int64 `json:"cursor"`
+}
+
+func (h *loginHistoryHandler) Activity(ctx *gin.Context) {
+	type Req struct {
+		Cursor int64 `json:"cursor"`
+		Limit  int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	records, cursor, err := h.svc.Recent(ctx, claims.UserId, req.Cursor, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: loginActivityResp{Records: records, Cursor: cursor}})
+}
+
+func (h *loginHistoryHandler) Search(ctx *gin.Context) {
+	type Req struct {
+		domain.LoginRecordFilter
+		Page     int `json:"page"`
+		PageSize int `json:"pageSize"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	records, total, err := h.svc.Search(ctx, req.LoginRecordFilter, req.Page, req.PageSize)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: gin.H{"items": records, "total": total}})
+}
+
+// 记录一次登录尝试，补上ip、浏览器和这次签发的会话，记录失败不影响这次的响应
+func recordLogin(ctx *gin.Context, svc service.LoginHistoryService, r domain.LoginRecord) {
+	r.Ip = ctx.ClientIP()
+	r.UserAgent = ctx.Request.UserAgent()
+	if ssid := ijwt.IssuedSsid(ctx); ssid != "" {
+		r.Ssid = ssid
+	}
+	if err := svc.Record(ctx, r); err != nil {
+		log.Println("记录登录历史失败", err)
+	}
+}
+
+// 登录失败的原因
+func loginResultOf(err error) string {
+	switch err {
+	case service.ErrEmailOrPassWrong, service.ErrTwoFactorCodeWrong:
+		return domain.LoginResultWrongCredentials
+	case service.ErrLoginLocked, service.ErrIpBlocked, service.ErrCaptchaRequired,
+		service.ErrCodeVerifyTooMany, service.ErrChallengeVerifyTooMany:
+		return domain.LoginResultLocked
+	case service.ErrUserBanned:
+		return domain.LoginResultBanned
+	case service.ErrUserDeleted:
+		return domain.LoginResultDeleted
+	case service.ErrChallengeInvalid, service.ErrTwoFactorNotEnabled,
+		ErrUserLogout, ijwt.ErrSessionExpired:
+		return domain.LoginResultInvalidToken
+	case ijwt.ErrRefreshTokenReused:
+		return domain.LoginResultTokenReused
+	default:
+		return domain.LoginResultError
+	}
+}
@@ -0,0 +1,188 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 短信验证码证明另一个账号是自己的
+const mergeAccountBiz = "merge_account"
+
+// 把另一个账号合并到当前登录的账号上
+type MergeHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	SendCode(ctx *gin.Context)
+	Merge(ctx *gin.Context)
+}
+
+type mergeHandler struct {
+	svc         service.AccountMergeService
+	userSvc     service.UserService
+	identitySvc service.IdentityService
+	codeSvc     service.CodeService
+	handler     ijwt.Handler
+	// 邮箱+密码证明的时候，和密码登录一样防暴力破解
+	guard service.LoginGuardService
+	// 证明方式绕不过被合并账号的两步验证
+	twoFactor service.TwoFactorService
+}
+
+func NewMergeHandler(svc service.AccountMergeService, userSvc service.UserService, identitySvc service.IdentityService,
+	codeSvc service.CodeService, handler ijwt.Handler, guard service.LoginGuardService,
+	twoFactor service.TwoFactorService) MergeHandler {
+	return &mergeHandler{svc: svc, userSvc: userSvc, identitySvc: identitySvc, codeSvc: codeSvc, handler: handler, guard: guard,
+		twoFactor: twoFactor}
+}
+
+func (m *mergeHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	mg := server.Group("/user/merge")
+	// 当前会话证明保留的账号是自己的，个人访问令牌不能合并
+	rules.Routes(mg, middleware.AuthRequired).
+		POST("", m.Merge).
+		POST("/code/send", m.SendCode)
+}
+
+// 给另一个账号的手机号发验证码
+func (m *mergeHandler) SendCode(ctx *gin.Context) {
+	type sendReq struct {
+		Phone string `json:"phone"`
+	}
+	var req sendReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if req.Phone == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请输入手机号"})
+		return
+	}
+	err := m.codeSvc.Send(ctx, mergeAccountBiz, req.Phone)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+	case service.ErrCodeSendTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送过于频繁"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 用手机号+验证码，或者邮箱+密码证明另一个账号是自己的，然后合并到当前账号
+func (m *mergeHandler) Merge(ctx *gin.Context) {
+	type mergeReq struct {
+		Phone    string `json:"phone"`
+		Code     string `json:"code"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Captcha  string `json:"captcha"`
+	}
+	var req mergeReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	var sourceId int64
+	var err error
+	if req.Phone != "" {
+		sourceId, err = m.proveByCode(ctx, req.Phone, req.Code)
+	} else {
+		var attempt domain.LoginAttempt
+		attempt, err = checkLoginGuard(ctx, m.guard, req.Email, req.Captcha)
+		if err != nil {
+			return
+		}
+		var u domain.User
+		u, err = m.userSvc.VerifyPassword(ctx, req.Email, req.Password)
+		sourceId = u.Id
+		// 密码错误时占用的尝试就是这次失败
+		if err != service.ErrEmailOrPassWrong {
+			loginReleased(ctx, m.guard, attempt)
+		}
+	}
+	switch err {
+	case nil:
+	case errCodeWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	case service.ErrCodeVerifyTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"})
+		return
+	case service.ErrIdentityNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这个手机号没有注册账号"})
+		return
+	case service.ErrEmailOrPassWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "邮箱或密码错误"})
+		return
+	case service.ErrUserBanned:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这个账号已被封禁，不能合并"})
+		return
+	case service.ErrUserDeleted:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这个账号已注销，不能合并"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	enabled, err := m.twoFactor.Enabled(ctx, sourceId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if enabled {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "被合并的账号启用了两步验证，请先登录它关闭两步验证"})
+		return
+	}
+
+	evt, err := m.svc.Merge(ctx, claims.UserId, sourceId)
+	switch err {
+	case nil:
+	case service.ErrMergeSameUser:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这就是当前登录的账号"})
+		return
+	case service.ErrMergeConflict:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "两个账号绑定了同一类登录方式，请先解绑其中一个"})
+		return
+	case service.ErrUserMerged:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已经被合并过了"})
+		return
+	case service.ErrUserBanned:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这个账号已被封禁，不能合并"})
+		return
+	case service.ErrUserDeleted:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "这个账号已注销，不能合并"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	// 被合并的账号只剩墓碑，登录着的设备都踢掉
+	if err = m.handler.RevokeAllSessions(ctx, evt.SourceId); err != nil {
+		log.Println("合并账号后退出被合并账号的会话失败", err)
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "合并成功", Data: evt})
+}
+
+// 校验验证码，返回手机号所属的用户
+func (m *mergeHandler) proveByCode(ctx *gin.Context, phone string, code string) (int64, error) {
+	ok, err := m.codeSvc.Verify(ctx, mergeAccountBiz, phone, code)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errCodeWrong
+	}
+	return m.identitySvc.Owner(ctx, domain.IdentityPhone, phone)
+}
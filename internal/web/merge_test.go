@@ -0,0 +1,88 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/web/ijwt"
+	ijwtmocks "webook/internal/web/ijwt/mocks"
+	"webook/internal/web/middleware"
+
+	"go.uber.org/mock/gomock"
+)
+
+// 用手机号+验证码证明被合并的账号
+func TestMergeHandler_MergeByCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.AccountMergeService, ijwt.Handler)
+
+		wantResponse Result
+	}{
+		{
+			name: "合并成功，下线被合并的账号",
+			mock: func(ctrl *gomock.Controller) (service.AccountMergeService, ijwt.Handler) {
+				svc := svcmocks.NewMockAccountMergeService(ctrl)
+				svc.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).
+					Return(domain.UserMergedEvent{SourceId: 2, TargetId: 1}, nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+				return svc, handler
+			},
+			wantResponse: Result{Code: 0, Msg: "合并成功",
+				Data: map[string]any{"sourceId": float64(2), "targetId": float64(1), "mergedAt": float64(0)}},
+		},
+		{
+			name: "被合并的账号封禁中",
+			mock: func(ctrl *gomock.Controller) (service.AccountMergeService, ijwt.Handler) {
+				svc := svcmocks.NewMockAccountMergeService(ctrl)
+				svc.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).
+					Return(domain.UserMergedEvent{}, service.ErrUserBanned)
+				return svc, ijwtmocks.NewMockHandler(ctrl)
+			},
+			wantResponse: Result{Code: 4, Msg: "这个账号已被封禁，不能合并"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, handler := tc.mock(ctrl)
+			codeSvc := svcmocks.NewMockCodeService(ctrl)
+			codeSvc.EXPECT().Verify(gomock.Any(), mergeAccountBiz, "13800000000", "123456").Return(true, nil)
+			identitySvc := svcmocks.NewMockIdentityService(ctrl)
+			identitySvc.EXPECT().Owner(gomock.Any(), domain.IdentityPhone, "13800000000").Return(int64(2), nil)
+			h := NewMergeHandler(svc, svcmocks.NewMockUserService(ctrl), identitySvc, codeSvc, handler,
+				svcmocks.NewMockLoginGuardService(ctrl), newTestTwoFactor(ctrl))
+
+			server := gin.Default()
+			// 模拟登录中间件
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("Claims", &ijwt.UserJwtClaims{UserId: 1})
+			})
+			h.RegisterRoutes(server, middleware.NewAuthRules())
+			body := bytes.NewBufferString(`{"phone":"13800000000","code":"123456"}`)
+			req, err := http.NewRequest(http.MethodPost, "/user/merge", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, req)
+
+			var respBody Result
+			if err = json.NewDecoder(response.Body).Decode(&respBody); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			assert.Equal(t, respBody, tc.wantResponse)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 路由的鉴权级别
+type AuthLevel int
+
+const (
+	// 必须登录，没有匹配到任何规则时的默认级别
+	AuthRequired AuthLevel = iota
+	// 可选登录：带了合法token就设置Claims，没带或者不合法也放行
+	AuthOptional
+	// 公开，不校验token
+	AuthPublic
+)
+
+// 一条鉴权规则
+// Pattern支持三种写法：
+//   - /user/login    精确匹配
+//   - /u/*           *匹配一段路径
+//   - /static/**     **只能放在最后，匹配任意后缀（前缀匹配）
+//
+// Method为空或者*表示匹配所有方法
+// Scope是个人访问令牌访问这个路由需要的授权范围，为空表示不允许用令牌访问
+type AuthRule struct {
+	Method  string
+	Pattern string
+	Level   AuthLevel
+	Scope   string
+}
+
+// 鉴权规则引擎，handler注册路由的时候声明自己的鉴权级别
+// 多条规则都能匹配时，越具体的规则优先级越高
+type AuthRules struct {
+	lock  sync.RWMutex
+	rules []AuthRule
+}
+
+func NewAuthRules() *AuthRules {
+	return &AuthRules{}
+}
+
+func (r *AuthRules) Add(method string, pattern string, level AuthLevel) *AuthRules {
+	return r.AddRule(AuthRule{Method: method, Pattern: pattern, Level: level})
+}
+
+func (r *AuthRules) AddRule(rule AuthRule) *AuthRules {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	rule.Method = strings.ToUpper(rule.Method)
+	r.rules = append(r.rules, rule)
+	return r
+}
+
+func (r *AuthRules) Public(method string, pattern string) *AuthRules {
+	return r.Add(method, pattern, AuthPublic)
+}
+
+func (r *AuthRules) Optional(method string, pattern string) *AuthRules {
+	return r.Add(method, pattern, AuthOptional)
+}
+
+func (r *AuthRules) Required(method string, pattern string) *AuthRules {
+	return r.Add(method, pattern, AuthRequired)
+}
+
+// 找到最具体的规则，没有匹配则必须登录
+func (r *AuthRules) Match(method string, path string) AuthLevel {
+	return r.Lookup(method, path).Level
+}
+
+// 返回最具体的那条规则，没有匹配则返回必须登录、不允许令牌访问的规则
+func (r *AuthRules) Lookup(method string, path string) AuthRule {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	matched, best := AuthRule{Level: AuthRequired}, -1
+	for _, rule := range r.rules {
+		if rule.Method != "" && rule.Method != "*" && rule.Method != method {
+			continue
+		}
+		score, ok := matchPattern(rule.Pattern, path)
+		if !ok {
+			continue
+		}
+		// 指定了方法的规则更具体
+		if rule.Method != "" && rule.Method != "*" {
+			score++
+		}
+		if score > best {
+			matched, best = rule, score
+		}
+	}
+	return matched
+}
+
+// 返回是否匹配，以及匹配的具体程度：字面量段越多越具体
+func matchPattern(pattern string, path string) (int, bool) {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	score := 0
+	for i, seg := range patternSegs {
+		if seg == "**" && i == len(patternSegs)-1 {
+			return score * 4, true
+		}
+		if i >= len(pathSegs) {
+			return 0, false
+		}
+		switch seg {
+		case "*":
+			score++
+		case pathSegs[i]:
+			score += 2
+		default:
+			return 0, false
+		}
+	}
+	if len(patternSegs) != len(pathSegs) {
+		return 0, false
+	}
+	// 完整匹配的比前缀匹配更具体
+	return score*4 + 2, true
+}
+
+// 注册路由的同时声明鉴权级别
+type AuthRoutes struct {
+	group gin.IRoutes
+	base  string
+	rules *AuthRules
+	level AuthLevel
+	scope string
+}
+
+// 用路由分组注册，根路由可以传&server.RouterGroup
+func (r *AuthRules) Routes(group *gin.RouterGroup, level AuthLevel) *AuthRoutes {
+	return &AuthRoutes{group: group, base: group.BasePath(), rules: r, level: level}
+}
+
+// 之后注册的路由允许持有这个scope的个人访问令牌访问
+func (a *AuthRoutes) Scope(scope string) *AuthRoutes {
+	scoped := *a
+	scoped.scope = scope
+	return &scoped
+}
+
+func (a *AuthRoutes) Handle(method string, relativePath string, handlers ...gin.HandlerFunc) *AuthRoutes {
+	a.group.Handle(method, relativePath, handlers...)
+	a.rules.AddRule(AuthRule{Method: method, Pattern: joinPath(a.base, relativePath), Level: a.level, Scope: a.scope})
+	return a
+}
+
+func (a *AuthRoutes) GET(relativePath string, handlers ...gin.HandlerFunc) *AuthRoutes {
+	return a.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (a *AuthRoutes) POST(relativePath string, handlers ...gin.HandlerFunc) *AuthRoutes {
+	return a.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (a *AuthRoutes) Any(relativePath string, handlers ...gin.HandlerFunc) *AuthRoutes {
+	a.group.Any(relativePath, handlers...)
+	a.rules.AddRule(AuthRule{Method: "*", Pattern: joinPath(a.base, relativePath), Level: a.level, Scope: a.scope})
+	return a
+}
+
+// gin的路径参数（:id、*path）转换成规则里的通配符
+func joinPath(base string, relativePath string) string {
+	full := strings.TrimRight(base, "/") + "/" + strings.TrimLeft(relativePath, "/")
+	segs := strings.Split(full, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") {
+			segs[i] = "*"
+		} else if strings.HasPrefix(seg, "*") {
+			segs[i] = "**"
+		}
+	}
+	return strings.Join(segs, "/")
+}
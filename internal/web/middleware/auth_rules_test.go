@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthRules_Match(t *testing.T) {
+	rules := NewAuthRules().
+		Public("*", "/user/login").
+		Public(http.MethodGet, "/static/**").
+		Optional(http.MethodGet, "/u/*").
+		Required(http.MethodGet, "/u/*/private").
+		Public("", "/open/**").
+		Required(http.MethodPost, "/open/admin")
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+
+		wantLevel AuthLevel
+	}{
+		{name: "精确匹配", method: http.MethodPost, path: "/user/login", wantLevel: AuthPublic},
+		{name: "没有匹配的规则默认必须登录", method: http.MethodPost, path: "/user/profile", wantLevel: AuthRequired},
+		{name: "前缀匹配", method: http.MethodGet, path: "/static/js/app.js", wantLevel: AuthPublic},
+		{name: "前缀匹配方法不对", method: http.MethodPost, path: "/static/js/app.js", wantLevel: AuthRequired},
+		{name: "单段通配", method: http.MethodGet, path: "/u/tom", wantLevel: AuthOptional},
+		{name: "单段通配不匹配多段", method: http.MethodGet, path: "/u/tom/posts", wantLevel: AuthRequired},
+		{name: "更具体的规则优先", method: http.MethodGet, path: "/u/tom/private", wantLevel: AuthRequired},
+		{name: "精确规则优先于前缀规则", method: http.MethodPost, path: "/open/admin", wantLevel: AuthRequired},
+		{name: "前缀规则兜底", method: http.MethodPost, path: "/open/anything", wantLevel: AuthPublic},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantLevel, rules.Match(tc.method, tc.path))
+		})
+	}
+}
+
+func TestAuthRules_Routes(t *testing.T) {
+	rules := NewAuthRules()
+	server := gin.New()
+	rules.Routes(server.Group("/u"), AuthOptional).GET("/:handle", func(ctx *gin.Context) {})
+	rules.Routes(server.Group("/files"), AuthPublic).GET("/*path", func(ctx *gin.Context) {})
+
+	assert.Equal(t, AuthOptional, rules.Match(http.MethodGet, "/u/tom"))
+	assert.Equal(t, AuthRequired, rules.Match(http.MethodPost, "/u/tom"))
+	assert.Equal(t, AuthPublic, rules.Match(http.MethodGet, "/files/a/b.png"))
+}
+
+func TestAuthRoutes_Scope(t *testing.T) {
+	rules := NewAuthRules()
+	server := gin.New()
+	routes := rules.Routes(server.Group("/user"), AuthRequired)
+	routes.Scope("profile:read").POST("/profile", func(ctx *gin.Context) {})
+	routes.POST("/logout", func(ctx *gin.Context) {})
+
+	assert.Equal(t, "profile:read", rules.Lookup(http.MethodPost, "/user/profile").Scope)
+	// Scope不影响原来的分组
+	assert.Equal(t, "", rules.Lookup(http.MethodPost, "/user/logout").Scope)
+	assert.Equal(t, AuthRule{Level: AuthRequired}, rules.Lookup(http.MethodPost, "/nothing"))
+}
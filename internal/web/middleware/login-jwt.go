@@ -1,15 +1,27 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/redis/go-redis/v9"
 	"log"
 	"net/http"
+	"strings"
+	"time"
+	"webook/internal/domain"
 	"webook/internal/web/ijwt"
 )
 
+// 校验个人访问令牌的明文
+type PersonalTokenVerifier interface {
+	Verify(ctx context.Context, token string) (domain.PersonalToken, error)
+}
+
+// 查询账号是否被封禁或者正在注销，每个请求都会调用，实现方自己做缓存
+type AccountStatusChecker interface {
+	Unusable(ctx context.Context, userId int64) (bool, error)
+}
+
 /*
 *
 jwt相关的点
@@ -20,18 +32,48 @@ jwt相关的点
 5. redis挂了如何降级？
 */
 type LoginJWTMiddlewareBuilder struct {
-	paths   []string
-	client  redis.Cmdable
+	rules *AuthRules
+	// 退出登录的判断交给handler，底层是redis还是内存中间件不关心
 	handler ijwt.Handler
+	// 短token剩余有效期小于这个值时自动续期，0表示不续期
+	renewWindow time.Duration
+	// 为nil时不接受个人访问令牌
+	personalTokens PersonalTokenVerifier
+	// 为nil时不检查封禁状态
+	accountStatus AccountStatusChecker
 }
 
-func NewLoginJWTMiddlewareBuilder(client redis.Cmdable, handler ijwt.Handler) *LoginJWTMiddlewareBuilder {
-	return &LoginJWTMiddlewareBuilder{client: client, handler: handler}
+func NewLoginJWTMiddlewareBuilder(handler ijwt.Handler) *LoginJWTMiddlewareBuilder {
+	return &LoginJWTMiddlewareBuilder{handler: handler, rules: NewAuthRules()}
+}
+
+// 使用共享的规则引擎，handler注册路由时声明的规则都在里面
+func (m *LoginJWTMiddlewareBuilder) Rules(rules *AuthRules) *LoginJWTMiddlewareBuilder {
+	m.rules = rules
+	return m
 }
 
-// Builder模式
+// Builder模式，path支持AuthRules的通配写法
 func (m *LoginJWTMiddlewareBuilder) Ignore(path string) *LoginJWTMiddlewareBuilder {
-	m.paths = append(m.paths, path)
+	m.rules.Public("*", path)
+	return m
+}
+
+// 开启短token滑动续期，最长不会超过会话的最长寿命
+func (m *LoginJWTMiddlewareBuilder) RenewWindow(window time.Duration) *LoginJWTMiddlewareBuilder {
+	m.renewWindow = window
+	return m
+}
+
+// 允许用个人访问令牌代替jwt，令牌只能访问声明了对应scope的路由
+func (m *LoginJWTMiddlewareBuilder) PersonalTokens(verifier PersonalTokenVerifier) *LoginJWTMiddlewareBuilder {
+	m.personalTokens = verifier
+	return m
+}
+
+// 被封禁、正在注销的账号，已经签发的jwt和个人访问令牌也不能再用
+func (m *LoginJWTMiddlewareBuilder) AccountStatus(checker AccountStatusChecker) *LoginJWTMiddlewareBuilder {
+	m.accountStatus = checker
 	return m
 }
 
@@ -39,85 +81,104 @@ func (m *LoginJWTMiddlewareBuilder) Ignore(path string) *LoginJWTMiddlewareBuild
 // 校验jwt token是否存在
 func (m *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// 对指定路径无视中间件
-		for _, path := range m.paths {
-			if ctx.Request.URL.Path == path {
-				return
-			}
-		}
-
-		//// 判断header
-		//tokenHeader := ctx.Request.Header.Get("Authorization")
-		//if tokenHeader == "" {
-		//	//	没带token
-		//	ctx.AbortWithStatus(http.StatusUnauthorized)
-		//	return
-		//}
-		//// 通常是 Bearer xxx
-		//segs := strings.Split(tokenHeader, " ")
-		//if len(segs) != 2 {
-		//	ctx.AbortWithStatus(http.StatusUnauthorized)
-		//	return
-		//}
-		//
-		//// 真正的tokenString: xxxx.xxxx.xxxx
-		//// 校验jwt token
-		//tokenStr := segs[1]
-
-		tokenStr := m.handler.ExtractTokenString(ctx)
-		if tokenStr == "" {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
+		rule := m.rules.Lookup(ctx.Request.Method, ctx.Request.URL.Path)
+		// 公开的路由无视中间件
+		if rule.Level == AuthPublic {
 			return
 		}
 
-		claims := &ijwt.UserJwtClaims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("95osj3fUD7fo0mlYdDbncXz4VD2igvf0"), nil
-		})
-		if err != nil {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
-		}
-		// 判断jwt payload，此处等于0也许不行
-		if token == nil || !token.Valid || claims.UserId == 0 {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
-		}
-		//	判断UserAgent
-		if ctx.Request.UserAgent() != claims.UserAgent {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
+		claims, status := m.authenticate(ctx, rule)
+		if status == http.StatusOK {
+			status = m.checkAccountStatus(ctx, claims.UserId)
 		}
-		// 判断是否已经退出登录
-		logout, err := m.client.Exists(ctx, fmt.Sprintf("users:ssid:%s", claims.Ssid)).Result()
-		// redis崩溃、或者用户退出
-		// 也许可以扩展的点，如果redis崩溃了，就不校验ssid了，作为一个降级策略
-		// 如果redis没有崩溃，则校验ssid
-		if err != nil {
-			// log一下
-			log.Println("记录用户ssid的中间件挂了")
-		} else if logout > 0 {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
+		if status != http.StatusOK {
+			// 可选登录的路由，token不合法就当没登录
+			if rule.Level == AuthOptional && status == http.StatusUnauthorized {
+				return
+			}
+			ctx.AbortWithStatus(status)
 			return
 		}
 
-		//// token续期
-		//now := time.Now()
-		//// 距离签发时间过了10s，就刷新
-		//if claims.ExpiresAt.Sub(now) < time.Second*50 {
-		//	// 重新设定过期时间
-		//	claims.ExpiresAt = ijwt.NewNumericDate(time.Now().Add(time.Minute))
-		//	// 办法新token
-		//	tokenStr, err := token.SignedString([]byte("95osj3fUD7fo0mlYdDbncXz4VD2igvf0"))
-		//	if err != nil {
-		//		// 刷新失败
-		//		log.Println("jwt刷新失败 ", err)
-		//	}
-		//	fmt.Println("签发失败吗 ", err)
-		//	ctx.Header("x-ijwt-token", tokenStr)
-		//}
-
 		// 拿到user信息，供后面的路由使用，注意这里放的是指针，断言应该也是指针
 		ctx.Set("Claims", claims)
 	}
 }
+
+// 校验token，返回claims和http状态码
+func (m *LoginJWTMiddlewareBuilder) authenticate(ctx *gin.Context, rule AuthRule) (*ijwt.UserJwtClaims, int) {
+	tokenStr := m.handler.ExtractTokenString(ctx)
+	if tokenStr == "" {
+		return nil, http.StatusUnauthorized
+	}
+	if m.personalTokens != nil && strings.HasPrefix(tokenStr, domain.PersonalTokenPrefix) {
+		return m.authenticatePersonalToken(ctx, rule, tokenStr)
+	}
+
+	// 按header里的kid找公钥校验，同时校验payload
+	claims, err := m.handler.ParseAccessToken(tokenStr)
+	if err != nil {
+		return nil, http.StatusUnauthorized
+	}
+	//	判断UserAgent
+	if ctx.Request.UserAgent() != claims.UserAgent {
+		return nil, http.StatusUnauthorized
+	}
+	// 判断是否已经退出登录，redis挂了会降级到本地缓存
+	err = m.handler.CheckSession(ctx, claims.Ssid)
+	if errors.Is(err, ijwt.ErrSessionCheckUnavailable) {
+		// 本地数据太旧了，没法判断
+		log.Println("校验ssid失败", err)
+		return nil, http.StatusServiceUnavailable
+	}
+	if err != nil {
+		return nil, http.StatusUnauthorized
+	}
+	// 更新会话的最后活跃时间，失败不影响请求
+	if err = m.handler.TouchSession(ctx, claims); err != nil {
+		log.Println("更新会话活跃时间失败", err)
+	}
+
+	// token续期：快过期了就用同一个ssid重新签发，通过header返回
+	if m.renewWindow > 0 && claims.ExpiresAt != nil && time.Until(claims.ExpiresAt.Time) < m.renewWindow {
+		err = m.handler.SetAccessToken(ctx, *claims)
+		// 超过会话最长寿命的不再续期，等token自然过期
+		if err != nil && err != ijwt.ErrSessionExpired {
+			log.Println("jwt续期失败", err)
+		}
+	}
+	return claims, http.StatusOK
+}
+
+func (m *LoginJWTMiddlewareBuilder) checkAccountStatus(ctx *gin.Context, userId int64) int {
+	if m.accountStatus == nil {
+		return http.StatusOK
+	}
+	unusable, err := m.accountStatus.Unusable(ctx, userId)
+	if err != nil {
+		log.Println("查询账号状态失败", err)
+		return http.StatusInternalServerError
+	}
+	if unusable {
+		return http.StatusForbidden
+	}
+	return http.StatusOK
+}
+
+// 个人访问令牌没有会话，不需要校验ssid和续期
+func (m *LoginJWTMiddlewareBuilder) authenticatePersonalToken(ctx *gin.Context, rule AuthRule, tokenStr string) (*ijwt.UserJwtClaims, int) {
+	t, err := m.personalTokens.Verify(ctx, tokenStr)
+	if err != nil {
+		return nil, http.StatusUnauthorized
+	}
+	// 路由没有声明scope，或者令牌没有申请这个scope
+	if rule.Scope == "" || !t.HasScope(rule.Scope) {
+		return nil, http.StatusForbidden
+	}
+	return &ijwt.UserJwtClaims{
+		UserId:    t.UserId,
+		UserAgent: ctx.Request.UserAgent(),
+		TokenType: ijwt.PersonalTokenType,
+		Scopes:    t.Scopes,
+	}, http.StatusOK
+}
@@ -6,16 +6,12 @@ import (
 	"net/http"
 )
 
-var IgnorePaths []string
-
-// 对指定路由校验session
-func CheckLogin() gin.HandlerFunc {
+// 对指定路由校验session，公开的路由由rules决定
+func CheckLogin(rules *AuthRules) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// 注册、登录接口不需要校验session
-		for _, path := range IgnorePaths {
-			if ctx.Request.URL.Path == path {
-				return
-			}
+		if rules.Match(ctx.Request.Method, ctx.Request.URL.Path) == AuthPublic {
+			return
 		}
 
 		session := sessions.Default(ctx)
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"webook/internal/web/ijwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 查询用户是否有某个权限，每次请求都实时查询，角色变更下一次请求就生效
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userId int64, permission string) (bool, error)
+}
+
+type RBACMiddlewareBuilder struct {
+	checker PermissionChecker
+}
+
+func NewRBACMiddlewareBuilder(checker PermissionChecker) *RBACMiddlewareBuilder {
+	return &RBACMiddlewareBuilder{checker: checker}
+}
+
+// 挂在具体的路由或者路由分组上，必须放在登录中间件之后
+func (b *RBACMiddlewareBuilder) RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, ok := ctx.Get("Claims")
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		claims, ok := val.(*ijwt.UserJwtClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		allowed, err := b.checker.HasPermission(ctx, claims.UserId, permission)
+		if err != nil {
+			log.Println("查询用户权限失败", err)
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"webook/internal/web/ijwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 查询用户是否已经验证了邮箱，没有邮箱的账号也算通过
+type EmailVerifiedChecker interface {
+	EmailVerified(ctx context.Context, userId int64) (bool, error)
+}
+
+type EmailVerifiedMiddlewareBuilder struct {
+	checker EmailVerifiedChecker
+}
+
+func NewEmailVerifiedMiddlewareBuilder(checker EmailVerifiedChecker) *EmailVerifiedMiddlewareBuilder {
+	return &EmailVerifiedMiddlewareBuilder{checker: checker}
+}
+
+// 挂在验证邮箱前不允许做的操作上，必须放在登录中间件之后
+func (b *EmailVerifiedMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, ok := ctx.Get("Claims")
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		claims, ok := val.(*ijwt.UserJwtClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		verified, err := b.checker.EmailVerified(ctx, claims.UserId)
+		if err != nil {
+			log.Println("查询邮箱验证状态失败", err)
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,306 @@
+package web
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/dlclark/regexp2"
+	"github.com/gin-gonic/gin"
+)
+
+// 验证码业务
+const (
+	// 邮箱验证码重置密码
+	resetPasswordBiz = "reset_password"
+	// 短信验证码修改密码
+	changePasswordBiz = "change_password"
+)
+
+// 密码相关的路由
+type PasswordHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Forgot(ctx *gin.Context)
+	Reset(ctx *gin.Context)
+	Change(ctx *gin.Context)
+	SendChangeCode(ctx *gin.Context)
+}
+
+type passwordHandler struct {
+	emailReg    *regexp2.Regexp
+	passwordReg *regexp2.Regexp
+	userSvc     service.UserService
+	// 短信验证码
+	codeSvc service.CodeService
+	// 邮箱验证码
+	emailCodeSvc service.EmailCodeService
+	handler      ijwt.Handler
+	securitySvc  service.SecurityEventService
+	// 重置密码时作废个人访问令牌
+	tokenSvc service.PersonalTokenService
+}
+
+func NewPasswordHandler(userSvc service.UserService, codeSvc service.CodeService, emailCodeSvc service.EmailCodeService,
+	handler ijwt.Handler, securitySvc service.SecurityEventService, tokenSvc service.PersonalTokenService) PasswordHandler {
+	return &passwordHandler{
+		emailReg:     regexp2.MustCompile(emailRegPattern, regexp2.None),
+		passwordReg:  regexp2.MustCompile(passwordRegParttern, regexp2.None),
+		userSvc:      userSvc,
+		codeSvc:      codeSvc,
+		emailCodeSvc: emailCodeSvc,
+		handler:      handler,
+		securitySvc:  securitySvc,
+		tokenSvc:     tokenSvc,
+	}
+}
+
+func (p *passwordHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	pg := server.Group("/user/password")
+	// 忘记密码的时候肯定没登录
+	rules.Routes(pg, middleware.AuthPublic).
+		POST("/forgot", p.Forgot).
+		POST("/reset", p.Reset)
+	// 修改密码需要登录，个人访问令牌不能改
+	rules.Routes(pg, middleware.AuthRequired).
+		POST("", p.Change).
+		POST("/code/send", p.SendChangeCode)
+}
+
+// 给注册邮箱发重置密码的验证码
+func (p *passwordHandler) Forgot(ctx *gin.Context) {
+	type forgotReq struct {
+		Email string `json:"email"`
+	}
+	var req forgotReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	isValid, err := p.emailReg.MatchString(req.Email)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isValid {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "邮箱格式不正确"})
+		return
+	}
+
+	_, err = p.userSvc.FindByEmail(ctx, req.Email)
+	// 邮箱没注册也返回成功，不让别人借此探测哪些邮箱注册过
+	if err == service.ErrUserNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	err = p.emailCodeSvc.Send(ctx, resetPasswordBiz, req.Email)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+	case service.ErrCodeSendTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送过于频繁"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 校验验证码，设置新密码，并退出所有设备
+func (p *passwordHandler) Reset(ctx *gin.Context) {
+	type resetReq struct {
+		Email      string `json:"email"`
+		Code       string `json:"code"`
+		Password   string `json:"password"`
+		Repassword string `json:"repassword"`
+	}
+	var req resetReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	isValid, err := p.passwordReg.MatchString(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isValid {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码必须大于8位，包含数字、特殊字符"})
+		return
+	}
+	if req.Repassword != req.Password {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码与确认密码不一致！"})
+		return
+	}
+
+	ok, err := p.emailCodeSvc.Verify(ctx, resetPasswordBiz, req.Email, req.Code)
+	if err == service.ErrCodeVerifyTooMany {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	}
+
+	u, err := p.userSvc.ResetPassword(ctx, req.Email, req.Password)
+	if err == service.ErrUserNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	// 密码可能已经泄漏了，所有设备都要重新登录
+	if err = p.handler.RevokeAllSessions(ctx, u.Id); err != nil {
+		log.Println("重置密码后退出所有会话失败", err)
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "密码已重置，但退出其他设备失败，请登录后手动退出"})
+		return
+	}
+	// 个人访问令牌不走会话，要单独作废
+	if _, err = p.tokenSvc.RevokeAll(ctx, u.Id); err != nil {
+		log.Println("重置密码后作废个人访问令牌失败", err)
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "密码已重置，但作废个人访问令牌失败，请登录后手动删除"})
+		return
+	}
+	er := p.securitySvc.Record(ctx, domain.SecurityEvent{
+		UserId:    u.Id,
+		Type:      domain.SecurityEventPasswordReset,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if er != nil {
+		log.Println("记录安全事件失败", er)
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "密码已重置，请重新登录"})
+}
+
+// 给绑定的手机号发修改密码的验证码，忘了当前密码的时候用
+func (p *passwordHandler) SendChangeCode(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	u, err := p.userSvc.FindOne(ctx, claims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if u.Phone == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "没有绑定手机号，请使用当前密码修改"})
+		return
+	}
+	err = p.codeSvc.Send(ctx, changePasswordBiz, u.Phone)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+	case service.ErrCodeSendTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送过于频繁"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 修改密码，需要当前密码或者刚收到的短信验证码，改完踢掉其他设备，保留当前设备
+func (p *passwordHandler) Change(ctx *gin.Context) {
+	type changeReq struct {
+		OldPassword string `json:"oldPassword"`
+		// 填了验证码就不校验当前密码
+		Code       string `json:"code"`
+		Password   string `json:"password"`
+		Repassword string `json:"repassword"`
+	}
+	var req changeReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	isValid, err := p.passwordReg.MatchString(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isValid {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码必须大于8位，包含数字、特殊字符"})
+		return
+	}
+	if req.Repassword != req.Password {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码与确认密码不一致！"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	if req.Code != "" {
+		err = p.changeByCode(ctx, claims.UserId, req.Code, req.Password)
+	} else {
+		err = p.userSvc.ChangePassword(ctx, claims.UserId, req.OldPassword, req.Password)
+	}
+	switch err {
+	case nil:
+	case service.ErrPasswordWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "当前密码错误"})
+		return
+	case errCodeWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	case service.ErrCodeVerifyTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+
+	if err = p.handler.RevokeOtherSessions(ctx, claims.UserId, claims.Family); err != nil {
+		log.Println("修改密码后退出其他会话失败", err)
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "密码已修改，但退出其他设备失败，请手动退出"})
+		return
+	}
+	er := p.securitySvc.Record(ctx, domain.SecurityEvent{
+		UserId:    claims.UserId,
+		Type:      domain.SecurityEventPasswordChange,
+		Ssid:      claims.Ssid,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if er != nil {
+		log.Println("记录安全事件失败", er)
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "密码已修改"})
+}
+
+var errCodeWrong = errors.New("验证码有误")
+
+func (p *passwordHandler) changeByCode(ctx *gin.Context, userId int64, code string, password string) error {
+	u, err := p.userSvc.FindOne(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if u.Phone == "" {
+		return errCodeWrong
+	}
+	ok, err := p.codeSvc.Verify(ctx, changePasswordBiz, u.Phone, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errCodeWrong
+	}
+	return p.userSvc.SetPassword(ctx, userId, password)
+}
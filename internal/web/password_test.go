@@ -0,0 +1,284 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/web/ijwt"
+	ijwtmocks "webook/internal/web/ijwt/mocks"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPasswordHandler_Forgot(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService)
+		body string
+
+		wantResponse Result
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{Id: 1}, nil)
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Send(gomock.Any(), resetPasswordBiz, "123@qq.com").Return(nil)
+				return userSvc, codeSvc
+			},
+			body:         `{"email":"123@qq.com"}`,
+			wantResponse: Result{Code: 0, Msg: "验证码已发送"},
+		},
+		{
+			name: "邮箱没注册也返回成功，但不发送",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{}, service.ErrUserNotFound)
+				return userSvc, svcmocks.NewMockEmailCodeService(ctrl)
+			},
+			body:         `{"email":"123@qq.com"}`,
+			wantResponse: Result{Code: 0, Msg: "验证码已发送"},
+		},
+		{
+			name: "发送太频繁",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{Id: 1}, nil)
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Send(gomock.Any(), resetPasswordBiz, "123@qq.com").Return(service.ErrCodeSendTooMany)
+				return userSvc, codeSvc
+			},
+			body:         `{"email":"123@qq.com"}`,
+			wantResponse: Result{Code: 4, Msg: "验证码发送过于频繁"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc := tc.mock(ctrl)
+			h := NewPasswordHandler(userSvc, svcmocks.NewMockCodeService(ctrl), codeSvc, ijwtmocks.NewMockHandler(ctrl),
+				svcmocks.NewMockSecurityEventService(ctrl), svcmocks.NewMockPersonalTokenService(ctrl))
+			assert.Equal(t, servePasswordRequest(t, h, "/user/password/forgot", tc.body), tc.wantResponse)
+		})
+	}
+}
+
+func TestPasswordHandler_Reset(t *testing.T) {
+	const body = `{"email":"123@qq.com","code":"123456","password":"123456aA!","repassword":"123456aA!"}`
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService)
+		// 为nil表示不会作废个人访问令牌
+		revokeTokens func() error
+		body         string
+
+		wantResponse Result
+	}{
+		{
+			name: "重置成功并退出所有设备",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), resetPasswordBiz, "123@qq.com", "123456").Return(true, nil)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "123@qq.com", "123456aA!").Return(domain.User{Id: 1}, nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeAllSessions(gomock.Any(), int64(1)).Return(nil)
+				securitySvc := svcmocks.NewMockSecurityEventService(ctrl)
+				securitySvc.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				return userSvc, codeSvc, handler, securitySvc
+			},
+			// 个人访问令牌也要作废
+			revokeTokens: func() error { return nil },
+			body:         body,
+			wantResponse: Result{Code: 0, Msg: "密码已重置，请重新登录"},
+		},
+		{
+			name: "密码格式不对",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				return nil, nil, nil, nil
+			},
+			body:         `{"email":"123@qq.com","code":"123456","password":"1234","repassword":"1234"}`,
+			wantResponse: Result{Code: 4, Msg: "密码必须大于8位，包含数字、特殊字符"},
+		},
+		{
+			name: "验证码错误",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), resetPasswordBiz, "123@qq.com", "123456").Return(false, nil)
+				return svcmocks.NewMockUserService(ctrl), codeSvc, ijwtmocks.NewMockHandler(ctrl), nil
+			},
+			body:         body,
+			wantResponse: Result{Code: 4, Msg: "验证码有误"},
+		},
+		{
+			name: "验证次数太多",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), resetPasswordBiz, "123@qq.com", "123456").Return(false, service.ErrCodeVerifyTooMany)
+				return svcmocks.NewMockUserService(ctrl), codeSvc, ijwtmocks.NewMockHandler(ctrl), nil
+			},
+			body:         body,
+			wantResponse: Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"},
+		},
+		{
+			name: "退出会话失败",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), resetPasswordBiz, "123@qq.com", "123456").Return(true, nil)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "123@qq.com", "123456aA!").Return(domain.User{Id: 1}, nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeAllSessions(gomock.Any(), int64(1)).Return(errors.New("redis error"))
+				return userSvc, codeSvc, handler, nil
+			},
+			body:         body,
+			wantResponse: Result{Code: 5, Msg: "密码已重置，但退出其他设备失败，请登录后手动退出"},
+		},
+		{
+			name: "作废个人访问令牌失败",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService, ijwt.Handler, service.SecurityEventService) {
+				codeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), resetPasswordBiz, "123@qq.com", "123456").Return(true, nil)
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "123@qq.com", "123456aA!").Return(domain.User{Id: 1}, nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeAllSessions(gomock.Any(), int64(1)).Return(nil)
+				return userSvc, codeSvc, handler, nil
+			},
+			revokeTokens: func() error { return errors.New("db error") },
+			body:         body,
+			wantResponse: Result{Code: 5, Msg: "密码已重置，但作废个人访问令牌失败，请登录后手动删除"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc, handler, securitySvc := tc.mock(ctrl)
+			tokenSvc := svcmocks.NewMockPersonalTokenService(ctrl)
+			if tc.revokeTokens != nil {
+				tokenSvc.EXPECT().RevokeAll(gomock.Any(), int64(1)).Return(int64(2), tc.revokeTokens())
+			}
+			h := NewPasswordHandler(userSvc, svcmocks.NewMockCodeService(ctrl), codeSvc, handler, securitySvc, tokenSvc)
+			assert.Equal(t, servePasswordRequest(t, h, "/user/password/reset", tc.body), tc.wantResponse)
+		})
+	}
+}
+
+func TestPasswordHandler_Change(t *testing.T) {
+	claims := &ijwt.UserJwtClaims{UserId: 1, Ssid: "ssid-1", Family: "family-1"}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService)
+		body string
+
+		wantResponse Result
+	}{
+		{
+			name: "用当前密码修改，保留当前会话",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ChangePassword(gomock.Any(), int64(1), "old123aA!", "123456aA!").Return(nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeOtherSessions(gomock.Any(), int64(1), "family-1").Return(nil)
+				securitySvc := svcmocks.NewMockSecurityEventService(ctrl)
+				securitySvc.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				return userSvc, svcmocks.NewMockCodeService(ctrl), handler, securitySvc
+			},
+			body:         `{"oldPassword":"old123aA!","password":"123456aA!","repassword":"123456aA!"}`,
+			wantResponse: Result{Code: 0, Msg: "密码已修改"},
+		},
+		{
+			name: "当前密码错误",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ChangePassword(gomock.Any(), int64(1), "wrong", "123456aA!").Return(service.ErrPasswordWrong)
+				return userSvc, svcmocks.NewMockCodeService(ctrl), ijwtmocks.NewMockHandler(ctrl), nil
+			},
+			body:         `{"oldPassword":"wrong","password":"123456aA!","repassword":"123456aA!"}`,
+			wantResponse: Result{Code: 4, Msg: "当前密码错误"},
+		},
+		{
+			name: "用短信验证码修改",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindOne(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Phone: "13800000000"}, nil)
+				userSvc.EXPECT().SetPassword(gomock.Any(), int64(1), "123456aA!").Return(nil)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), changePasswordBiz, "13800000000", "123456").Return(true, nil)
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().RevokeOtherSessions(gomock.Any(), int64(1), "family-1").Return(nil)
+				securitySvc := svcmocks.NewMockSecurityEventService(ctrl)
+				securitySvc.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+				return userSvc, codeSvc, handler, securitySvc
+			},
+			body:         `{"code":"123456","password":"123456aA!","repassword":"123456aA!"}`,
+			wantResponse: Result{Code: 0, Msg: "密码已修改"},
+		},
+		{
+			name: "短信验证码错误",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().FindOne(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Phone: "13800000000"}, nil)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), changePasswordBiz, "13800000000", "000000").Return(false, nil)
+				return userSvc, codeSvc, ijwtmocks.NewMockHandler(ctrl), nil
+			},
+			body:         `{"code":"000000","password":"123456aA!","repassword":"123456aA!"}`,
+			wantResponse: Result{Code: 4, Msg: "验证码有误"},
+		},
+		{
+			name: "新密码不符合规则",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler, service.SecurityEventService) {
+				return nil, nil, nil, nil
+			},
+			body:         `{"oldPassword":"old123aA!","password":"123","repassword":"123"}`,
+			wantResponse: Result{Code: 4, Msg: "密码必须大于8位，包含数字、特殊字符"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc, handler, securitySvc := tc.mock(ctrl)
+			h := NewPasswordHandler(userSvc, codeSvc, nil, handler, securitySvc, svcmocks.NewMockPersonalTokenService(ctrl))
+			// 模拟登录中间件
+			login := func(ctx *gin.Context) { ctx.Set("Claims", claims) }
+			assert.Equal(t, servePasswordRequest(t, h, "/user/password", tc.body, login), tc.wantResponse)
+		})
+	}
+}
+
+func servePasswordRequest(t *testing.T, h PasswordHandler, path string, body string, middlewares ...gin.HandlerFunc) Result {
+	server := gin.Default()
+	server.Use(middlewares...)
+	h.RegisterRoutes(server, middleware.NewAuthRules())
+	req, err := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	var res Result
+	if err = json.NewDecoder(response.Body).Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	return res
+}
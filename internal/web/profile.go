@@ -0,0 +1,76 @@
+package web
+
+import (
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 用户名和公开主页
+type ProfileHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	ClaimHandle(ctx *gin.Context)
+	PublicProfile(ctx *gin.Context)
+}
+
+type profileHandler struct {
+	svc service.ProfileService
+	// 没验证邮箱的账号不能改资料
+	verified *middleware.EmailVerifiedMiddlewareBuilder
+}
+
+func NewProfileHandler(svc service.ProfileService, verified *middleware.EmailVerifiedMiddlewareBuilder) ProfileHandler {
+	return &profileHandler{svc: svc, verified: verified}
+}
+
+func (p *profileHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	rules.Routes(server.Group("/user"), middleware.AuthRequired).Scope(domain.ScopeProfileWrite).
+		POST("/handle", p.verified.Build(), p.ClaimHandle)
+	rules.Routes(server.Group("/u"), middleware.AuthPublic).
+		GET("/:handle", p.PublicProfile)
+}
+
+func (p *profileHandler) ClaimHandle(ctx *gin.Context) {
+	type Req struct {
+		Handle string `json:"handle"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	handle, err := p.svc.ClaimHandle(ctx, claims.UserId, req.Handle)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: handle})
+	case service.ErrHandleInvalid:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户名只能包含小写字母、数字和下划线，字母开头，3到20位"})
+	case service.ErrHandleReserved, service.ErrHandleTaken:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户名已被占用"})
+	case service.ErrHandleTooSoon:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户名修改太频繁，请稍后再试"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 公开主页，只返回PublicProfile里的字段
+func (p *profileHandler) PublicProfile(ctx *gin.Context) {
+	profile, err := p.svc.FindByHandle(ctx, ctx.Param("handle"))
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: profile})
+	case service.ErrUserNotFound:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户不存在"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
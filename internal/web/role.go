@@ -0,0 +1,92 @@
+package web
+
+import (
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理员给用户分配角色
+type RoleHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Assign(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+	UserAuthz(ctx *gin.Context)
+}
+
+type roleHandler struct {
+	svc  service.RoleService
+	rbac *middleware.RBACMiddlewareBuilder
+}
+
+func NewRoleHandler(svc service.RoleService, rbac *middleware.RBACMiddlewareBuilder) RoleHandler {
+	return &roleHandler{svc: svc, rbac: rbac}
+}
+
+func (h *roleHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	rg := server.Group("/admin/roles")
+	rg.Use(h.rbac.RequirePermission(domain.PermissionRoleManage))
+	rules.Routes(rg, middleware.AuthRequired).
+		POST("/assign", h.Assign).
+		POST("/revoke", h.Revoke).
+		POST("/user", h.UserAuthz)
+}
+
+type userRoleReq struct {
+	UserId int64  `json:"userId"`
+	Role   string `json:"role"`
+}
+
+func (h *roleHandler) Assign(ctx *gin.Context) {
+	var req userRoleReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := h.svc.Assign(ctx, req.UserId, req.Role)
+	if err == service.ErrRoleNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "角色不存在"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
+
+func (h *roleHandler) Revoke(ctx *gin.Context) {
+	var req userRoleReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := h.svc.Revoke(ctx, req.UserId, req.Role)
+	if err == service.ErrRoleNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "角色不存在"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
+
+// 查看某个用户的角色和权限
+func (h *roleHandler) UserAuthz(ctx *gin.Context) {
+	var req userRoleReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	authz, err := h.svc.Authz(ctx, req.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: authz})
+}
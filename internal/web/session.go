@@ -0,0 +1,102 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 多设备的会话管理
+type SessionHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	List(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+	RevokeOthers(ctx *gin.Context)
+}
+
+type sessionHandler struct {
+	handler ijwt.Handler
+}
+
+func NewSessionHandler(handler ijwt.Handler) SessionHandler {
+	return &sessionHandler{handler: handler}
+}
+
+func (s *sessionHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	sg := server.Group("/user/sessions")
+	rules.Routes(sg, middleware.AuthRequired).
+		POST("", s.List).
+		POST("/revoke", s.Revoke).
+		POST("/revoke_others", s.RevokeOthers)
+}
+
+// 我的所有登录设备
+func (s *sessionHandler) List(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	sessions, err := s.handler.ListSessions(ctx, claims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == claims.Family
+	}
+	// 最近活跃的排前面
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt > sessions[j].LastSeenAt
+	})
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: sessions})
+}
+
+// 踢掉某个设备
+func (s *sessionHandler) Revoke(ctx *gin.Context) {
+	type revokeReq struct {
+		Id string `json:"id"`
+	}
+	var req revokeReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := s.handler.RevokeSession(ctx, claims.UserId, req.Id)
+	if err == ijwt.ErrSessionNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "会话不存在"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
+
+// 退出其他所有设备，保留当前设备
+func (s *sessionHandler) RevokeOthers(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	// 老版本的token没有family，无法区分当前会话
+	if claims.Family == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请重新登录后再试"})
+		return
+	}
+	if err := s.handler.RevokeOtherSessions(ctx, claims.UserId, claims.Family); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"net/http"
+	"time"
+	"unicode/utf8"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 个人访问令牌的管理，只能用登录态操作，令牌本身不能管理令牌
+type PersonalTokenHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	List(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+}
+
+type personalTokenHandler struct {
+	svc      service.PersonalTokenService
+	verified *middleware.EmailVerifiedMiddlewareBuilder
+}
+
+func NewPersonalTokenHandler(svc service.PersonalTokenService, verified *middleware.EmailVerifiedMiddlewareBuilder) PersonalTokenHandler {
+	return &personalTokenHandler{svc: svc, verified: verified}
+}
+
+func (h *personalTokenHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	tg := server.Group("/user/tokens")
+	rules.Routes(tg, middleware.AuthRequired).
+		POST("", h.List).
+		// 验证邮箱之前不能创建令牌
+		POST("/create", h.verified.Build(), h.Create).
+		POST("/revoke", h.Revoke)
+}
+
+func (h *personalTokenHandler) List(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	tokens, err := h.svc.List(ctx, claims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: tokens})
+}
+
+func (h *personalTokenHandler) Create(ctx *gin.Context) {
+	type createReq struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+		// 有效天数，0表示永不过期
+		ExpireDays int `json:"expireDays"`
+	}
+	var req createReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > 64 {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "令牌名称不能为空，且不超过64个字符"})
+		return
+	}
+	if len(req.Scopes) == 0 {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请至少选择一个授权范围"})
+		return
+	}
+	if req.ExpireDays < 0 || req.ExpireDays > 365 {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "有效期不能超过365天"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	var expireAt int64
+	if req.ExpireDays > 0 {
+		expireAt = time.Now().AddDate(0, 0, req.ExpireDays).UnixMilli()
+	}
+	token, t, err := h.svc.Create(ctx, claims.UserId, req.Name, req.Scopes, expireAt)
+	if err == service.ErrPersonalTokenScope {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "不支持的授权范围"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	// 明文只返回这一次
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: map[string]any{
+		"token": token,
+		"info":  t,
+	}})
+}
+
+func (h *personalTokenHandler) Revoke(ctx *gin.Context) {
+	type revokeReq struct {
+		Id int64 `json:"id"`
+	}
+	var req revokeReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := h.svc.Revoke(ctx, claims.UserId, req.Id)
+	if err == service.ErrPersonalTokenNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "令牌不存在"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok"})
+}
@@ -0,0 +1,303 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 两步验证的绑定、关闭，以及登录的第二步
+type TwoFactorHandler interface {
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
+	Enroll(ctx *gin.Context)
+	Confirm(ctx *gin.Context)
+	Disable(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
+	Login(ctx *gin.Context)
+}
+
+type twoFactorHandler struct {
+	svc         service.TwoFactorService
+	handler     ijwt.Handler
+	securitySvc service.SecurityEventService
+	logins      service.LoginHistoryService
+	userSvc     service.UserService
+	// 验证码猜错和密码错误一样计入账号的失败次数
+	guard service.LoginGuardService
+}
+
+func NewTwoFactorHandler(svc service.TwoFactorService, handler ijwt.Handler,
+	securitySvc service.SecurityEventService, logins service.LoginHistoryService,
+	userSvc service.UserService, guard service.LoginGuardService) TwoFactorHandler {
+	return &twoFactorHandler{svc: svc, handler: handler, securitySvc: securitySvc, logins: logins,
+		userSvc: userSvc, guard: guard}
+}
+
+func (t *twoFactorHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
+	// 第一步登录拿到的凭证换token，这时候还没有token
+	rules.Routes(server.Group("/user/login"), middleware.AuthPublic).
+		POST("/2fa", t.Login)
+	// 个人访问令牌不能管理两步验证
+	rules.Routes(server.Group("/user/2fa"), middleware.AuthRequired).
+		POST("/enroll", t.Enroll).
+		POST("/confirm", t.Confirm).
+		POST("/disable", t.Disable).
+		POST("/recovery_codes", t.RegenerateRecoveryCodes)
+}
+
+// 生成密钥和二维码内容，确认之前登录不受影响
+func (t *twoFactorHandler) Enroll(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	enrollment, err := t.svc.Enroll(ctx, claims.UserId)
+	if err == service.ErrTwoFactorEnabled {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "两步验证已经启用"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: enrollment})
+}
+
+// 输入认证器上的验证码确认绑定，返回恢复码
+func (t *twoFactorHandler) Confirm(ctx *gin.Context) {
+	type confirmReq struct {
+		Code string `json:"code"`
+	}
+	var req confirmReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	codes, err := t.svc.Confirm(ctx, claims.UserId, req.Code)
+	switch err {
+	case nil:
+	case service.ErrTwoFactorEnabled:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "两步验证已经启用"})
+		return
+	case service.ErrTwoFactorNotEnabled:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请先生成密钥"})
+		return
+	case service.ErrTwoFactorCodeWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	t.record(ctx, claims, domain.SecurityEventTwoFactorEnable)
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "两步验证已启用，请妥善保存恢复码", Data: codes})
+}
+
+// 关闭两步验证，需要当前密码和验证码
+func (t *twoFactorHandler) Disable(ctx *gin.Context) {
+	type disableReq struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	var req disableReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err := t.svc.Disable(ctx, claims.UserId, req.Password, req.Code)
+	switch err {
+	case nil:
+	case service.ErrPasswordWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "当前密码错误"})
+		return
+	case service.ErrTwoFactorNotEnabled:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "没有启用两步验证"})
+		return
+	case service.ErrTwoFactorCodeWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	t.record(ctx, claims, domain.SecurityEventTwoFactorDisable)
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "两步验证已关闭"})
+}
+
+// 重新生成恢复码，旧的全部作废
+func (t *twoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	type regenerateReq struct {
+		Code string `json:"code"`
+	}
+	var req regenerateReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	codes, err := t.svc.RegenerateRecoveryCodes(ctx, claims.UserId, req.Code)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "ok", Data: codes})
+	case service.ErrTwoFactorNotEnabled:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "没有启用两步验证"})
+	case service.ErrTwoFactorCodeWrong:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// 登录的第二步，用登录凭证和验证码（或恢复码）换长短token
+func (t *twoFactorHandler) Login(ctx *gin.Context) {
+	type loginReq struct {
+		Challenge string `json:"challenge"`
+		Code      string `json:"code"`
+	}
+	var req loginReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	userId, err := t.svc.VerifyChallenge(ctx, req.Challenge, req.Code)
+	if err != nil {
+		recordLogin(ctx, t.logins, domain.LoginRecord{UserId: userId, Method: domain.LoginMethodTwoFactor,
+			Result: loginResultOf(err)})
+	}
+	switch err {
+	case nil:
+	case service.ErrTwoFactorCodeWrong:
+		t.loginFailed(ctx, userId)
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	case service.ErrChallengeVerifyTooMany:
+		t.loginFailed(ctx, userId)
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录已失效，请重新登录"})
+		return
+	// 中途关闭了两步验证也要重新登录
+	case service.ErrChallengeInvalid, service.ErrTwoFactorNotEnabled:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录已失效，请重新登录"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if res := restoreAccount(ctx, t.userSvc, userId); res != "" {
+		recordLogin(ctx, t.logins, domain.LoginRecord{UserId: userId, Method: domain.LoginMethodTwoFactor, Result: res})
+		return
+	}
+	if err = t.handler.SetLoginToken(ctx, userId); err != nil {
+		recordLogin(ctx, t.logins, domain.LoginRecord{UserId: userId, Method: domain.LoginMethodTwoFactor,
+			Result: domain.LoginResultError})
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if account := t.account(ctx, userId); account != "" {
+		loginSucceeded(ctx, t.guard, account)
+	}
+	recordLogin(ctx, t.logins, domain.LoginRecord{UserId: userId, Method: domain.LoginMethodTwoFactor,
+		Result: domain.LoginResultSuccess})
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "登陆成功"})
+}
+
+// 拿到密码的人可以不停地申请新凭证，每个凭证都能试几次，所以猜错要记到账号上
+func (t *twoFactorHandler) loginFailed(ctx *gin.Context, userId int64) {
+	if account := t.account(ctx, userId); account != "" {
+		loginFailed(ctx, t.guard, account)
+	}
+}
+
+// 失败次数按登录邮箱统计，没有邮箱的账号不能用密码登录，不用统计
+func (t *twoFactorHandler) account(ctx *gin.Context, userId int64) string {
+	if userId == 0 {
+		return ""
+	}
+	u, err := t.userSvc.FindOne(ctx, userId)
+	if err != nil {
+		log.Println("查询两步验证的用户失败", err)
+		return ""
+	}
+	return u.Email
+}
+
+func (t *twoFactorHandler) record(ctx *gin.Context, claims *ijwt.UserJwtClaims, typ string) {
+	err := t.securitySvc.Record(ctx, domain.SecurityEvent{
+		UserId:    claims.UserId,
+		Type:      typ,
+		Ssid:      claims.Ssid,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		log.Println("记录安全事件失败", err)
+	}
+}
+
+// 第一步登录成功后调用，启用了两步验证的账号只返回登录凭证，没启用的直接颁发长短token
+// 返回这次登录的结果，用于记录登录历史
+func finishLogin(ctx *gin.Context, twoFactor service.TwoFactorService, handler ijwt.Handler,
+	users service.UserService, userId int64) string {
+	enabled, err := twoFactor.Enabled(ctx, userId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return domain.LoginResultError
+	}
+	if enabled {
+		challenge, err := twoFactor.Challenge(ctx, userId)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+			return domain.LoginResultError
+		}
+		type challengeResp struct {
+			TwoFactorRequired bool   `json:"twoFactorRequired"`
+			Challenge         string `json:"challenge"`
+		}
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "请输入两步验证码",
+			Data: challengeResp{TwoFactorRequired: true, Challenge: challenge}})
+		return domain.LoginResultTwoFactorRequired
+	}
+	if res := restoreAccount(ctx, users, userId); res != "" {
+		return res
+	}
+	if err = handler.SetLoginToken(ctx, userId); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return domain.LoginResultError
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "登陆成功"})
+	return domain.LoginResultSuccess
+}
+
+// 所有验证都通过了才恢复正在注销的账号，失败时已经写好了响应，返回登录结果，成功返回空
+func restoreAccount(ctx *gin.Context, users service.UserService, userId int64) string {
+	err := users.Restore(ctx, userId)
+	switch err {
+	case nil:
+		return ""
+	case service.ErrUserDeleted:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已注销"})
+		return domain.LoginResultDeleted
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return domain.LoginResultError
+	}
+}
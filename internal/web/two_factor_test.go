@@ -0,0 +1,116 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/internal/web/ijwt"
+	ijwtmocks "webook/internal/web/ijwt/mocks"
+	"webook/internal/web/middleware"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestTwoFactorHandler_Login(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService)
+		// 为nil表示不会走到恢复账号
+		restore func() error
+
+		wantResponse Result
+	}{
+		{
+			name: "验证通过，清掉失败次数",
+			mock: func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService) {
+				svc := svcmocks.NewMockTwoFactorService(ctrl)
+				svc.EXPECT().VerifyChallenge(gomock.Any(), "c", "123456").Return(int64(1), nil)
+				guard := svcmocks.NewMockLoginGuardService(ctrl)
+				guard.EXPECT().Succeeded(gomock.Any(), "123@qq.com").Return(nil)
+				return svc, newTestJWTHandler(ctrl), guard
+			},
+			restore:      func() error { return nil },
+			wantResponse: Result{Code: 0, Msg: "登陆成功"},
+		},
+		{
+			name: "验证通过，但是已经过了注销的恢复期",
+			mock: func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService) {
+				svc := svcmocks.NewMockTwoFactorService(ctrl)
+				svc.EXPECT().VerifyChallenge(gomock.Any(), "c", "123456").Return(int64(1), nil)
+				// 不颁发token，也不清失败次数
+				return svc, ijwtmocks.NewMockHandler(ctrl), svcmocks.NewMockLoginGuardService(ctrl)
+			},
+			restore:      func() error { return service.ErrUserDeleted },
+			wantResponse: Result{Code: 4, Msg: "账号已注销"},
+		},
+		{
+			name: "验证码错误，记到账号上",
+			mock: func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService) {
+				svc := svcmocks.NewMockTwoFactorService(ctrl)
+				svc.EXPECT().VerifyChallenge(gomock.Any(), "c", "123456").Return(int64(1), service.ErrTwoFactorCodeWrong)
+				guard := svcmocks.NewMockLoginGuardService(ctrl)
+				guard.EXPECT().Failed(gomock.Any(), "123@qq.com", gomock.Any()).Return(nil)
+				return svc, ijwtmocks.NewMockHandler(ctrl), guard
+			},
+			wantResponse: Result{Code: 4, Msg: "验证码有误"},
+		},
+		{
+			name: "试太多次，也记到账号上",
+			mock: func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService) {
+				svc := svcmocks.NewMockTwoFactorService(ctrl)
+				svc.EXPECT().VerifyChallenge(gomock.Any(), "c", "123456").Return(int64(1), service.ErrChallengeVerifyTooMany)
+				guard := svcmocks.NewMockLoginGuardService(ctrl)
+				guard.EXPECT().Failed(gomock.Any(), "123@qq.com", gomock.Any()).Return(nil)
+				return svc, ijwtmocks.NewMockHandler(ctrl), guard
+			},
+			wantResponse: Result{Code: 4, Msg: "登录已失效，请重新登录"},
+		},
+		{
+			name: "凭证过期，不知道是谁",
+			mock: func(ctrl *gomock.Controller) (service.TwoFactorService, ijwt.Handler, service.LoginGuardService) {
+				svc := svcmocks.NewMockTwoFactorService(ctrl)
+				svc.EXPECT().VerifyChallenge(gomock.Any(), "c", "123456").Return(int64(0), service.ErrChallengeInvalid)
+				return svc, ijwtmocks.NewMockHandler(ctrl), svcmocks.NewMockLoginGuardService(ctrl)
+			},
+			wantResponse: Result{Code: 4, Msg: "登录已失效，请重新登录"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, handler, guard := tc.mock(ctrl)
+			userSvc := svcmocks.NewMockUserService(ctrl)
+			userSvc.EXPECT().FindOne(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Email: "123@qq.com"}, nil).AnyTimes()
+			if tc.restore != nil {
+				userSvc.EXPECT().Restore(gomock.Any(), int64(1)).Return(tc.restore())
+			}
+			h := NewTwoFactorHandler(svc, handler, nil, newTestLoginHistory(ctrl), userSvc, guard)
+
+			server := gin.Default()
+			h.RegisterRoutes(server, middleware.NewAuthRules())
+			body := bytes.NewBufferString(`{"challenge":"c","code":"123456"}`)
+			req, err := http.NewRequest(http.MethodPost, "/user/login/2fa", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, req)
+
+			var respBody Result
+			if err = json.NewDecoder(response.Body).Decode(&respBody); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			assert.Equal(t, respBody, tc.wantResponse)
+		})
+	}
+}
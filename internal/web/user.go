@@ -1,23 +1,23 @@
 package web
 
 import (
+	"log"
 	"net/http"
 	"time"
 	"unicode/utf8"
 	"webook/internal/domain"
 	"webook/internal/service"
 	ijwt "webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
 
 	"github.com/dlclark/regexp2"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 var ErrUserLogout = ijwt.ErrUserLogout
-var RefreshTokenKey = ijwt.RefreshTokenKey
 
 type UserHandler interface {
-	RegisterRoutes(server *gin.Engine)
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
 	Signup(ctx *gin.Context)
 	LoginJWT(ctx *gin.Context)
 	Logout(ctx *gin.Context)
@@ -26,6 +26,8 @@ type UserHandler interface {
 	SignUpCode(ctx *gin.Context)
 	LoginByCode(ctx *gin.Context)
 	RefreshToken(ctx *gin.Context)
+	VerifyEmail(ctx *gin.Context)
+	SendVerifyEmail(ctx *gin.Context)
 }
 
 // 定义user模块的所有路由
@@ -34,44 +36,69 @@ type userHandler struct {
 	passwordReg *regexp2.Regexp
 	srv         service.UserService
 	codeService service.CodeService
-	handler     ijwt.Handler
+	// 邮箱验证码
+	emailCodeSvc service.EmailCodeService
+	handler      ijwt.Handler
+	securitySvc  service.SecurityEventService
+	// 没验证邮箱的账号不能访问的路由挂这个中间件
+	verified *middleware.EmailVerifiedMiddlewareBuilder
+	// 密码登录防暴力破解
+	guard service.LoginGuardService
+	// 两步验证
+	twoFactor service.TwoFactorService
+	// 登录历史
+	logins service.LoginHistoryService
 }
 
-func NewUserHandler(srv service.UserService, codeService service.CodeService, handler ijwt.Handler) UserHandler {
-	// controller入参正则pattern
-	const (
-		emailRegPattern     = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-		passwordRegParttern = `^(?=.*[a-z])(?=.*[A-Z])(?=.*[!@#$%^&*]).{8,60}$`
-	)
+// controller入参正则pattern
+const (
+	emailRegPattern     = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
+	passwordRegParttern = `^(?=.*[a-z])(?=.*[A-Z])(?=.*[!@#$%^&*]).{8,60}$`
+)
+
+// 注册邮箱验证码的业务
+const verifyEmailBiz = "verify_email"
 
+func NewUserHandler(srv service.UserService, codeService service.CodeService, emailCodeSvc service.EmailCodeService,
+	handler ijwt.Handler, securitySvc service.SecurityEventService, verified *middleware.EmailVerifiedMiddlewareBuilder,
+	guard service.LoginGuardService, twoFactor service.TwoFactorService, logins service.LoginHistoryService) UserHandler {
 	emailReg, passwordReg := regexp2.MustCompile(emailRegPattern, regexp2.None), regexp2.MustCompile(passwordRegParttern, regexp2.None)
 
 	u := &userHandler{
-		emailReg:    emailReg,
-		passwordReg: passwordReg,
-		srv:         srv,
-		codeService: codeService,
-		handler:     handler,
+		emailReg:     emailReg,
+		passwordReg:  passwordReg,
+		srv:          srv,
+		codeService:  codeService,
+		emailCodeSvc: emailCodeSvc,
+		handler:      handler,
+		securitySvc:  securitySvc,
+		verified:     verified,
+		guard:        guard,
+		twoFactor:    twoFactor,
+		logins:       logins,
 	}
 	return u
 }
 
-// 统一注册user的路由
-func (u *userHandler) RegisterRoutes(server *gin.Engine) {
+// 统一注册user的路由，同时声明每个路由的鉴权级别
+func (u *userHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
 	//// 统一前缀
 	ug := server.Group("/user")
-	ug.POST("/signup", u.Signup)
-	//ug.POST("/login", u.Login)
-	ug.POST("/login", u.LoginJWT)
-	ug.POST("/logout", u.Logout)
-	ug.POST("/edit", u.Edit)
-	ug.POST("/profile", u.Profile)
-	ug.POST("/signup/code/send", u.SignUpCode)
-	ug.POST("/login/code", u.LoginByCode)
-	ug.POST("/refresh_token", u.RefreshToken)
-	//server.POST("/user/login", u.Login)
-	//server.POST("/user/logout", u.Signout)
-	//server.POST("/user/edit", u.Edit)
+	// 不需要登录的路由
+	rules.Routes(ug, middleware.AuthPublic).
+		POST("/signup", u.Signup).
+		POST("/login", u.LoginJWT).
+		POST("/signup/code/send", u.SignUpCode).
+		POST("/login/code", u.LoginByCode).
+		POST("/refresh_token", u.RefreshToken).
+		POST("/email/verify", u.VerifyEmail).
+		POST("/email/verify/send", u.SendVerifyEmail)
+	// 需要登录的路由
+	required := rules.Routes(ug, middleware.AuthRequired)
+	required.POST("/logout", u.Logout)
+	// 个人访问令牌也能访问的路由
+	required.Scope(domain.ScopeProfileWrite).POST("/edit", u.verified.Build(), u.Edit)
+	required.Scope(domain.ScopeProfileRead).POST("/profile", u.Profile)
 }
 
 // 注册路由handler
@@ -134,15 +161,88 @@ func (u *userHandler) Signup(ctx *gin.Context) {
 		return
 	}
 
+	// 账号已经建好了，验证邮件发送失败用户可以重新发送
+	if err = u.emailCodeSvc.Send(ctx, verifyEmailBiz, req.Email); err != nil {
+		log.Println("发送邮箱验证码失败", err)
+	}
 	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "注册成功"})
 }
 
+// 校验注册邮箱收到的验证码
+func (u *userHandler) VerifyEmail(ctx *gin.Context) {
+	type verifyReq struct {
+		Email string `json:"email"`
+		Code  string `json:"code"`
+	}
+	var req verifyReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ok, err := u.emailCodeSvc.Verify(ctx, verifyEmailBiz, req.Email, req.Code)
+	if err == service.ErrCodeVerifyTooMany {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码验证次数过多，请重新发送"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	}
+	err = u.srv.VerifyEmail(ctx, req.Email)
+	if err == service.ErrUserNotFound {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码有误"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "邮箱验证成功"})
+}
+
+// 重新发送邮箱验证码，发送频率的限制和短信验证码一样
+func (u *userHandler) SendVerifyEmail(ctx *gin.Context) {
+	type sendReq struct {
+		Email string `json:"email"`
+	}
+	var req sendReq
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	user, err := u.srv.FindByEmail(ctx, req.Email)
+	// 没注册或者已经验证过的，也返回成功，不暴露邮箱的状态
+	if err == service.ErrUserNotFound || (err == nil && user.EmailVerified) {
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	err = u.emailCodeSvc.Send(ctx, verifyEmailBiz, req.Email)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "验证码已发送"})
+	case service.ErrCodeSendTooMany:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送过于频繁"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
 // 登录
 func (u *userHandler) LoginJWT(ctx *gin.Context) {
 	// 1. 定义请求体
 	type ReqUserLogin struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		// 失败次数多了之后需要人机验证
+		Captcha string `json:"captcha"`
 	}
 
 	// 2. bind拿结果
@@ -154,25 +254,49 @@ func (u *userHandler) LoginJWT(ctx *gin.Context) {
 		return
 	}
 
+	record := domain.LoginRecord{Method: domain.LoginMethodPassword, Account: reqUserLogin.Email}
+	attempt, err := checkLoginGuard(ctx, u.guard, reqUserLogin.Email, reqUserLogin.Captcha)
+	if err != nil {
+		record.Result = loginResultOf(err)
+		recordLogin(ctx, u.logins, record)
+		return
+	}
+
 	// 3. 调用服务方法，注意传值
 	user, err := u.srv.Login(ctx, domain.User{Email: reqUserLogin.Email, Password: reqUserLogin.Password})
+	record.UserId = user.Id
+	// 密码错误时占用的尝试就是这次失败，其他情况都还回去
+	if err != service.ErrEmailOrPassWrong {
+		loginReleased(ctx, u.guard, attempt)
+	}
+	if err != nil {
+		record.Result = loginResultOf(err)
+		recordLogin(ctx, u.logins, record)
+	}
 	if err == service.ErrEmailOrPassWrong {
 		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "邮箱或者密码错误"})
 		return
 	}
-
-	if err != nil {
-		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	if err == service.ErrUserDeleted {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已注销"})
+		return
+	}
+	if err == service.ErrUserBanned {
+		ctx.JSON(http.StatusOK, bannedResult(user))
 		return
 	}
 
-	err = u.handler.SetLoginToken(ctx, user.Id)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
 	}
-	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "登陆成功"})
-	return
+	// 启用了两步验证的账号，这里只返回登录凭证
+	record.Result = finishLogin(ctx, u.twoFactor, u.handler, u.srv, user.Id)
+	recordLogin(ctx, u.logins, record)
+	// 真正拿到token才清失败次数，两步验证通过的时候再清
+	if record.Result == domain.LoginResultSuccess {
+		loginSucceeded(ctx, u.guard, reqUserLogin.Email)
+	}
 }
 
 // 退出
@@ -331,8 +455,16 @@ func (u *userHandler) LoginByCode(ctx *gin.Context) {
 
 	// 校验手机验证码
 	ok, err := u.codeService.Verify(ctx, "login", req.Phone, req.Code)
+	record := domain.LoginRecord{Method: domain.LoginMethodSms, Account: req.Phone}
+	if err != nil || !ok {
+		record.Result = domain.LoginResultWrongCredentials
+		if err != nil {
+			record.Result = loginResultOf(err)
+		}
+		recordLogin(ctx, u.logins, record)
+	}
 
-	if err == service.ErrUnknownForCode {
+	if err == service.ErrCodeVerifyTooMany {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 4,
 			Msg:  "验证码验证次数过多，请重新发送",
@@ -354,14 +486,19 @@ func (u *userHandler) LoginByCode(ctx *gin.Context) {
 		return
 	}
 	user, err := u.srv.FindOrCreate(ctx, req.Phone)
+	record.UserId = user.Id
 	if err != nil {
-		ctx.JSON(http.StatusOK, Result{
-			Code: 5,
-			Msg:  "系统错误",
-		})
+		record.Result = loginResultOf(err)
+		recordLogin(ctx, u.logins, record)
+	}
+	if err == service.ErrUserDeleted {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已注销"})
+		return
+	}
+	if err == service.ErrUserBanned {
+		ctx.JSON(http.StatusOK, bannedResult(user))
 		return
 	}
-	err = u.handler.SetLoginToken(ctx, user.Id)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -369,11 +506,8 @@ func (u *userHandler) LoginByCode(ctx *gin.Context) {
 		})
 		return
 	}
-	ctx.JSON(http.StatusOK, Result{
-		Code: 0,
-		Msg:  "登陆成功",
-	})
-	return
+	record.Result = finishLogin(ctx, u.twoFactor, u.handler, u.srv, user.Id)
+	recordLogin(ctx, u.logins, record)
 }
 
 func (u *userHandler) RefreshToken(ctx *gin.Context) {
@@ -384,23 +518,21 @@ func (u *userHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 	// 保持和jwt中间件中一样的逻辑
-	refreshClaims := ijwt.UserRefreshJwtClaims{}
-	refreshToken, err := jwt.ParseWithClaims(refreshTokenStr, &refreshClaims, func(token *jwt.Token) (interface{}, error) {
-		return RefreshTokenKey, nil
-	})
-
+	refreshClaims, err := u.handler.ParseRefreshToken(refreshTokenStr)
 	if err != nil {
-		ctx.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-	// 判断payload中的数据，此处认为用火的主键不会为0
-	if refreshToken == nil || !refreshToken.Valid || refreshClaims.UserId == int64(0) {
+		recordLogin(ctx, u.logins, domain.LoginRecord{Method: domain.LoginMethodRefresh,
+			Result: domain.LoginResultInvalidToken})
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
+	record := domain.LoginRecord{UserId: refreshClaims.UserId, Method: domain.LoginMethodRefresh, Ssid: refreshClaims.Ssid}
 	// 检查ssid
 	err = u.handler.CheckSession(ctx, refreshClaims.Ssid)
+	if err != nil {
+		record.Result = loginResultOf(err)
+		recordLogin(ctx, u.logins, record)
+	}
 
 	if err == ErrUserLogout {
 		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户已经退出"})
@@ -411,7 +543,37 @@ func (u *userHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	err = u.handler.SetLoginToken(ctx, refreshClaims.UserId)
+	// 轮换refresh token，旧的refresh token作废
+	err = u.handler.RotateRefreshToken(ctx, refreshClaims)
+	record.Result = domain.LoginResultSuccess
+	if err != nil {
+		record.Result = loginResultOf(err)
+	}
+	recordLogin(ctx, u.logins, record)
+	if err == ijwt.ErrRefreshTokenReused {
+		// 整个family已经被吊销了，这里只需要留痕
+		er := u.securitySvc.Record(ctx, domain.SecurityEvent{
+			UserId:    refreshClaims.UserId,
+			Type:      domain.SecurityEventRefreshTokenReuse,
+			Ssid:      refreshClaims.Ssid,
+			Ip:        ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+			Detail:    "family: " + refreshClaims.Family,
+		})
+		if er != nil {
+			log.Println("记录安全事件失败", er)
+		}
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录状态异常，请重新登录"})
+		return
+	}
+	if err == ErrUserLogout {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "用户已经退出"})
+		return
+	}
+	if err == ijwt.ErrSessionExpired {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录已过期，请重新登录"})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
@@ -419,3 +581,8 @@ func (u *userHandler) RefreshToken(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "刷新token成功"})
 	return
 }
+
+// 被封禁的账号登录时，告诉用户原因和截止时间
+func bannedResult(u domain.User) Result {
+	return Result{Code: 4, Msg: "账号已被封禁", Data: u.UserStatus}
+}
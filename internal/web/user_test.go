@@ -2,6 +2,7 @@ package web
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,9 @@ import (
 	"webook/internal/domain"
 	"webook/internal/service"
 	svcmocks "webook/internal/service/mocks"
+	"webook/internal/web/ijwt"
+	ijwtmocks "webook/internal/web/ijwt/mocks"
+	"webook/internal/web/middleware"
 
 	"go.uber.org/mock/gomock"
 )
@@ -26,6 +30,8 @@ func TestUserHandlerSignup(t *testing.T) {
 
 		// userhandler需要的实例
 		mock func(ctrl *gomock.Controller) (service.UserService, service.CodeService)
+		// 注册成功后发送邮箱验证码，不需要时为nil
+		emailMock func(ctrl *gomock.Controller) service.EmailCodeService
 		// 构造请求
 		reqBuilder func(t *testing.T) *http.Request
 
@@ -45,6 +51,11 @@ func TestUserHandlerSignup(t *testing.T) {
 				codeService := svcmocks.NewMockCodeService(ctrl)
 				return userService, codeService
 			},
+			emailMock: func(ctrl *gomock.Controller) service.EmailCodeService {
+				emailCodeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				emailCodeSvc.EXPECT().Send(gomock.Any(), verifyEmailBiz, "123@qq.com").Return(nil)
+				return emailCodeSvc
+			},
 			reqBuilder: func(t *testing.T) *http.Request {
 				// 传给request的body
 				body := bytes.NewBuffer([]byte(`{"email":"123@qq.com", "password": "123456aA!", "repassword": "123456aA!"}`))
@@ -195,12 +206,16 @@ func TestUserHandlerSignup(t *testing.T) {
 			defer ctrl.Finish()
 			// mock的服务
 			userService, codeService := tc.mock(ctrl)
+			var emailCodeSvc service.EmailCodeService
+			if tc.emailMock != nil {
+				emailCodeSvc = tc.emailMock(ctrl)
+			}
 			// userhandler实例
-			userHandler := NewUserHandler(userService, codeService)
+			userHandler := NewUserHandler(userService, codeService, emailCodeSvc, newTestJWTHandler(ctrl), nil, middleware.NewEmailVerifiedMiddlewareBuilder(userService), newTestLoginGuard(ctrl), newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
 
 			// 注册路由
 			server := gin.Default()
-			userHandler.RegisterRoutes(server)
+			userHandler.RegisterRoutes(server, middleware.NewAuthRules())
 			// req
 			req := tc.reqBuilder(t)
 			// response
@@ -254,6 +269,8 @@ func TestUserHandler_LoginJWT(t *testing.T) {
 					Email:    "123@qq.com",
 					Password: "123456aA!",
 				}, nil)
+				// 颁发token之前恢复正在注销的账号
+				userService.EXPECT().Restore(gomock.Any(), int64(0)).Return(nil)
 
 				return userService, codeService
 			},
@@ -269,7 +286,7 @@ func TestUserHandler_LoginJWT(t *testing.T) {
 				return req
 			},
 			wantCode:     http.StatusOK,
-			wantResponse: Result{Code: 0, Msg: "登录成功"},
+			wantResponse: Result{Code: 0, Msg: "登陆成功"},
 			verifyHeader: true,
 		},
 		{
@@ -354,11 +371,11 @@ func TestUserHandler_LoginJWT(t *testing.T) {
 			// mock的服务
 			userService, codeService := tc.mock(ctrl)
 			// userhandler实例
-			userHandler := NewUserHandler(userService, codeService)
+			userHandler := NewUserHandler(userService, codeService, nil, newTestJWTHandler(ctrl), nil, middleware.NewEmailVerifiedMiddlewareBuilder(userService), newTestLoginGuard(ctrl), newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
 
 			// 注册路由
 			server := gin.Default()
-			userHandler.RegisterRoutes(server)
+			userHandler.RegisterRoutes(server, middleware.NewAuthRules())
 			// req
 			req := tc.reqBuilder(t)
 			// response
@@ -377,7 +394,7 @@ func TestUserHandler_LoginJWT(t *testing.T) {
 			// 断言响应体
 			assert.Equal(t, respBody, tc.wantResponse)
 			if tc.verifyHeader {
-				jwtHeader := response.Header().Get("x-jwt-token")
+				jwtHeader := response.Header().Get("x-ijwt-token")
 				if strings.TrimSpace(jwtHeader) == "" {
 					t.Errorf("Expected header 'x-ijwt-token' to be present")
 				}
@@ -385,3 +402,263 @@ func TestUserHandler_LoginJWT(t *testing.T) {
 		})
 	}
 }
+
+// 测试用的防暴力破解，总是放行
+func newTestLoginGuard(ctrl *gomock.Controller) service.LoginGuardService {
+	guard := svcmocks.NewMockLoginGuardService(ctrl)
+	guard.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.LoginAttempt{}, nil).AnyTimes()
+	guard.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	guard.EXPECT().Failed(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	guard.EXPECT().Succeeded(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return guard
+}
+
+// 测试用的两步验证，都没有启用
+func newTestTwoFactor(ctrl *gomock.Controller) service.TwoFactorService {
+	twoFactor := svcmocks.NewMockTwoFactorService(ctrl)
+	twoFactor.EXPECT().Enabled(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	return twoFactor
+}
+
+// 测试用的登录历史，记录什么都不关心
+func newTestLoginHistory(ctrl *gomock.Controller) service.LoginHistoryService {
+	logins := svcmocks.NewMockLoginHistoryService(ctrl)
+	logins.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return logins
+}
+
+// 启用了两步验证，密码正确也只返回登录凭证，不颁发token
+func TestUserHandler_LoginJWTWithTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userService := svcmocks.NewMockUserService(ctrl)
+	userService.EXPECT().Login(gomock.Any(), domain.User{Email: "123@qq.com", Password: "123456aA!"}).Return(domain.User{Id: 1}, nil)
+	twoFactor := svcmocks.NewMockTwoFactorService(ctrl)
+	twoFactor.EXPECT().Enabled(gomock.Any(), int64(1)).Return(true, nil)
+	twoFactor.EXPECT().Challenge(gomock.Any(), int64(1)).Return("challenge-1", nil)
+	// 不应该调用SetLoginToken，也不能恢复正在注销的账号
+	handler := ijwtmocks.NewMockHandler(ctrl)
+	// 记录为等待两步验证，不是登录成功
+	logins := svcmocks.NewMockLoginHistoryService(ctrl)
+	logins.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, r domain.LoginRecord) error {
+		assert.Equal(t, domain.LoginRecord{UserId: 1, Method: domain.LoginMethodPassword,
+			Result: domain.LoginResultTwoFactorRequired, Account: "123@qq.com", Ip: r.Ip}, r)
+		return nil
+	})
+
+	// 密码正确，归还占用的尝试，但还没拿到token，不能清失败次数
+	attempt := domain.LoginAttempt{Account: "123@qq.com", Ip: "127.0.0.1", AccountFailures: 1}
+	guard := svcmocks.NewMockLoginGuardService(ctrl)
+	guard.EXPECT().Check(gomock.Any(), "123@qq.com", gomock.Any(), gomock.Any()).Return(attempt, nil)
+	guard.EXPECT().Release(gomock.Any(), attempt).Return(nil)
+
+	userHandler := NewUserHandler(userService, svcmocks.NewMockCodeService(ctrl), nil, handler, nil,
+		middleware.NewEmailVerifiedMiddlewareBuilder(userService), guard, twoFactor, logins)
+	server := gin.Default()
+	userHandler.RegisterRoutes(server, middleware.NewAuthRules())
+	req, err := http.NewRequest(http.MethodPost, "/user/login", bytes.NewBuffer([]byte(`{"email":"123@qq.com", "password": "123456aA!"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), `{"code":0,"msg":"请输入两步验证码","data":{"twoFactorRequired":true,"challenge":"challenge-1"}}`)
+	assert.Equal(t, response.Header().Get("x-ijwt-token"), "")
+}
+
+// 密码错误，Check占用的尝试就算这次失败，不归还也不再多记一次
+func TestUserHandler_LoginJWTWrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userService := svcmocks.NewMockUserService(ctrl)
+	userService.EXPECT().Login(gomock.Any(), domain.User{Email: "123@qq.com", Password: "123456aA!"}).
+		Return(domain.User{}, service.ErrEmailOrPassWrong)
+	guard := svcmocks.NewMockLoginGuardService(ctrl)
+	guard.EXPECT().Check(gomock.Any(), "123@qq.com", gomock.Any(), gomock.Any()).
+		Return(domain.LoginAttempt{Account: "123@qq.com", AccountFailures: 1}, nil)
+
+	userHandler := NewUserHandler(userService, svcmocks.NewMockCodeService(ctrl), nil, ijwtmocks.NewMockHandler(ctrl), nil,
+		middleware.NewEmailVerifiedMiddlewareBuilder(userService), guard, newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
+	server := gin.Default()
+	userHandler.RegisterRoutes(server, middleware.NewAuthRules())
+	req, err := http.NewRequest(http.MethodPost, "/user/login", bytes.NewBuffer([]byte(`{"email":"123@qq.com", "password": "123456aA!"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), `{"code":4,"msg":"邮箱或者密码错误","data":null}`)
+}
+
+// 测试用的jwt handler，只负责把token放到header里
+func newTestJWTHandler(ctrl *gomock.Controller) ijwt.Handler {
+	handler := ijwtmocks.NewMockHandler(ctrl)
+	handler.EXPECT().SetLoginToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx *gin.Context, userId int64) error {
+		ctx.Header("x-ijwt-token", "token")
+		ctx.Header("x-ijwt-refresh-token", "refresh-token")
+		return nil
+	}).AnyTimes()
+	return handler
+}
+
+func TestUserHandler_RefreshToken(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (ijwt.Handler, service.SecurityEventService)
+
+		wantResponse Result
+	}{
+		{
+			name: "刷新成功",
+			mock: func(ctrl *gomock.Controller) (ijwt.Handler, service.SecurityEventService) {
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().ParseRefreshToken("refresh-token").Return(&ijwt.UserRefreshJwtClaims{UserId: 1, Ssid: "ssid-1", Family: "ssid-0"}, nil)
+				handler.EXPECT().CheckSession(gomock.Any(), "ssid-1").Return(nil)
+				handler.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+				return handler, svcmocks.NewMockSecurityEventService(ctrl)
+			},
+			wantResponse: Result{Code: 0, Msg: "刷新token成功"},
+		},
+		{
+			name: "refresh token被重复使用",
+			mock: func(ctrl *gomock.Controller) (ijwt.Handler, service.SecurityEventService) {
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().ParseRefreshToken("refresh-token").Return(&ijwt.UserRefreshJwtClaims{UserId: 1, Ssid: "ssid-1", Family: "ssid-0"}, nil)
+				handler.EXPECT().CheckSession(gomock.Any(), "ssid-1").Return(nil)
+				handler.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(ijwt.ErrRefreshTokenReused)
+				securitySvc := svcmocks.NewMockSecurityEventService(ctrl)
+				securitySvc.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, e domain.SecurityEvent) error {
+					assert.Equal(t, e.Type, domain.SecurityEventRefreshTokenReuse)
+					assert.Equal(t, e.UserId, int64(1))
+					return nil
+				})
+				return handler, securitySvc
+			},
+			wantResponse: Result{Code: 4, Msg: "登录状态异常，请重新登录"},
+		},
+		{
+			name: "已经退出登录",
+			mock: func(ctrl *gomock.Controller) (ijwt.Handler, service.SecurityEventService) {
+				handler := ijwtmocks.NewMockHandler(ctrl)
+				handler.EXPECT().ParseRefreshToken("refresh-token").Return(&ijwt.UserRefreshJwtClaims{UserId: 1, Ssid: "ssid-1", Family: "ssid-0"}, nil)
+				handler.EXPECT().CheckSession(gomock.Any(), "ssid-1").Return(ijwt.ErrUserLogout)
+				return handler, svcmocks.NewMockSecurityEventService(ctrl)
+			},
+			wantResponse: Result{Code: 4, Msg: "用户已经退出"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			handler, securitySvc := tc.mock(ctrl)
+			userService := svcmocks.NewMockUserService(ctrl)
+			userHandler := NewUserHandler(userService, svcmocks.NewMockCodeService(ctrl), nil, handler, securitySvc, middleware.NewEmailVerifiedMiddlewareBuilder(userService), newTestLoginGuard(ctrl), newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
+
+			server := gin.Default()
+			userHandler.RegisterRoutes(server, middleware.NewAuthRules())
+			req, err := http.NewRequest(http.MethodPost, "/user/refresh_token", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("x-jwt-refresh-token", "refresh-token")
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, req)
+
+			assert.Equal(t, response.Code, http.StatusOK)
+			var respBody Result
+			err = json.NewDecoder(response.Body).Decode(&respBody)
+			if err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			assert.Equal(t, respBody, tc.wantResponse)
+		})
+	}
+}
+
+func TestUserHandler_VerifyEmail(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService)
+
+		wantResponse Result
+	}{
+		{
+			name: "验证成功",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService) {
+				emailCodeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				emailCodeSvc.EXPECT().Verify(gomock.Any(), verifyEmailBiz, "123@qq.com", "123456").Return(true, nil)
+				userService := svcmocks.NewMockUserService(ctrl)
+				userService.EXPECT().VerifyEmail(gomock.Any(), "123@qq.com").Return(nil)
+				return userService, emailCodeSvc
+			},
+			wantResponse: Result{Code: 0, Msg: "邮箱验证成功"},
+		},
+		{
+			name: "验证码错误",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.EmailCodeService) {
+				emailCodeSvc := svcmocks.NewMockEmailCodeService(ctrl)
+				emailCodeSvc.EXPECT().Verify(gomock.Any(), verifyEmailBiz, "123@qq.com", "123456").Return(false, nil)
+				return svcmocks.NewMockUserService(ctrl), emailCodeSvc
+			},
+			wantResponse: Result{Code: 4, Msg: "验证码有误"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userService, emailCodeSvc := tc.mock(ctrl)
+			userHandler := NewUserHandler(userService, svcmocks.NewMockCodeService(ctrl), emailCodeSvc, newTestJWTHandler(ctrl), nil, middleware.NewEmailVerifiedMiddlewareBuilder(userService), newTestLoginGuard(ctrl), newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
+
+			server := gin.Default()
+			userHandler.RegisterRoutes(server, middleware.NewAuthRules())
+			body := bytes.NewBufferString(`{"email":"123@qq.com","code":"123456"}`)
+			req, err := http.NewRequest(http.MethodPost, "/user/email/verify", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, req)
+
+			var respBody Result
+			if err = json.NewDecoder(response.Body).Decode(&respBody); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			assert.Equal(t, respBody, tc.wantResponse)
+		})
+	}
+}
+
+func TestUserHandler_EditRequiresVerifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userService := svcmocks.NewMockUserService(ctrl)
+	userService.EXPECT().EmailVerified(gomock.Any(), int64(1)).Return(false, nil)
+	userHandler := NewUserHandler(userService, svcmocks.NewMockCodeService(ctrl), nil, newTestJWTHandler(ctrl), nil, middleware.NewEmailVerifiedMiddlewareBuilder(userService), newTestLoginGuard(ctrl), newTestTwoFactor(ctrl), newTestLoginHistory(ctrl))
+
+	server := gin.Default()
+	// 模拟登录中间件
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("Claims", &ijwt.UserJwtClaims{UserId: 1})
+	})
+	userHandler.RegisterRoutes(server, middleware.NewAuthRules())
+	req, err := http.NewRequest(http.MethodPost, "/user/edit", bytes.NewBufferString(`{"nickname":"tom"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+	assert.Equal(t, response.Code, http.StatusForbidden)
+}
@@ -8,37 +8,69 @@ import (
 	"github.com/google/uuid"
 	"net/http"
 	"time"
+	"webook/internal/domain"
 	"webook/internal/service"
 	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
 )
 
 var stateJWTKey = []byte("moyn8y9abnd7q4zkq2m73yw8tu9j5ixB")
 var errStateWrong = errors.New("state被篡改了")
 
+// state cookie的有效期，超过之后扫码回调会失败
+const stateCookieTTL = time.Minute * 10
+
 type OAuth2WeChatHandler interface {
-	RegisterRoutes(server *gin.Engine)
+	RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules)
 	AuthUrl(ctx *gin.Context)
+	BindAuthUrl(ctx *gin.Context)
 	Callback(ctx *gin.Context)
 }
 
 type oAuth2WeChatHandler struct {
 	svc         service.WeChatService
 	userService service.UserService
+	identitySvc service.IdentityService
+	handler     ijwt.Handler
+	twoFactor   service.TwoFactorService
+	logins      service.LoginHistoryService
 }
 
-func NewOAuth2WeChatHandler(svc service.WeChatService) OAuth2WeChatHandler {
-	return &oAuth2WeChatHandler{svc: svc}
+func NewOAuth2WeChatHandler(svc service.WeChatService, userService service.UserService,
+	identitySvc service.IdentityService, handler ijwt.Handler, twoFactor service.TwoFactorService,
+	logins service.LoginHistoryService) OAuth2WeChatHandler {
+	return &oAuth2WeChatHandler{svc: svc, userService: userService, identitySvc: identitySvc, handler: handler,
+		twoFactor: twoFactor, logins: logins}
 }
 
-func (handler *oAuth2WeChatHandler) RegisterRoutes(server *gin.Engine) {
+func (handler *oAuth2WeChatHandler) RegisterRoutes(server *gin.Engine, rules *middleware.AuthRules) {
 	group := server.Group("/oauth2/wechat")
-	// 申请登录的第三方地址
-	group.GET("/authurl", handler.AuthUrl)
-	// 扫码后的callback url，用Any保险一点
-	group.Any("/callback", handler.Callback)
+	rules.Routes(group, middleware.AuthPublic).
+		// 申请登录的第三方地址
+		GET("/authurl", handler.AuthUrl).
+		// 扫码后的callback url，用Any保险一点
+		Any("/callback", handler.Callback)
+	// 已登录的用户绑定微信，回调还是上面的callback
+	rules.Routes(group, middleware.AuthRequired).
+		GET("/bind/authurl", handler.BindAuthUrl)
 }
 
 func (handler *oAuth2WeChatHandler) AuthUrl(ctx *gin.Context) {
+	handler.authUrl(ctx, 0)
+}
+
+func (handler *oAuth2WeChatHandler) BindAuthUrl(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("Claims").(*ijwt.UserJwtClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	handler.authUrl(ctx, claims.UserId)
+}
+
+// bindUserId不为0表示这次扫码是给这个用户绑定微信
+// 发起绑定的用户记在服务端，cookie里只标记这是一次绑定
+func (handler *oAuth2WeChatHandler) authUrl(ctx *gin.Context, bindUserId int64) {
 	// 标识此次扫码的code，类似于单次会话id
 	state := uuid.New()
 	url, err := handler.svc.AuthURL(state.String())
@@ -46,8 +78,14 @@ func (handler *oAuth2WeChatHandler) AuthUrl(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
 	}
+	if bindUserId != 0 {
+		if err = handler.identitySvc.PrepareWeChatBind(ctx, state.String(), bindUserId); err != nil {
+			ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+			return
+		}
+	}
 	// 设置cookie
-	err = handler.setStateCookie(ctx, state.String())
+	err = handler.setStateCookie(ctx, state.String(), bindUserId != 0)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
@@ -58,7 +96,7 @@ func (handler *oAuth2WeChatHandler) AuthUrl(ctx *gin.Context) {
 }
 
 func (handler *oAuth2WeChatHandler) Callback(ctx *gin.Context) {
-	err := handler.verifyStateCookie(ctx)
+	state, err := handler.verifyStateCookie(ctx)
 	if err == errStateWrong {
 		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "登录失效，请重新登录"})
 		return
@@ -70,10 +108,28 @@ func (handler *oAuth2WeChatHandler) Callback(ctx *gin.Context) {
 	code := ctx.Query("code")
 	result, err := handler.svc.VerifyCode(ctx, code)
 	if err != nil {
+		recordLogin(ctx, handler.logins, domain.LoginRecord{Method: domain.LoginMethodWeChat,
+			Result: loginResultOf(err)})
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
 	}
+	if state.Bind {
+		handler.bind(ctx, state.State, result)
+		return
+	}
 	user, err := handler.userService.FindOrCreateByWeChat(ctx, result)
+	if err != nil {
+		recordLogin(ctx, handler.logins, domain.LoginRecord{UserId: user.Id, Method: domain.LoginMethodWeChat,
+			Result: loginResultOf(err)})
+	}
+	if err == service.ErrUserDeleted {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已注销"})
+		return
+	}
+	if err == service.ErrUserBanned {
+		ctx.JSON(http.StatusOK, bannedResult(user))
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -81,33 +137,42 @@ func (handler *oAuth2WeChatHandler) Callback(ctx *gin.Context) {
 		})
 		return
 	}
-	// 颁发token: todo封装方法
-	claims := ijwt.UserJwtClaims{
-		UserId:    user.Id,
-		UserAgent: ctx.Request.UserAgent(),
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 600)),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
-
-	// 签发 xxx.xx.xx的字符串
-	tokenStr, err := token.SignedString([]byte("95osj3fUD7fo0mlYdDbncXz4VD2igvf0"))
+	// 颁发长短token，和其他登录方式保持一致
+	res := finishLogin(ctx, handler.twoFactor, handler.handler, handler.userService, user.Id)
+	recordLogin(ctx, handler.logins, domain.LoginRecord{UserId: user.Id, Method: domain.LoginMethodWeChat, Result: res})
+}
 
-	// 签发失败
+// 把扫码的微信绑定到发起绑定的账号上，发起绑定的用户只从服务端取
+func (handler *oAuth2WeChatHandler) bind(ctx *gin.Context, state string, result domain.WeChatResult) {
+	userId, err := handler.identitySvc.TakeWeChatBind(ctx, state)
+	if err == service.ErrBindStateInvalid {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "绑定请求已失效，请重新发起"})
+		return
+	}
 	if err != nil {
-		ctx.String(http.StatusOK, "系统错误")
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		return
 	}
-	// 把token放到header里去
-	ctx.Writer.Header().Set("x-jwt-token", tokenStr)
-	ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "登陆成功"})
-	return
+	err = handler.identitySvc.BindWeChat(ctx, userId, result)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{Code: 0, Msg: "绑定成功"})
+	case service.ErrIdentityTaken:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "微信已经绑定了其他账号"})
+	case service.ErrIdentityExists:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "已经绑定了微信，请先解绑"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
 }
 
-func (handler *oAuth2WeChatHandler) setStateCookie(ctx *gin.Context, state string) error {
+func (handler *oAuth2WeChatHandler) setStateCookie(ctx *gin.Context, state string, bind bool) error {
 	claims := WeChatStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(stateCookieTTL)),
+		},
 		State: state,
+		Bind:  bind,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
 	tokenStr, err := token.SignedString(stateJWTKey)
@@ -115,17 +180,17 @@ func (handler *oAuth2WeChatHandler) setStateCookie(ctx *gin.Context, state strin
 		return err
 	}
 	// 放在cookie里，再callback时进行校验
-	ctx.SetCookie("ijwt-oauth2-state", tokenStr, 600, "/oauth2/wechat/callback", "", false, true)
+	ctx.SetCookie("ijwt-oauth2-state", tokenStr, int(stateCookieTTL.Seconds()), "/oauth2/wechat/callback", "", false, true)
 	return nil
 }
 
-func (handler *oAuth2WeChatHandler) verifyStateCookie(ctx *gin.Context) error {
+func (handler *oAuth2WeChatHandler) verifyStateCookie(ctx *gin.Context) (*WeChatStateClaims, error) {
 	// url上的state
 	state := ctx.Query("state")
 	tokenStr, err := ctx.Cookie("ijwt-oauth2-state")
 	// 拿不到cookie
 	if err != nil || tokenStr == "" {
-		return fmt.Errorf("%w, 无法拿到cookie", err)
+		return nil, fmt.Errorf("%w, 无法拿到cookie", err)
 	}
 	// 校验tokenStr
 	claims := &WeChatStateClaims{}
@@ -133,15 +198,17 @@ func (handler *oAuth2WeChatHandler) verifyStateCookie(ctx *gin.Context) error {
 		return stateJWTKey, nil
 	})
 	if err != nil {
-		return fmt.Errorf("%w, token解析失败", err)
+		return nil, fmt.Errorf("%w, token解析失败", err)
 	}
 	if state != claims.State {
-		return errStateWrong
+		return nil, errStateWrong
 	}
-	return nil
+	return claims, nil
 }
 
 type WeChatStateClaims struct {
-	jwt.Claims
+	jwt.RegisteredClaims
 	State string
+	// 这次扫码是绑定微信，发起绑定的用户按State存在服务端
+	Bind bool `json:",omitempty"`
 }
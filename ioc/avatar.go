@@ -0,0 +1,16 @@
+package ioc
+
+import (
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/storage"
+)
+
+func InitAvatarService(repo repository.UserRepository, storage storage.Service) service.AvatarService {
+	cfg := config.Config.Avatar
+	return service.NewAvatarService(repo, storage, service.AvatarPolicy{
+		MaxSize:      cfg.MaxSize,
+		MaxDimension: cfg.MaxDimension,
+	})
+}
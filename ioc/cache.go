@@ -0,0 +1,50 @@
+package ioc
+
+import (
+	"github.com/redis/go-redis/v9"
+	"webook/internal/repository/cache"
+)
+
+// 登录链路上用到的缓存，会话存在内存里说明没有redis，这些也都放在内存里
+
+func InitUserCache(client redis.Cmdable) cache.UserCache {
+	if withoutRedis() {
+		return cache.NewLocalUserCache()
+	}
+	return cache.NewUserCache(client)
+}
+
+func InitCodeCache(client redis.Cmdable) cache.CodeCache {
+	if withoutRedis() {
+		return cache.NewLocalCodeCache()
+	}
+	return cache.NewCodeCache(client)
+}
+
+func InitRoleCache(client redis.Cmdable) cache.RoleCache {
+	if withoutRedis() {
+		return cache.NewLocalRoleCache()
+	}
+	return cache.NewRoleCache(client)
+}
+
+func InitFollowCache(client redis.Cmdable) cache.FollowCache {
+	if withoutRedis() {
+		return cache.NewLocalFollowCache()
+	}
+	return cache.NewFollowCache(client)
+}
+
+func InitTwoFactorChallengeCache(client redis.Cmdable) cache.TwoFactorChallengeCache {
+	if withoutRedis() {
+		return cache.NewLocalTwoFactorChallengeCache()
+	}
+	return cache.NewTwoFactorChallengeCache(client)
+}
+
+func InitWeChatBindCache(client redis.Cmdable) cache.WeChatBindCache {
+	if withoutRedis() {
+		return cache.NewLocalWeChatBindCache()
+	}
+	return cache.NewWeChatBindCache(client)
+}
@@ -0,0 +1,29 @@
+package captcha
+
+import (
+	"log"
+	"os"
+	"webook/config"
+	"webook/internal/service/captcha"
+	"webook/internal/service/captcha/local"
+	"webook/internal/service/captcha/siteverify"
+)
+
+// 根据配置选择人机验证服务，没有配置直接启动失败，免得线上用了本地的假实现
+func InitCaptchaService() captcha.Service {
+	cfg := config.Config.Captcha
+	switch cfg.Provider {
+	case config.CaptchaLocal:
+		log.Println("使用本地的人机验证，任何票据都能通过，只能用于本地开发")
+		return local.NewService()
+	case config.CaptchaSiteVerify:
+		// 密钥不写在代码里，通过环境变量注入
+		svc, err := siteverify.NewService(cfg.VerifyURL, os.Getenv("CAPTCHA_SECRET"))
+		if err != nil {
+			panic(err)
+		}
+		return svc
+	default:
+		panic("没有配置人机验证服务")
+	}
+}
@@ -0,0 +1,23 @@
+package ioc
+
+import (
+	"context"
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/service"
+)
+
+// 清理注销用户时，数据不在users表里的模块在这里登记
+func InitUserPurgedListeners(avatarSvc service.AvatarService, followSvc service.FollowService,
+	loginHistorySvc service.LoginHistoryService, exportSvc service.DataExportService,
+	smsRecordSvc service.SmsRecordService) []service.UserPurgedListener {
+	return []service.UserPurgedListener{avatarSvc, followSvc, loginHistorySvc, exportSvc, smsRecordSvc}
+}
+
+// 顺便启动清理注销用户的定时任务
+func InitAccountDeletionService(repo repository.UserRepository, listeners []service.UserPurgedListener) service.AccountDeletionService {
+	cfg := config.Config.Account
+	svc := service.NewAccountDeletionService(repo, cfg.DeletionGracePeriod, listeners)
+	go svc.RunPurge(context.Background(), cfg.PurgeInterval)
+	return svc
+}
@@ -0,0 +1,19 @@
+package email
+
+import (
+	"os"
+	"webook/config"
+	"webook/internal/service/email"
+	"webook/internal/service/email/local"
+	"webook/internal/service/email/smtp"
+)
+
+// 没有配置SMTP服务器时只打印，不真正发送
+func InitEmailService() email.Service {
+	cfg := config.Config.Email
+	if cfg.Host == "" {
+		return local.NewMemoryService()
+	}
+	// 密码不写在代码里，通过环境变量注入
+	return smtp.NewService(cfg.Host, cfg.Port, cfg.Username, os.Getenv("SMTP_PASSWORD"), cfg.From)
+}
@@ -0,0 +1,43 @@
+package ioc
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+	"os"
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/service"
+)
+
+// 顺便启动清理过期归档的定时任务
+func InitDataExportService(repo repository.DataExportRepository, users repository.UserRepository,
+	identities repository.IdentityRepository, security repository.SecurityEventRepository,
+	sms repository.SmsRepository, tokens repository.PersonalTokenRepository,
+	logins repository.LoginRecordRepository, follows repository.FollowRepository) service.DataExportService {
+	cfg := config.Config.Export
+	// 密钥不写在代码里；本地没配置就临时生成一把，重启之后旧链接失效
+	// 多实例部署时每个实例的密钥必须一样，没配置直接启动失败
+	key := []byte(os.Getenv("EXPORT_SIGN_KEY"))
+	if len(key) == 0 {
+		if !cfg.EphemeralSignKey {
+			panic("没有配置EXPORT_SIGN_KEY")
+		}
+		log.Println("没有配置EXPORT_SIGN_KEY，使用临时生成的密钥")
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+	}
+	svc := service.NewDataExportService(repo, service.DataExportSources{
+		Users:      users,
+		Identities: identities,
+		Security:   security,
+		Sms:        sms,
+		Tokens:     tokens,
+		Logins:     logins,
+		Follows:    follows,
+	}, cfg.Dir, key, cfg.Retention, cfg.LinkExpiration)
+	go svc.RunCleanup(context.Background(), cfg.CleanupInterval)
+	return svc
+}
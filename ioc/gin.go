@@ -1,34 +1,75 @@
 package ioc
 
 import (
+	"expvar"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"time"
+	"webook/config"
+	"webook/internal/domain"
+	"webook/internal/service"
 	"webook/internal/web"
 	"webook/internal/web/ijwt"
 	"webook/internal/web/middleware"
 	"webook/pkg/ginx/middlewares/ratelimit"
+	"webook/pkg/limiter"
 )
 
 // 初始gin的服务器
-func InitWebServer(ug web.UserHandler, wechatHandler web.OAuth2WeChatHandler, fn []gin.HandlerFunc) *gin.Engine {
+// 各个handler注册路由时，把鉴权级别登记到rules里，登录中间件按rules校验
+func InitWebServer(ug web.UserHandler, wechatHandler web.OAuth2WeChatHandler, jwksHandler web.JWKSHandler, sessionHandler web.SessionHandler,
+	roleHandler web.RoleHandler, tokenHandler web.PersonalTokenHandler,
+	passwordHandler web.PasswordHandler, identityHandler web.IdentityHandler,
+	mergeHandler web.MergeHandler, deletionHandler web.AccountDeletionHandler,
+	exportHandler web.DataExportHandler, loginLockHandler web.LoginLockHandler, twoFactorHandler web.TwoFactorHandler,
+	avatarHandler web.AvatarHandler, profileHandler web.ProfileHandler,
+	followHandler web.FollowHandler, adminUserHandler web.AdminUserHandler,
+	loginHistoryHandler web.LoginHistoryHandler, rbac *middleware.RBACMiddlewareBuilder, rules *middleware.AuthRules,
+	fn []gin.HandlerFunc) *gin.Engine {
 	server := gin.Default()
 	server.Use(fn...)
-	ug.RegisterRoutes(server)
-	wechatHandler.RegisterRoutes(server)
+	// expvar的监控指标，包含启动参数和内存信息，只给有权限的管理员看
+	rules.Routes(&server.RouterGroup, middleware.AuthRequired).
+		GET("/debug/vars", rbac.RequirePermission(domain.PermissionDebugView), gin.WrapH(expvar.Handler()))
+	ug.RegisterRoutes(server, rules)
+	wechatHandler.RegisterRoutes(server, rules)
+	jwksHandler.RegisterRoutes(server, rules)
+	sessionHandler.RegisterRoutes(server, rules)
+	roleHandler.RegisterRoutes(server, rules)
+	tokenHandler.RegisterRoutes(server, rules)
+	passwordHandler.RegisterRoutes(server, rules)
+	identityHandler.RegisterRoutes(server, rules)
+	mergeHandler.RegisterRoutes(server, rules)
+	deletionHandler.RegisterRoutes(server, rules)
+	exportHandler.RegisterRoutes(server, rules)
+	loginLockHandler.RegisterRoutes(server, rules)
+	twoFactorHandler.RegisterRoutes(server, rules)
+	avatarHandler.RegisterRoutes(server, rules)
+	profileHandler.RegisterRoutes(server, rules)
+	followHandler.RegisterRoutes(server, rules)
+	adminUserHandler.RegisterRoutes(server, rules)
+	loginHistoryHandler.RegisterRoutes(server, rules)
 	return server
 }
 
-func InitMiddlewares(cmd redis.Cmdable, handler ijwt.Handler) []gin.HandlerFunc {
+func InitMiddlewares(cmd redis.Cmdable, handler ijwt.Handler, tokens service.PersonalTokenService,
+	statusSvc service.AccountStatusService, rules *middleware.AuthRules) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
 		middleware.InitCors(),
-		ratelimit.NewBuilder(cmd, time.Minute, 100).Build(),
-		middleware.NewLoginJWTMiddlewareBuilder(cmd, handler).
-			Ignore("/user/login").
-			Ignore("/user/signup").
-			Ignore("/user/signup/code/send").
-			Ignore("/user/login/code").
-			Ignore("/user/refresh_token").
+		initRateLimit(cmd).Build(),
+		middleware.NewLoginJWTMiddlewareBuilder(handler).
+			Rules(rules).
+			RenewWindow(config.Config.JWT.RenewWindow).
+			PersonalTokens(tokens).
+			AccountStatus(statusSvc).
 			Build(),
 	}
 }
+
+// 没有redis时按单机限流
+func initRateLimit(cmd redis.Cmdable) *ratelimit.Builder {
+	if withoutRedis() {
+		return ratelimit.NewBuilderWithLimiter(limiter.NewLocalLimiter(time.Minute, 100))
+	}
+	return ratelimit.NewBuilder(cmd, time.Minute, 100)
+}
@@ -0,0 +1,66 @@
+package ioc
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"webook/config"
+	"webook/internal/service"
+	"webook/internal/web/ijwt"
+)
+
+// 初始化jwt的签名密钥
+func InitKeyManager() ijwt.KeyManager {
+	cfg := config.Config.JWT
+	keys, err := ijwt.NewKeyManager(cfg.Algorithm, cfg.MaxKeys)
+	if err != nil {
+		panic(err)
+	}
+	// 没有配置密钥，临时生成一把，只适合本地开发
+	if len(cfg.Keys) == 0 {
+		if err = keys.Rotate(); err != nil {
+			panic(err)
+		}
+		return keys
+	}
+	// AddKey会把新密钥放在最前面，所以倒着加，保证第一个是主密钥
+	for i := len(cfg.Keys) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(cfg.Keys[i].PrivateKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		private, err := ijwt.ParsePrivateKeyPEM(data)
+		if err != nil {
+			panic(err)
+		}
+		if err = keys.AddKey(cfg.Keys[i].Kid, private); err != nil {
+			panic(err)
+		}
+	}
+	return keys
+}
+
+// 本地的退出登录缓存，后台订阅其他实例的退出事件
+func InitRevocationCache(client redis.Cmdable) ijwt.RevocationCache {
+	cfg := config.Config.JWT
+	revoked := ijwt.NewLocalRevocationCache(cfg.RefreshExpiration, cfg.RevocationLag, cfg.RevocationCacheMB)
+	// 会话存在内存里时用不到，订阅需要具体的client，Cmdable上没有Subscribe
+	if uc, ok := client.(redis.UniversalClient); ok && cfg.SessionStore != config.SessionStoreMemory {
+		go revoked.Sync(context.Background(), uc)
+	}
+	return revoked
+}
+
+// 根据配置选择会话状态的存储，memory不依赖redis
+func InitJWTHandler(client redis.Cmdable, keys ijwt.KeyManager, revoked ijwt.RevocationCache, roles service.RoleService) ijwt.Handler {
+	cfg := config.Config.JWT
+	opts := ijwt.Options{
+		AccessExpiration:   cfg.AccessExpiration,
+		RefreshExpiration:  cfg.RefreshExpiration,
+		MaxSessionLifetime: cfg.MaxSessionLifetime,
+	}
+	if cfg.SessionStore == config.SessionStoreMemory {
+		return ijwt.NewMemoryHandler(keys, roles, opts)
+	}
+	return ijwt.NewRedisHandler(client, keys, revoked, roles, opts)
+}
@@ -0,0 +1,32 @@
+package ioc
+
+import (
+	"github.com/redis/go-redis/v9"
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/repository/cache"
+	"webook/internal/service"
+	"webook/internal/service/captcha"
+)
+
+// 会话存在内存里说明没有redis，失败次数也放在内存里，否则redis连不上登录就一直报系统错误
+func InitLoginAttemptCache(client redis.Cmdable) cache.LoginAttemptCache {
+	if withoutRedis() {
+		return cache.NewLocalLoginAttemptCache(config.Config.Login.Window)
+	}
+	return cache.NewLoginAttemptCache(client, config.Config.Login.Window)
+}
+
+func InitLoginGuardService(repo repository.LoginAttemptRepository, captchaSvc captcha.Service) service.LoginGuardService {
+	cfg := config.Config.Login
+	return service.NewLoginGuardService(repo, captchaSvc, service.LoginGuardPolicy{
+		DelayAfter:     cfg.DelayAfter,
+		BaseDelay:      cfg.BaseDelay,
+		MaxDelay:       cfg.MaxDelay,
+		LockAfter:      cfg.LockAfter,
+		LockDuration:   cfg.LockDuration,
+		CaptchaAfter:   cfg.CaptchaAfter,
+		IpCaptchaAfter: cfg.IpCaptchaAfter,
+		IpBlockAfter:   cfg.IpBlockAfter,
+	})
+}
@@ -0,0 +1,16 @@
+package ioc
+
+import (
+	"context"
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/service"
+)
+
+// 顺便启动按保留策略清理登录记录的定时任务
+func InitLoginHistoryService(repo repository.LoginRecordRepository, users repository.UserRepository) service.LoginHistoryService {
+	cfg := config.Config.LoginHistory
+	svc := service.NewLoginHistoryService(repo, users, cfg.Retention)
+	go svc.RunPrune(context.Background(), cfg.PruneInterval)
+	return svc
+}
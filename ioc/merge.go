@@ -0,0 +1,9 @@
+package ioc
+
+import "webook/internal/service"
+
+// 账号合并后需要改外键的模块，新模块在这里登记
+func InitUserMergedListeners(roleSvc service.RoleService, tokenSvc service.PersonalTokenService,
+	followSvc service.FollowService, loginHistorySvc service.LoginHistoryService) []service.UserMergedListener {
+	return []service.UserMergedListener{roleSvc, tokenSvc, followSvc, loginHistorySvc}
+}
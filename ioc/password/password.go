@@ -0,0 +1,26 @@
+package password
+
+import (
+	"webook/config"
+	"webook/internal/service/password"
+)
+
+// 两种算法都要能校验，切换配置之后老用户的密码还能用，登录时自动升级
+// 限制并发，避免登录高峰时argon2id把内存打满
+func InitPasswordHasher() password.Hasher {
+	cfg := config.Config.Password
+	bcrypt := password.NewBcrypt(cfg.BcryptCost)
+	argon2id := password.NewArgon2id(password.Argon2idParams{
+		Memory:  cfg.Argon2Memory,
+		Time:    cfg.Argon2Time,
+		Threads: cfg.Argon2Threads,
+	})
+	var hasher password.Hasher
+	switch cfg.Algorithm {
+	case config.PasswordArgon2id:
+		hasher = password.NewHasher(argon2id, bcrypt)
+	default:
+		hasher = password.NewHasher(bcrypt, argon2id)
+	}
+	return password.NewLimitedHasher(hasher, cfg.MaxConcurrency)
+}
@@ -0,0 +1,11 @@
+package ioc
+
+import (
+	"webook/config"
+	"webook/internal/repository"
+	"webook/internal/service"
+)
+
+func InitProfileService(repo repository.UserRepository) service.ProfileService {
+	return service.NewProfileService(repo, config.Config.Account.HandleChangeCooldown)
+}
@@ -0,0 +1,10 @@
+package ioc
+
+import (
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+)
+
+func InitRBAC(svc service.RoleService) *middleware.RBACMiddlewareBuilder {
+	return middleware.NewRBACMiddlewareBuilder(svc)
+}
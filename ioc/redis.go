@@ -12,3 +12,9 @@ func InitRedis() redis.Cmdable {
 	})
 	return redisClient
 }
+
+// 会话存在内存里说明没有redis，依赖redis的存储都换成进程内的，只适合单机部署和本地开发
+// 这时redis的client还会创建，但是不会连接
+func withoutRedis() bool {
+	return config.Config.JWT.SessionStore == config.SessionStoreMemory
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"os"
+	"webook/config"
+	"webook/internal/service/storage"
+	"webook/internal/service/storage/local"
+	"webook/internal/service/storage/s3"
+)
+
+func InitStorageService() storage.Service {
+	cfg := config.Config.Storage
+	if cfg.Type != config.StorageS3 {
+		return local.NewService(cfg.LocalDir, cfg.PublicURL)
+	}
+	// 密钥不写在代码里，通过环境变量注入
+	svc, err := s3.NewService(cfg.Endpoint, cfg.Bucket, cfg.Region,
+		os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), cfg.PublicURL)
+	if err != nil {
+		panic(err)
+	}
+	return svc
+}
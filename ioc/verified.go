@@ -0,0 +1,16 @@
+package ioc
+
+import (
+	"webook/config"
+	"webook/internal/repository/cache"
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+)
+
+func InitUserStatusCache() cache.UserStatusCache {
+	return cache.NewLocalUserStatusCache(config.Config.Account.StatusCacheTTL)
+}
+
+func InitEmailVerified(svc service.UserService) *middleware.EmailVerifiedMiddlewareBuilder {
+	return middleware.NewEmailVerifiedMiddlewareBuilder(svc)
+}
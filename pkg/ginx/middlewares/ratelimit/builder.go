@@ -25,6 +25,14 @@ func NewBuilder(cmd redis.Cmdable, interval time.Duration, rate int) *Builder {
 	}
 }
 
+// 限流器由外面决定，比如没有redis时用进程内的
+func NewBuilderWithLimiter(l limiter.Limiter) *Builder {
+	return &Builder{
+		prefix:  "ip-limiter",
+		limiter: l,
+	}
+}
+
 func (b *Builder) Prefix(prefix string) *Builder {
 	b.prefix = prefix
 	return b
@@ -0,0 +1,62 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 进程内的滑动窗口，没有redis时用，多实例之间不共享
+type localLimiter struct {
+	lock sync.Mutex
+	// 每个key窗口内的请求时间，按时间排序
+	requests map[string][]time.Time
+	// 限流窗口大小
+	interval time.Duration
+	// 窗口内请求大小
+	rate int
+	// 上一次清理空窗口的时间
+	lastSweep time.Time
+}
+
+func NewLocalLimiter(interval time.Duration, rate int) Limiter {
+	return &localLimiter{requests: map[string][]time.Time{}, interval: interval, rate: rate, lastSweep: time.Now()}
+}
+
+// 和lua脚本一样，被限流的请求不计入窗口
+func (l *localLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := time.Now()
+	start := now.Add(-l.interval)
+	l.sweep(now, start)
+	reqs := l.trim(l.requests[key], start)
+	if len(reqs) >= l.rate {
+		l.requests[key] = reqs
+		return true, nil
+	}
+	l.requests[key] = append(reqs, now)
+	return false, nil
+}
+
+// 去掉窗口之前的请求
+func (l *localLimiter) trim(reqs []time.Time, start time.Time) []time.Time {
+	i := 0
+	for i < len(reqs) && !reqs[i].After(start) {
+		i++
+	}
+	return reqs[i:]
+}
+
+// 每个窗口清理一次不再请求的key，避免map一直变大
+func (l *localLimiter) sweep(now time.Time, start time.Time) {
+	if now.Sub(l.lastSweep) < l.interval {
+		return
+	}
+	l.lastSweep = now
+	for key, reqs := range l.requests {
+		if len(l.trim(reqs, start)) == 0 {
+			delete(l.requests, key)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocalLimiter(time.Millisecond*50, 2)
+	for i := 0; i < 2; i++ {
+		limited, err := l.Limit(ctx, "ip-1")
+		require.NoError(t, err)
+		assert.False(t, limited)
+	}
+	limited, err := l.Limit(ctx, "ip-1")
+	require.NoError(t, err)
+	assert.True(t, limited)
+	// 不同的key分开算
+	limited, err = l.Limit(ctx, "ip-2")
+	require.NoError(t, err)
+	assert.False(t, limited)
+
+	// 出了窗口重新计数
+	time.Sleep(time.Millisecond * 60)
+	limited, err = l.Limit(ctx, "ip-1")
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
@@ -6,14 +6,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"webook/internal/repository"
-	"webook/internal/repository/cache"
 	"webook/internal/repository/entity"
 	"webook/internal/service"
 	"webook/internal/web"
 	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
+	"webook/ioc/captcha"
+	"webook/ioc/email"
 	"webook/ioc/oauth2"
+	"webook/ioc/password"
 	"webook/ioc/sms"
+	"webook/ioc/storage"
 )
 
 // 根目录下运行wire
@@ -21,7 +25,9 @@ func InitWebServer() *gin.Engine {
 	wire.Build(
 
 		// ijwt handler
-		ijwt.NewRedisHandler,
+		ioc.InitKeyManager,
+		ioc.InitRevocationCache,
+		ioc.InitJWTHandler,
 
 		// db和redis
 		ioc.InitDB,
@@ -29,12 +35,39 @@ func InitWebServer() *gin.Engine {
 
 		// cache和entity
 		entity.NewUserEntity,
-		cache.NewCodeCache,
-		cache.NewUserCache,
+		entity.NewSecurityEventEntity,
+		entity.NewRoleEntity,
+		entity.NewPersonalTokenEntity,
+		entity.NewIdentityEntity,
+		entity.NewSMSEntity,
+		entity.NewDataExportEntity,
+		entity.NewTwoFactorEntity,
+		entity.NewFollowEntity,
+		entity.NewAuditLogEntity,
+		entity.NewLoginRecordEntity,
+		// 没有redis时换成进程内的
+		ioc.InitCodeCache,
+		ioc.InitUserCache,
+		ioc.InitRoleCache,
+		ioc.InitTwoFactorChallengeCache,
+		ioc.InitFollowCache,
+		ioc.InitWeChatBindCache,
 
 		// repo
 		repository.NewUserRepository,
 		repository.NewCodeRepository,
+		repository.NewSecurityEventRepository,
+		repository.NewRoleRepository,
+		repository.NewPersonalTokenRepository,
+		repository.NewIdentityRepository,
+		repository.NewSmsRepository,
+		repository.NewDataExportRepository,
+		repository.NewLoginAttemptRepository,
+		repository.NewTwoFactorRepository,
+		repository.NewFollowRepository,
+		repository.NewAuditLogRepository,
+		repository.NewUserStatusRepository,
+		repository.NewLoginRecordRepository,
 
 		// wechat service
 		oauth2.InitWeChatService,
@@ -42,13 +75,58 @@ func InitWebServer() *gin.Engine {
 		// service
 		//local.NewMemoryService,
 		sms.InitSmsService,
+		email.InitEmailService,
 		service.NewCodeService,
+		service.NewEmailCodeService,
+		password.InitPasswordHasher,
 		service.NewUserService,
+		service.NewSecurityEventService,
+		service.NewRoleService,
+		service.NewPersonalTokenService,
+		service.NewIdentityService,
+		service.NewAccountMergeService,
+		ioc.InitUserMergedListeners,
+		ioc.InitAccountDeletionService,
+		ioc.InitUserPurgedListeners,
+		ioc.InitAvatarService,
+		ioc.InitProfileService,
+		storage.InitStorageService,
+		ioc.InitDataExportService,
+		ioc.InitLoginAttemptCache,
+		ioc.InitLoginGuardService,
+		captcha.InitCaptchaService,
+		service.NewTwoFactorService,
+		service.NewFollowService,
+		service.NewAdminUserService,
+		wire.Bind(new(service.SessionRevoker), new(ijwt.Handler)),
+		service.NewAccountStatusService,
+		ioc.InitUserStatusCache,
+		ioc.InitLoginHistoryService,
+		service.NewSmsRecordService,
 
 		// controller
 		web.NewUserHandler,
 		web.NewOAuth2WeChatHandler,
+		web.NewJWKSHandler,
+		web.NewSessionHandler,
+		web.NewRoleHandler,
+		web.NewPersonalTokenHandler,
+		web.NewPasswordHandler,
+		web.NewIdentityHandler,
+		web.NewMergeHandler,
+		web.NewAccountDeletionHandler,
+		web.NewDataExportHandler,
+		web.NewLoginLockHandler,
+		web.NewTwoFactorHandler,
+		web.NewAvatarHandler,
+		web.NewProfileHandler,
+		web.NewFollowHandler,
+		web.NewAdminUserHandler,
+		web.NewLoginHistoryHandler,
 
+		middleware.NewAuthRules,
+		ioc.InitRBAC,
+		ioc.InitEmailVerified,
 		ioc.InitMiddlewares,
 		ioc.InitWebServer,
 	)
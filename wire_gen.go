@@ -9,14 +9,17 @@ package main
 import (
 	"github.com/gin-gonic/gin"
 	"webook/internal/repository"
-	"webook/internal/repository/cache"
 	"webook/internal/repository/entity"
 	"webook/internal/service"
 	"webook/internal/web"
-	"webook/internal/web/ijwt"
+	"webook/internal/web/middleware"
 	"webook/ioc"
+	"webook/ioc/captcha"
+	"webook/ioc/email"
 	"webook/ioc/oauth2"
+	"webook/ioc/password"
 	"webook/ioc/sms"
+	"webook/ioc/storage"
 )
 
 // Injectors from wire.go:
@@ -26,18 +29,90 @@ func InitWebServer() *gin.Engine {
 	db := ioc.InitDB()
 	userEntity := entity.NewUserEntity(db)
 	cmdable := ioc.InitRedis()
-	userCache := cache.NewUserCache(cmdable)
+	userCache := ioc.InitUserCache(cmdable)
 	userRepository := repository.NewUserRepository(userEntity, userCache)
-	userService := service.NewUserService(userRepository)
-	codeCache := cache.NewCodeCache(cmdable)
+	hasher := password.InitPasswordHasher()
+	userService := service.NewUserService(userRepository, hasher)
+	codeCache := ioc.InitCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := sms.InitSmsService()
 	codeService := service.NewCodeService(codeRepository, smsService)
-	handler := ijwt.NewRedisHandler(cmdable)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	keyManager := ioc.InitKeyManager()
+	revocationCache := ioc.InitRevocationCache(cmdable)
+	roleEntity := entity.NewRoleEntity(db)
+	roleCache := ioc.InitRoleCache(cmdable)
+	roleRepository := repository.NewRoleRepository(roleEntity, roleCache)
+	roleService := service.NewRoleService(roleRepository)
+	handler := ioc.InitJWTHandler(cmdable, keyManager, revocationCache, roleService)
+	securityEventEntity := entity.NewSecurityEventEntity(db)
+	securityEventRepository := repository.NewSecurityEventRepository(securityEventEntity)
+	securityEventService := service.NewSecurityEventService(securityEventRepository)
+	emailService := email.InitEmailService()
+	emailCodeService := service.NewEmailCodeService(codeRepository, emailService)
+	emailVerifiedMiddlewareBuilder := ioc.InitEmailVerified(userService)
+	loginAttemptCache := ioc.InitLoginAttemptCache(cmdable)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(loginAttemptCache)
+	captchaService := captcha.InitCaptchaService()
+	loginGuardService := ioc.InitLoginGuardService(loginAttemptRepository, captchaService)
+	twoFactorEntity := entity.NewTwoFactorEntity(db)
+	twoFactorChallengeCache := ioc.InitTwoFactorChallengeCache(cmdable)
+	twoFactorRepository := repository.NewTwoFactorRepository(twoFactorEntity, twoFactorChallengeCache)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository, userRepository, hasher)
+	loginRecordEntity := entity.NewLoginRecordEntity(db)
+	loginRecordRepository := repository.NewLoginRecordRepository(loginRecordEntity)
+	loginHistoryService := ioc.InitLoginHistoryService(loginRecordRepository, userRepository)
+	userHandler := web.NewUserHandler(userService, codeService, emailCodeService, handler, securityEventService, emailVerifiedMiddlewareBuilder, loginGuardService, twoFactorService, loginHistoryService)
 	weChatService := oauth2.InitWeChatService()
-	oAuth2WeChatHandler := web.NewOAuth2WeChatHandler(weChatService)
-	v := ioc.InitMiddlewares(cmdable, handler)
-	engine := ioc.InitWebServer(userHandler, oAuth2WeChatHandler, v)
+	identityEntity := entity.NewIdentityEntity(db)
+	weChatBindCache := ioc.InitWeChatBindCache(cmdable)
+	identityRepository := repository.NewIdentityRepository(identityEntity, userCache, weChatBindCache)
+	identityService := service.NewIdentityService(identityRepository, userRepository)
+	oAuth2WeChatHandler := web.NewOAuth2WeChatHandler(weChatService, userService, identityService, handler, twoFactorService, loginHistoryService)
+	jwksHandler := web.NewJWKSHandler(keyManager)
+	sessionHandler := web.NewSessionHandler(handler)
+	rbacMiddlewareBuilder := ioc.InitRBAC(roleService)
+	roleHandler := web.NewRoleHandler(roleService, rbacMiddlewareBuilder)
+	personalTokenEntity := entity.NewPersonalTokenEntity(db)
+	personalTokenRepository := repository.NewPersonalTokenRepository(personalTokenEntity)
+	personalTokenService := service.NewPersonalTokenService(personalTokenRepository)
+	personalTokenHandler := web.NewPersonalTokenHandler(personalTokenService, emailVerifiedMiddlewareBuilder)
+	passwordHandler := web.NewPasswordHandler(userService, codeService, emailCodeService, handler, securityEventService, personalTokenService)
+	identityHandler := web.NewIdentityHandler(identityService, codeService)
+	followEntity := entity.NewFollowEntity(db)
+	followCache := ioc.InitFollowCache(cmdable)
+	followRepository := repository.NewFollowRepository(followEntity, followCache)
+	followService := service.NewFollowService(followRepository, userRepository)
+	v := ioc.InitUserMergedListeners(roleService, personalTokenService, followService, loginHistoryService)
+	accountMergeService := service.NewAccountMergeService(userRepository, securityEventService, v)
+	mergeHandler := web.NewMergeHandler(accountMergeService, userService, identityService, codeService, handler, loginGuardService, twoFactorService)
+	storageService := storage.InitStorageService()
+	avatarService := ioc.InitAvatarService(userRepository, storageService)
+	dataExportEntity := entity.NewDataExportEntity(db)
+	dataExportRepository := repository.NewDataExportRepository(dataExportEntity)
+	smsEntity := entity.NewSMSEntity(db)
+	smsRepository := repository.NewSmsRepository(smsEntity)
+	dataExportService := ioc.InitDataExportService(dataExportRepository, userRepository, identityRepository, securityEventRepository, smsRepository, personalTokenRepository, loginRecordRepository, followRepository)
+	smsRecordService := service.NewSmsRecordService(userRepository, identityRepository, smsRepository)
+	v2 := ioc.InitUserPurgedListeners(avatarService, followService, loginHistoryService, dataExportService, smsRecordService)
+	accountDeletionService := ioc.InitAccountDeletionService(userRepository, v2)
+	accountDeletionHandler := web.NewAccountDeletionHandler(accountDeletionService, handler, securityEventService)
+	dataExportHandler := web.NewDataExportHandler(dataExportService)
+	loginLockHandler := web.NewLoginLockHandler(loginGuardService, rbacMiddlewareBuilder)
+	twoFactorHandler := web.NewTwoFactorHandler(twoFactorService, handler, securityEventService, loginHistoryService, userService, loginGuardService)
+	avatarHandler := web.NewAvatarHandler(avatarService, storageService, emailVerifiedMiddlewareBuilder)
+	profileService := ioc.InitProfileService(userRepository)
+	profileHandler := web.NewProfileHandler(profileService, emailVerifiedMiddlewareBuilder)
+	followHandler := web.NewFollowHandler(followService)
+	auditLogEntity := entity.NewAuditLogEntity(db)
+	auditLogRepository := repository.NewAuditLogRepository(auditLogEntity)
+	adminUserService := service.NewAdminUserService(userRepository, twoFactorRepository, auditLogRepository, handler)
+	adminUserHandler := web.NewAdminUserHandler(adminUserService, rbacMiddlewareBuilder)
+	loginHistoryHandler := web.NewLoginHistoryHandler(loginHistoryService, rbacMiddlewareBuilder)
+	authRules := middleware.NewAuthRules()
+	userStatusCache := ioc.InitUserStatusCache()
+	userStatusRepository := repository.NewUserStatusRepository(userRepository, userStatusCache)
+	accountStatusService := service.NewAccountStatusService(userStatusRepository)
+	v3 := ioc.InitMiddlewares(cmdable, handler, personalTokenService, accountStatusService, authRules)
+	engine := ioc.InitWebServer(userHandler, oAuth2WeChatHandler, jwksHandler, sessionHandler, roleHandler, personalTokenHandler, passwordHandler, identityHandler, mergeHandler, accountDeletionHandler, dataExportHandler, loginLockHandler, twoFactorHandler, avatarHandler, profileHandler, followHandler, adminUserHandler, loginHistoryHandler, rbacMiddlewareBuilder, authRules, v3)
 	return engine
 }